@@ -11,7 +11,6 @@ package main
 
 import (
 	"context"
-	"encoding/csv"
 	"errors"
 	"flag"
 	"io"
@@ -19,6 +18,8 @@ import (
 	"os"
 	"strings"
 
+	"github.com/csaf-auxiliary/oasis-quorum-calculator/pkg/misc"
+	"github.com/csaf-auxiliary/oasis-quorum-calculator/pkg/models"
 	"github.com/jmoiron/sqlx"
 
 	_ "github.com/mattn/go-sqlite3" // Link SQLite 3 driver.
@@ -37,7 +38,7 @@ func sqlite3URL(url string) string {
 	return url
 }
 
-func run(committeesCSV, databaseURL string) error {
+func run(committeesCSV, databaseURL string, dryRun bool) error {
 	ctx := context.Background()
 	f, err := os.Open(committeesCSV)
 	if err != nil {
@@ -54,11 +55,17 @@ func run(committeesCSV, databaseURL string) error {
 
 	tx, err := db.BeginTx(ctx, nil)
 	if err != nil {
-		return nil
+		return err
 	}
 	defer tx.Rollback()
 
-	r := csv.NewReader(f)
+	r, err := misc.NewCSVReader(f)
+	if err != nil {
+		return err
+	}
+	// Short lines are reported and skipped below.
+	r.FieldsPerRecord = -1
+	var created, updated int
 next:
 	for lineNo := 1; ; lineNo++ {
 		record, err := r.Read()
@@ -72,32 +79,65 @@ next:
 			log.Printf("line %d has not enough columns\n", lineNo)
 			continue
 		}
-		user := record[0]
+		name := strings.TrimSpace(record[0])
+		if name == "" {
+			log.Printf("line %d has no committee name\n", lineNo)
+			continue
+		}
 		var desc *string
 		if s := strings.TrimSpace(record[1]); len(s) > 1 {
 			desc = &s
 		}
-		const insertSQL = `INSERT INTO committees (name, description) VALUES (?, ?)` +
-			`ON CONFLICT DO UPDATE SET description = ?`
-
-		if _, err := tx.ExecContext(ctx, insertSQL, user, desc, desc); err != nil {
+		const existsSQL = `SELECT EXISTS(SELECT 1 FROM committees WHERE name = ?)`
+		var exists bool
+		if err := tx.QueryRowContext(ctx, existsSQL, name).Scan(&exists); err != nil {
 			return err
 		}
+		// Names are not unique in the database so check explicitly.
+		if exists {
+			const updateSQL = `UPDATE committees SET description = ?, ` +
+				`updated_at = CURRENT_TIMESTAMP WHERE name = ?`
+			if _, err := tx.ExecContext(ctx, updateSQL, desc, name); err != nil {
+				return err
+			}
+			updated++
+		} else {
+			slug, err := models.UniqueCommitteeSlugTx(ctx, tx, name)
+			if err != nil {
+				return err
+			}
+			const insertSQL = `INSERT INTO committees (name, slug, description, created_at, updated_at) ` +
+				`VALUES (?, ?, ?, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)`
+			if _, err := tx.ExecContext(ctx, insertSQL, name, slug, desc); err != nil {
+				return err
+			}
+			created++
+		}
 	}
 
-	return tx.Commit()
+	if dryRun {
+		log.Printf("dry run: %d committees would be created, %d updated\n", created, updated)
+		return nil
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	log.Printf("%d committees created, %d updated\n", created, updated)
+	return nil
 }
 
 func main() {
 	var (
 		committeesCSV string
 		databaseURL   string
+		dryRun        bool
 	)
 	flag.StringVar(&committeesCSV, "committees", "committees.csv", "CSV file of the committees to be created.")
 	flag.StringVar(&committeesCSV, "c", "committees.csv", "CSV file of the committees to be created (shorthand).")
 	flag.StringVar(&databaseURL, "database", "oqcd.sqlite", "SQLite database")
 	flag.StringVar(&databaseURL, "d", "oqcd.sqlite", "SQLite database (shorthand)")
+	flag.BoolVar(&dryRun, "dry-run", false, "Only report what would be changed.")
 	flag.Parse()
 
-	check(run(committeesCSV, databaseURL))
+	check(run(committeesCSV, databaseURL, dryRun))
 }
@@ -0,0 +1,122 @@
+// This file is Free Software under the Apache-2.0 License
+// without warranty, see README.md and LICENSE for details.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileCopyrightText: 2025 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2025 Intevation GmbH <https://intevation.de>
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/csaf-auxiliary/oasis-quorum-calculator/pkg/config"
+	"github.com/csaf-auxiliary/oasis-quorum-calculator/pkg/database"
+)
+
+// committees returns the names, slugs and descriptions of the committees.
+func committees(t *testing.T, db *database.Database) []string {
+	t.Helper()
+	rows, err := db.DB.Query(`SELECT name, slug, coalesce(description, '') FROM committees ORDER BY name`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+	var result []string
+	for rows.Next() {
+		var name, slug, desc string
+		if err := rows.Scan(&name, &slug, &desc); err != nil {
+			t.Fatal(err)
+		}
+		result = append(result, name+"|"+slug+"|"+desc)
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatal(err)
+	}
+	return result
+}
+
+// runLogged runs the import and returns the log output.
+func runLogged(t *testing.T, csv, databaseURL string, dryRun bool) string {
+	t.Helper()
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	flags := log.Flags()
+	log.SetFlags(0)
+	defer func() {
+		log.SetOutput(os.Stderr)
+		log.SetFlags(flags)
+	}()
+	if err := run(csv, databaseURL, dryRun); err != nil {
+		t.Fatalf("import failed: %v", err)
+	}
+	return buf.String()
+}
+
+func TestRun(t *testing.T) {
+	dir := t.TempDir()
+	databaseURL := filepath.Join(dir, "oqcd.sqlite")
+	db, err := database.NewDatabase(context.Background(), &config.Database{
+		DatabaseURL:        databaseURL,
+		Driver:             "sqlite3",
+		Migrate:            true,
+		MaxOpenConnections: 1,
+		MaxIdleConnections: 1,
+	})
+	if err != nil {
+		t.Fatalf("creating database failed: %v", err)
+	}
+	defer db.DB.Close()
+	if _, err := db.DB.Exec(`INSERT INTO committees (name, slug) VALUES ('Existing TC', 'existing-tc')`); err != nil {
+		t.Fatal(err)
+	}
+	csv := filepath.Join(dir, "committees.csv")
+	if err := os.WriteFile(csv, []byte(
+		" New TC ;A new committee\n"+
+			"Existing TC;Now described\n"+
+			"  ;Nameless\n"+
+			"Incomplete\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	before := committees(t, db)
+
+	out := runLogged(t, csv, databaseURL, true)
+	for _, want := range []string{
+		"line 3 has no committee name",
+		"line 4 has not enough columns",
+		"dry run: 1 committees would be created, 1 updated",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("dry run: %q not logged:\n%s", want, out)
+		}
+	}
+	if got := committees(t, db); !slices.Equal(got, before) {
+		t.Errorf("dry run changed committees to %q", got)
+	}
+
+	out = runLogged(t, csv, databaseURL, false)
+	if want := "1 committees created, 1 updated"; !strings.Contains(out, want) {
+		t.Errorf("%q not logged:\n%s", want, out)
+	}
+	want := []string{
+		"Existing TC|existing-tc|Now described",
+		"New TC|new-tc|A new committee",
+	}
+	if got := committees(t, db); !slices.Equal(got, want) {
+		t.Errorf("got committees %q, want %q", got, want)
+	}
+
+	// A second import only updates.
+	out = runLogged(t, csv, databaseURL, false)
+	if want := "0 committees created, 2 updated"; !strings.Contains(out, want) {
+		t.Errorf("%q not logged:\n%s", want, out)
+	}
+}
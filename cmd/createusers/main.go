@@ -11,7 +11,6 @@ package main
 
 import (
 	"context"
-	"encoding/csv"
 	"errors"
 	"flag"
 	"fmt"
@@ -27,8 +26,8 @@ import (
 )
 
 // CSV layout
-// nickname,first name,last name,committee,chair,member,status
-// "anton","Anton","Amann","false","asaf","false","true","voting"
+// nickname,first name,last name,admin,committee,chair,member,status[,email]
+// "anton","Anton","Amann","false","asaf","false","true","voting","anton@example.org"
 
 func check(err error) {
 	if err != nil {
@@ -48,6 +47,7 @@ var memberStatus = map[string]int{
 	"voting":     1,
 	"nonevoting": 2,
 	"nomember":   3,
+	"observer":   4,
 }
 
 func run(usersCSV, passwordCSV, databaseURL string) error {
@@ -74,7 +74,10 @@ func run(usersCSV, passwordCSV, databaseURL string) error {
 	}
 	defer db.Close()
 
-	r := csv.NewReader(f)
+	r, err := misc.NewCSVReader(f)
+	if err != nil {
+		return closePWs(err)
+	}
 next:
 	for lineNo := 1; ; lineNo++ {
 		record, err := r.Read()
@@ -97,6 +100,7 @@ next:
 			chair     *bool
 			member    *bool
 			status    *int
+			email     *string
 		)
 		if record[5] != "" {
 			x := record[5] == "true"
@@ -114,9 +118,16 @@ next:
 			}
 			status = &st
 		}
+		if len(record) > 8 {
+			email = misc.NilString(strings.TrimSpace(record[8]))
+			if email != nil && !misc.ValidEmail(*email) {
+				log.Printf("line %d: email column (9) is invalid.\n", lineNo)
+				continue
+			}
+		}
 
 		var exists bool
-		const existsSQL = `SELECT EXISTS(SELECT 1 FROM users WHERE nickname = ?)`
+		const existsSQL = `SELECT EXISTS(SELECT 1 FROM users WHERE lower(nickname) = lower(?))`
 		if err := db.QueryRowContext(ctx, existsSQL, nickname).Scan(&exists); err != nil {
 			return closePWs(err)
 		}
@@ -126,6 +137,7 @@ next:
 				Nickname:  nickname,
 				Firstname: firstname,
 				Lastname:  lastname,
+				Email:     email,
 				IsAdmin:   admin,
 			}
 			password := misc.RandomString(12)
@@ -137,7 +149,7 @@ next:
 				log.Printf("line %d: adding user failed.\n", lineNo)
 				continue
 			}
-			fmt.Fprintf(passwords, "%q,%q\n", nickname, password)
+			fmt.Fprintf(passwords, "%q,%q,%q\n", nickname, password, misc.EmptyString(email))
 		}
 
 		// TODO: Implement me!
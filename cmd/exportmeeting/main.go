@@ -6,7 +6,7 @@
 // SPDX-FileCopyrightText: 2025 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
 // Software-Engineering: 2025 Intevation GmbH <https://intevation.de>
 
-// Package main implements a meeting export.
+// Package main implements a meeting export from SQLite databases.
 package main
 
 import (
@@ -57,13 +57,15 @@ func run(meetingCSV, committee, databaseURL string) error {
 
 	meetings := []meeting{}
 
+	// group_concat is SQLite specific as is the whole tool.
 	loadAttendeesSQL := `SELECT m.start_time, group_concat(nickname) FROM meetings m ` +
 		`LEFT JOIN attendees a ON m.id = a.meetings_id `
 
 	queryArgs := []any{}
 	if committee != "" {
-		loadAttendeesSQL += `WHERE m.committees_id = (SELECT id FROM committees WHERE name = ?) `
-		queryArgs = append(queryArgs, committee)
+		loadAttendeesSQL += `WHERE m.committees_id = ` +
+			`(SELECT id FROM committees WHERE name = ? OR slug = ? ORDER BY slug = ? DESC LIMIT 1) `
+		queryArgs = append(queryArgs, committee, committee, committee)
 	}
 	loadAttendeesSQL += `GROUP BY m.start_time ORDER BY m.start_time`
 	rows, err := db.QueryContext(ctx, loadAttendeesSQL, queryArgs...)
@@ -146,7 +148,7 @@ func main() {
 	)
 	flag.StringVar(&meetingCSV, "meeting", "meetings.csv", "CSV file of the meetings to be exported.")
 	flag.StringVar(&meetingCSV, "m", "meetings.csv", "CSV file of the meetings to be exported (shorthand).")
-	flag.StringVar(&committee, "committee", "", "Committee meetings that should be exported")
+	flag.StringVar(&committee, "committee", "", "Committee meetings that should be exported (name or short code)")
 	flag.StringVar(&databaseURL, "database", "oqcd.sqlite", "SQLite database")
 	flag.StringVar(&databaseURL, "d", "oqcd.sqlite", "SQLite database (shorthand)")
 	flag.Parse()
@@ -17,8 +17,8 @@ import (
 	"strings"
 	"time"
 
-	"encoding/csv"
 	"flag"
+	"io"
 	"log"
 	"os"
 
@@ -57,46 +57,132 @@ func fuzzyMatchUser(name string) func(*models.User) bool {
 	}
 }
 
-func extractMeetings(records [][]string) ([]*meeting, error) {
-	var meetings []*meeting
+// Keys to match the names in the CSV file against the users.
+const (
+	matchAuto  = "auto"
+	matchEmail = "email"
+	matchName  = "name"
+)
+
+func matchEmailUser(email string) func(*models.User) bool {
+	return func(user *models.User) bool {
+		return user.Email != nil && strings.EqualFold(*user.Email, email)
+	}
+}
 
-	// Transpose rows to columns
-	numCols := len(records[0])
-	columns := make([][]string, numCols)
-	for i := range numCols {
-		for _, row := range records {
-			if i < len(row) {
-				columns[i] = append(columns[i], row[i])
+// resolver returns a function which resolves the names in the CSV file
+// to the nicknames of the users by the given match key.
+// With matchAuto the emails are matched exactly first, followed by
+// the nicknames and a fuzzy match of the first and last names.
+func resolver(users []*models.User, match string) func(string) (string, bool) {
+	find := func(cond func(*models.User) bool) (string, bool) {
+		if idx := slices.IndexFunc(users, cond); idx >= 0 {
+			return users[idx].Nickname, true
+		}
+		return "", false
+	}
+	byName := func(name string) (string, bool) {
+		// Check if username exists
+		if nickname, ok := find(func(u *models.User) bool {
+			return u.Nickname == name
+		}); ok {
+			return nickname, true
+		}
+		// Username not found trying firstname and lastname
+		return find(fuzzyMatchUser(name))
+	}
+	byEmail := func(name string) (string, bool) {
+		return find(matchEmailUser(name))
+	}
+	switch match {
+	case matchEmail:
+		return byEmail
+	case matchName:
+		return byName
+	default:
+		return func(name string) (string, bool) {
+			if nickname, ok := byEmail(name); ok {
+				return nickname, true
 			}
+			return byName(name)
 		}
 	}
+}
+
+// csvError is a problem found at a given position in the CSV file.
+type csvError struct {
+	line   int
+	column int
+	err    error
+}
+
+func (ce *csvError) Error() string {
+	if ce.column > 0 {
+		return fmt.Sprintf("line %d, column %d: %v", ce.line, ce.column, ce.err)
+	}
+	return fmt.Sprintf("line %d: %v", ce.line, ce.err)
+}
+
+func (ce *csvError) Unwrap() error {
+	return ce.err
+}
+
+// table are the records of the CSV file together with
+// the line numbers they start at.
+type table struct {
+	records [][]string
+	lines   []int
+}
+
+// errorf creates an error for a given cell of the table.
+// Rows and columns are zero based, column -1 refers to the whole row.
+func (t *table) errorf(row, column int, format string, args ...any) error {
+	return &csvError{
+		line:   t.lines[row],
+		column: column + 1,
+		err:    fmt.Errorf(format, args...),
+	}
+}
+
+func extractMeetings(t *table) ([]*meeting, error) {
+	var (
+		meetings []*meeting
+		errs     []error
+	)
 
 	// Meeting columns start after the initial user status list
-	if len(columns) <= 3 {
-		return nil, errors.New("not enough columns")
+	header := t.records[0]
+	if len(header) <= 3 {
+		return nil, t.errorf(0, -1, "not enough columns")
 	}
-	columns = columns[3:]
 
-	for _, m := range columns {
-		if len(m) < 1 || m[0] == "" {
+	for col := 3; col < len(header); col++ {
+		date := strings.TrimSpace(header[col])
+		if date == "" {
 			continue
 		}
-		t, err := time.Parse("2006-01-02", m[0])
+		startTime, err := time.Parse("2006-01-02", date)
 		if err != nil {
-			return nil, err
+			errs = append(errs, t.errorf(0, col, "invalid meeting date %q", date))
+			continue
 		}
 
 		attendees := []string{}
-		for _, a := range m[1:] {
-			if a != "" {
-				attendees = append(attendees, a)
+		for _, row := range t.records[1:] {
+			if col < len(row) {
+				if a := strings.TrimSpace(row[col]); a != "" {
+					attendees = append(attendees, a)
+				}
 			}
 		}
 		meetings = append(meetings, &meeting{
-			startTime: t,
+			startTime: startTime,
 			attendees: attendees,
 		})
 	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
 
 	// Meetings need to be sorted in ascending order
 	slices.SortFunc(meetings, func(a, b *meeting) int {
@@ -105,16 +191,21 @@ func extractMeetings(records [][]string) ([]*meeting, error) {
 	return meetings, nil
 }
 
-func extractUsers(records [][]string) ([]*user, error) {
-	var users []*user
+func extractUsers(t *table) ([]*user, error) {
+	var (
+		users []*user
+		errs  []error
+	)
 
-	if len(records) < 2 {
+	if len(t.records) < 2 {
 		return nil, errors.New("no users")
 	}
 
-	for _, row := range records[1:] {
+	for i, row := range t.records[1:] {
+		rowNo := i + 1
 		if len(row) < 3 {
-			return nil, errors.New("not enough user infos")
+			errs = append(errs, t.errorf(rowNo, -1, "not enough user infos"))
+			continue
 		}
 		status, role, name := row[0], row[1], row[2]
 		status = strings.TrimSpace(status)
@@ -125,14 +216,11 @@ func extractUsers(records [][]string) ([]*user, error) {
 			continue
 		}
 		// Parse status
-		var initialStatus models.MemberStatus
-		switch strings.ToLower(status) {
-		case "voter":
-			initialStatus = models.Voting
-		case "non-voter":
-			initialStatus = models.NoneVoting
-		default:
-			return nil, fmt.Errorf("unknown status %q for user %q", status, name)
+		valid := true
+		initialStatus, err := models.ParseMemberStatus(status)
+		if err != nil {
+			errs = append(errs, t.errorf(rowNo, 0, "unknown status %q for user %q", status, name))
+			valid = false
 		}
 		// Parse role
 		var initialRole models.Role
@@ -147,41 +235,78 @@ func extractUsers(records [][]string) ([]*user, error) {
 		case "secretary":
 			initialRole = models.SecretaryRole
 		default:
-			return nil, fmt.Errorf("unknown role %q for user %q", role, name)
+			errs = append(errs, t.errorf(rowNo, 1, "unknown role %q for user %q", role, name))
+			valid = false
 		}
-		users = append(users, &user{
-			name:          name,
-			initialStatus: initialStatus,
-			initialRole:   initialRole,
-		})
+		if valid {
+			users = append(users, &user{
+				name:          name,
+				initialStatus: initialStatus,
+				initialRole:   initialRole,
+			})
+		}
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
 	}
 
 	return users, nil
 }
 
-func loadCSV(filename string) (*data, error) {
-
+// readTable reads all records of the CSV file
+// and remembers the lines they start at.
+func readTable(filename string) (*table, error) {
 	f, err := os.Open(filename)
 	if err != nil {
 		return nil, err
 	}
 	defer f.Close()
 
-	r := csv.NewReader(f)
-
-	records, err := r.ReadAll()
+	r, err := misc.NewCSVReader(f)
 	if err != nil {
 		return nil, err
 	}
+	// Missing columns are reported by the validation.
+	r.FieldsPerRecord = -1
+
+	var t table
+	for {
+		record, err := r.Read()
+		switch {
+		case errors.Is(err, io.EOF):
+			if len(t.records) == 0 {
+				return nil, errors.New("empty CSV file")
+			}
+			return &t, nil
+		case err != nil:
+			return nil, err
+		}
+		line, _ := r.FieldPos(0)
+		t.records = append(t.records, record)
+		t.lines = append(t.lines, line)
+	}
+}
 
-	users, err := extractUsers(records)
+// loadCSV loads the users and meetings from the CSV file.
+// All problems found in the file are reported together.
+func loadCSV(filename string) (*data, error) {
+	t, err := readTable(filename)
 	if err != nil {
-		return nil, fmt.Errorf("extracting users failed: %w", err)
+		return nil, err
 	}
 
-	meetings, err := extractMeetings(records)
-	if err != nil {
-		return nil, fmt.Errorf("extracting meetings failed: %w", err)
+	users, errUsers := extractUsers(t)
+	meetings, errMeetings := extractMeetings(t)
+
+	if errUsers != nil || errMeetings != nil {
+		var errs []error
+		if errUsers != nil {
+			errs = append(errs, fmt.Errorf("extracting users failed:\n%w", errUsers))
+		}
+		if errMeetings != nil {
+			errs = append(errs, fmt.Errorf("extracting meetings failed:\n%w", errMeetings))
+		}
+		return nil, errors.Join(errs...)
 	}
 
 	return &data{
@@ -190,13 +315,15 @@ func loadCSV(filename string) (*data, error) {
 	}, nil
 }
 
-func run(committee, csv, databaseURL string) error {
+func run(committee, csv, databaseURL, match string) error {
 	ctx := context.Background()
 
 	table, err := loadCSV(csv)
 	if err != nil {
-		return fmt.Errorf("loading CSV failed: %w", err)
+		return fmt.Errorf("loading CSV failed:\n%w", err)
 	}
+	log.Printf("found %d users and %d meetings in %q\n",
+		len(table.users), len(table.meetings), csv)
 
 	db, err := database.NewDatabase(ctx, &config.Database{
 		Driver:      "sqlite3",
@@ -213,7 +340,7 @@ func run(committee, csv, databaseURL string) error {
 
 	var committeeModel *models.Committee
 	for _, c := range committees {
-		if c.Name == committee {
+		if c.Name == committee || c.Slug == committee {
 			committeeModel = c
 		}
 	}
@@ -222,41 +349,28 @@ func run(committee, csv, databaseURL string) error {
 	}
 
 	// Load and check if the username is correct and try to guess the username
-	// based on firstname and lastname if the specified name does not exist
+	// based on email, firstname and lastname if the specified name does not exist
 	users, err := models.LoadAllUsers(ctx, db)
 	if err != nil {
 		return fmt.Errorf("loading users failed: %w", err)
 	}
+	resolve := resolver(users, match)
 
 	for _, user := range table.users {
-		// Check if username exists
-		idx := slices.IndexFunc(users, func(u *models.User) bool {
-			return u.Nickname == user.name
-		})
-		// Username not found trying firstname and lastname
-		if idx < 0 {
-			if idx = slices.IndexFunc(users, fuzzyMatchUser(user.name)); idx < 0 {
-				return fmt.Errorf("no nickname found for user %q", user.name)
-			}
-			// Set username if a good match was found
-			user.name = users[idx].Nickname
+		nickname, ok := resolve(user.name)
+		if !ok {
+			return fmt.Errorf("no nickname found for user %q", user.name)
 		}
+		user.name = nickname
 	}
 
 	for _, m := range table.meetings {
 		for attendeeIdx, attendee := range m.attendees {
-			// Check if username exists
-			idx := slices.IndexFunc(users, func(u *models.User) bool {
-				return u.Nickname == attendee
-			})
-			// Username not found trying firstname and lastname
-			if idx < 0 {
-				if idx = slices.IndexFunc(users, fuzzyMatchUser(attendee)); idx < 0 {
-					return fmt.Errorf("no nickname found for attendee %q", attendee)
-				}
-				// Set username if a good match was found
-				m.attendees[attendeeIdx] = users[idx].Nickname
+			nickname, ok := resolve(attendee)
+			if !ok {
+				return fmt.Errorf("no nickname found for attendee %q", attendee)
 			}
+			m.attendees[attendeeIdx] = nickname
 		}
 	}
 
@@ -271,8 +385,9 @@ func run(committee, csv, databaseURL string) error {
 		}
 	}
 
+	meetings := make([]*models.Meeting, 0, len(table.meetings))
 	for _, m := range table.meetings {
-		meeting := models.Meeting{
+		meeting := &models.Meeting{
 			CommitteeID: committeeModel.ID,
 			Gathering:   false,
 			StartTime:   m.startTime,
@@ -283,13 +398,26 @@ func run(committee, csv, databaseURL string) error {
 		if err = meeting.StoreNew(ctx, db); err != nil {
 			return err
 		}
+		meetings = append(meetings, meeting)
+	}
+
+	// The memberships are stored with the current time.
+	// Let them start with the first meeting.
+	if err := models.EnsureInitialHistory(ctx, db, committeeModel.ID); err != nil {
+		return err
+	}
 
-		misc.Attribute(misc.Values(m.attendees), true)
+	for i, m := range table.meetings {
+		meeting := meetings[i]
 
 		if err = models.Attend(ctx, db, meeting.ID, misc.Attribute(misc.Values(m.attendees...), true), meeting.StartTime); err != nil {
 			return err
 		}
 
+		// Only running meetings can be concluded.
+		if err = models.ChangeMeetingStatus(ctx, db, meeting.ID, committeeModel.ID, models.MeetingRunning, meeting.StartTime); err != nil {
+			return err
+		}
 		if err = models.ChangeMeetingStatus(ctx, db, meeting.ID, committeeModel.ID, models.MeetingConcluded, meeting.StopTime); err != nil {
 			return err
 		}
@@ -309,9 +437,11 @@ func main() {
 		committee   string
 		databaseURL string
 		csvFile     string
+		match       string
 	)
-	flag.StringVar(&committee, "committee", "", "Committee to be imported")
+	flag.StringVar(&committee, "committee", "", "Committee to be imported (name or short code)")
 	flag.StringVar(&csvFile, "csv", "committee.csv", "CSV with a committee time table to import")
+	flag.StringVar(&match, "match", matchAuto, "Key to match the names in the CSV with the users (auto, email or name)")
 	flag.StringVar(&databaseURL, "database", "oqcd.sqlite", "SQLite database")
 	flag.StringVar(&databaseURL, "d", "oqcd.sqlite", "SQLite database (shorthand)")
 	flag.Parse()
@@ -321,5 +451,8 @@ func main() {
 	if csvFile == "" {
 		log.Fatalln("missing CSV filename")
 	}
-	check(run(committee, csvFile, databaseURL))
+	if !slices.Contains([]string{matchAuto, matchEmail, matchName}, match) {
+		log.Fatalf("invalid match key %q\n", match)
+	}
+	check(run(committee, csvFile, databaseURL, match))
 }
@@ -0,0 +1,180 @@
+// This file is Free Software under the Apache-2.0 License
+// without warranty, see README.md and LICENSE for details.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileCopyrightText: 2025 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2025 Intevation GmbH <https://intevation.de>
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/csaf-auxiliary/oasis-quorum-calculator/pkg/models"
+)
+
+// writeCSV writes the content to a CSV file in a temporary directory.
+func writeCSV(t *testing.T, content string) string {
+	t.Helper()
+	file := filepath.Join(t.TempDir(), "committee.csv")
+	if err := os.WriteFile(file, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return file
+}
+
+func TestLoadCSVFormats(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		content string
+	}{
+		{"comma", "Status,Role,Name,2025-01-08,2025-01-01\n" +
+			"Voting,Chair,Jane Doe,Jane Doe,\n" +
+			"Voting,Voting Member,John Roe,John Roe,John Roe\n"},
+		{"semicolon", "Status;Role;Name;2025-01-08;2025-01-01\n" +
+			"Voting;Chair;Jane Doe;Jane Doe;\n" +
+			"Voting;Voting Member;John Roe;John Roe;John Roe\n"},
+		{"bom", "\ufeffStatus,Role,Name,2025-01-08,2025-01-01\r\n" +
+			"Voting,Chair,Jane Doe,Jane Doe,\r\n" +
+			"Voting,Voting Member,John Roe,John Roe,John Roe\r\n"},
+		{"bom semicolon", "\ufeffStatus;Role;Name;2025-01-08;2025-01-01\r\n" +
+			"Voting;Chair;Jane Doe;Jane Doe;\r\n" +
+			"Voting;Voting Member;John Roe;John Roe;John Roe\r\n"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			d, err := loadCSV(writeCSV(t, tc.content))
+			if err != nil {
+				t.Fatalf("loading failed: %v", err)
+			}
+			wantUsers := []user{
+				{"Jane Doe", models.ChairRole, models.Voting},
+				{"John Roe", models.MemberRole, models.Voting},
+			}
+			if !slices.EqualFunc(d.users, wantUsers, func(a *user, b user) bool {
+				return *a == b
+			}) {
+				t.Errorf("got users %+v, want %+v", d.users, wantUsers)
+			}
+			wantMeetings := []meeting{
+				{time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), []string{"John Roe"}},
+				{time.Date(2025, 1, 8, 0, 0, 0, 0, time.UTC), []string{"Jane Doe", "John Roe"}},
+			}
+			if !slices.EqualFunc(d.meetings, wantMeetings, func(a *meeting, b meeting) bool {
+				return a.startTime.Equal(b.startTime) && slices.Equal(a.attendees, b.attendees)
+			}) {
+				t.Errorf("got meetings %+v, want %+v", d.meetings, wantMeetings)
+			}
+		})
+	}
+}
+
+func TestLoadCSVErrors(t *testing.T) {
+	d, err := loadCSV(writeCSV(t, "Status,Role,Name,2025-01-01,01/08/2025,2025-02-30\n"+
+		"Voting,Chair,Jane Doe,x,,\n"+
+		"Sometimes,Voting Member,John Roe,x,,\n"+
+		"Voting,Janitor,Max Mustermann\n"+
+		"Voting\n"+
+		",,\n"+
+		"\"Observer\nreally\",Boss,Erika Mustermann\n"))
+	if err == nil {
+		t.Fatalf("invalid file accepted: %+v", d)
+	}
+	// All problems are reported with their positions.
+	var positions []string
+	for _, e := range unwrapAll(err) {
+		if ce := (*csvError)(nil); errors.As(e, &ce) {
+			positions = append(positions, fmt.Sprintf("%d:%d", ce.line, ce.column))
+		}
+	}
+	// Line and column, zero columns refer to the whole line.
+	want := []string{"3:1", "4:2", "5:0", "7:1", "7:2", "1:5", "1:6"}
+	if !slices.Equal(positions, want) {
+		t.Errorf("got errors at %q, want %q\n%v", positions, want, err)
+	}
+}
+
+// unwrapAll returns the leaves of a tree of joined errors.
+func unwrapAll(err error) []error {
+	var errs []error
+	switch e := err.(type) {
+	case interface{ Unwrap() []error }:
+		for _, inner := range e.Unwrap() {
+			errs = append(errs, unwrapAll(inner)...)
+		}
+	case interface{ Unwrap() error }:
+		if _, ok := err.(*csvError); ok {
+			return []error{err}
+		}
+		return unwrapAll(e.Unwrap())
+	default:
+		errs = append(errs, err)
+	}
+	return errs
+}
+
+func TestLoadCSVNotEnoughColumns(t *testing.T) {
+	_, err := loadCSV(writeCSV(t, "Status,Role,Name\nVoting,Chair,Jane Doe\n"))
+	var ce *csvError
+	if !errors.As(err, &ce) || ce.line != 1 {
+		t.Errorf("got error %v, want one in line 1", err)
+	}
+}
+
+func TestResolver(t *testing.T) {
+	ptr := func(s string) *string { return &s }
+	users := []*models.User{
+		{Nickname: "jdoe", Firstname: ptr("Jane"), Lastname: ptr("Doe"), Email: ptr("jane@example.com")},
+		// Fuzzy matches "Jane Doe" too, emails disambiguate.
+		{Nickname: "jdoe2", Firstname: ptr("Jane"), Lastname: ptr("Doe"), Email: ptr("jane.doe@example.org")},
+		{Nickname: "jroe", Firstname: ptr("John"), Lastname: ptr("Roe")},
+	}
+	for _, tc := range []struct {
+		match, name string
+		want        string
+	}{
+		{matchAuto, "jane.doe@example.org", "jdoe2"},
+		{matchAuto, "JANE@example.com", "jdoe"},
+		{matchAuto, "Jane Doe", "jdoe"},
+		{matchAuto, "jroe", "jroe"},
+		{matchAuto, "John Roe", "jroe"},
+		{matchAuto, "nobody@example.com", ""},
+		{matchEmail, "jane.doe@example.org", "jdoe2"},
+		{matchEmail, "John Roe", ""},
+		{matchEmail, "jroe", ""},
+		// The fuzzy name match picks the first Jane Doe.
+		{matchName, "jane.doe@example.org", "jdoe"},
+		{matchName, "John Roe", "jroe"},
+	} {
+		nickname, ok := resolver(users, tc.match)(tc.name)
+		if nickname != tc.want || ok != (tc.want != "") {
+			t.Errorf("%s %q: got %q, %t, want %q", tc.match, tc.name, nickname, ok, tc.want)
+		}
+	}
+}
+
+func TestLoadCSVEmails(t *testing.T) {
+	d, err := loadCSV(writeCSV(t, "Status,Role,Name,2025-01-01\n"+
+		"Voting,Chair,Jane Doe,jane.doe@example.org\n"+
+		"Voting,Voting Member,John Roe,\n"))
+	if err != nil {
+		t.Fatalf("loading failed: %v", err)
+	}
+	ptr := func(s string) *string { return &s }
+	resolve := resolver([]*models.User{
+		{Nickname: "jdoe", Firstname: ptr("Jane"), Lastname: ptr("Doe"), Email: ptr("jane@example.com")},
+		{Nickname: "jdoe2", Firstname: ptr("Jane"), Lastname: ptr("Doe"), Email: ptr("jane.doe@example.org")},
+	}, matchEmail)
+	if len(d.meetings) != 1 || len(d.meetings[0].attendees) != 1 {
+		t.Fatalf("got meetings %+v", d.meetings)
+	}
+	if nickname, _ := resolve(d.meetings[0].attendees[0]); nickname != "jdoe2" {
+		t.Errorf("attendee resolved to %q, want %q", nickname, "jdoe2")
+	}
+}
@@ -0,0 +1,374 @@
+// This file is Free Software under the Apache-2.0 License
+// without warranty, see README.md and LICENSE for details.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileCopyrightText: 2025 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2025 Intevation GmbH <https://intevation.de>
+
+// Package main implements the export and import of committee memberships.
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/csaf-auxiliary/oasis-quorum-calculator/pkg/config"
+	"github.com/csaf-auxiliary/oasis-quorum-calculator/pkg/database"
+	"github.com/csaf-auxiliary/oasis-quorum-calculator/pkg/misc"
+	"github.com/csaf-auxiliary/oasis-quorum-calculator/pkg/models"
+)
+
+// header is the first row of the CSV file.
+var header = []string{"nickname", "committee", "roles", "status", "since"}
+
+// rolesSeparator separates the roles of a member in the roles column.
+const rolesSeparator = ";"
+
+// membership is the state of a user in a committee.
+type membership struct {
+	roles   []models.Role
+	history models.UserHistory
+}
+
+func check(err error) {
+	if err != nil {
+		log.Fatalf("error: %v\n", err)
+	}
+}
+
+func openDatabase(ctx context.Context, databaseURL string) (*database.Database, error) {
+	return database.NewDatabase(ctx, &config.Database{
+		Driver:      "sqlite3",
+		DatabaseURL: databaseURL,
+	})
+}
+
+func findCommittee(committees []*models.Committee, committee string) *models.Committee {
+	idx := slices.IndexFunc(committees, func(c *models.Committee) bool {
+		return c.Slug == committee || c.Name == committee
+	})
+	if idx < 0 {
+		return nil
+	}
+	return committees[idx]
+}
+
+func formatRoles(roles []models.Role) string {
+	names := make([]string, len(roles))
+	for i, role := range roles {
+		names[i] = role.String()
+	}
+	return strings.Join(names, rolesSeparator)
+}
+
+func parseRoles(s string) ([]models.Role, error) {
+	if s == "" {
+		return nil, nil
+	}
+	var roles []models.Role
+	for name := range strings.SplitSeq(s, rolesSeparator) {
+		role, err := models.ParseRole(strings.TrimSpace(name))
+		if err != nil {
+			return nil, err
+		}
+		if !slices.Contains(roles, role) {
+			roles = append(roles, role)
+		}
+	}
+	return roles, nil
+}
+
+func exportMemberships(csvFile, committee, databaseURL string) error {
+	ctx := context.Background()
+
+	db, err := openDatabase(ctx, databaseURL)
+	if err != nil {
+		return err
+	}
+	defer db.Close(ctx)
+
+	committees, err := models.LoadCommitteesFiltered(ctx, db, "", true)
+	if err != nil {
+		return err
+	}
+	if committee != "" {
+		c := findCommittee(committees, committee)
+		if c == nil {
+			return fmt.Errorf("committee %q not found", committee)
+		}
+		committees = []*models.Committee{c}
+	}
+
+	rows := [][]string{header}
+	for _, c := range committees {
+		users, err := models.LoadCommitteeUsers(ctx, db, c.ID, nil)
+		if err != nil {
+			return err
+		}
+		histories, err := models.LoadUsersHistories(ctx, db, c.ID)
+		if err != nil {
+			return err
+		}
+		members := map[string]*membership{}
+		for _, user := range users {
+			if ms := user.FindMembershipCriterion(models.MembershipByID(c.ID)); ms != nil {
+				members[user.Nickname] = &membership{roles: ms.Roles}
+			}
+		}
+		// Former members only have a history.
+		for nickname, history := range histories {
+			m := members[nickname]
+			if m == nil {
+				m = new(membership)
+				members[nickname] = m
+			}
+			m.history = history
+		}
+		for _, nickname := range slices.Sorted(maps.Keys(members)) {
+			m := members[nickname]
+			roles := formatRoles(m.roles)
+			if len(m.history) == 0 {
+				rows = append(rows, []string{nickname, c.Slug, roles, "", ""})
+				continue
+			}
+			for _, entry := range m.history {
+				rows = append(rows, []string{
+					nickname,
+					c.Slug,
+					roles,
+					entry.Status.String(),
+					entry.Since.UTC().Format(time.RFC3339Nano),
+				})
+			}
+		}
+	}
+
+	file, err := os.Create(csvFile)
+	if err != nil {
+		return err
+	}
+	writer := csv.NewWriter(file)
+	err = writer.WriteAll(rows)
+	return errors.Join(err, file.Close())
+}
+
+// readMemberships reads the memberships from a CSV file.
+// The result maps nicknames to committee slugs to memberships.
+func readMemberships(csvFile string) (map[string]map[string]*membership, error) {
+	file, err := os.Open(csvFile)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = len(header)
+	first, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("reading header failed: %w", err)
+	}
+	if !slices.Equal(first, header) {
+		return nil, fmt.Errorf("unexpected header %q", first)
+	}
+	var (
+		result = map[string]map[string]*membership{}
+		errs   []error
+	)
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		line, _ := reader.FieldPos(0)
+		nickname, committee := record[0], record[1]
+		if nickname == "" || committee == "" {
+			errs = append(errs, fmt.Errorf("line %d: missing nickname or committee", line))
+			continue
+		}
+		roles, err := parseRoles(record[2])
+		if err != nil {
+			errs = append(errs, fmt.Errorf("line %d: %w", line, err))
+			continue
+		}
+		committees := result[nickname]
+		if committees == nil {
+			committees = map[string]*membership{}
+			result[nickname] = committees
+		}
+		m := committees[committee]
+		if m == nil {
+			m = &membership{roles: roles}
+			committees[committee] = m
+		} else if !slices.Equal(m.roles, roles) {
+			errs = append(errs, fmt.Errorf("line %d: roles differ from previous lines", line))
+			continue
+		}
+		if record[3] == "" && record[4] == "" {
+			continue
+		}
+		status, err := models.ParseMemberStatus(record[3])
+		if err != nil {
+			errs = append(errs, fmt.Errorf("line %d: %w", line, err))
+			continue
+		}
+		since, err := time.Parse(time.RFC3339Nano, record[4])
+		if err != nil {
+			errs = append(errs, fmt.Errorf("line %d: invalid since %q", line, record[4]))
+			continue
+		}
+		m.history = append(m.history, &models.UserHistoryEntry{
+			Since:  since.UTC(),
+			Status: status,
+		})
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	for _, committees := range result {
+		for _, m := range committees {
+			slices.SortStableFunc(m.history, func(a, b *models.UserHistoryEntry) int {
+				return a.Since.Compare(b.Since)
+			})
+		}
+	}
+	return result, nil
+}
+
+func importMemberships(csvFile, databaseURL string) error {
+	ctx := context.Background()
+
+	memberships, err := readMemberships(csvFile)
+	if err != nil {
+		return fmt.Errorf("loading CSV failed:\n%w", err)
+	}
+
+	db, err := openDatabase(ctx, databaseURL)
+	if err != nil {
+		return err
+	}
+	defer db.Close(ctx)
+
+	committees, err := models.LoadCommitteesFiltered(ctx, db, "", true)
+	if err != nil {
+		return err
+	}
+
+	for _, nickname := range slices.Sorted(maps.Keys(memberships)) {
+		user, err := models.LoadUser(ctx, db, nickname, nil)
+		if err != nil {
+			return err
+		}
+		if user == nil {
+			return fmt.Errorf("user %q not found", nickname)
+		}
+		var (
+			updated  []*models.Membership
+			imported []int64
+		)
+		for slug, m := range memberships[nickname] {
+			committee := findCommittee(committees, slug)
+			if committee == nil {
+				return fmt.Errorf("committee %q not found", slug)
+			}
+			imported = append(imported, committee.ID)
+			if err := storeHistory(ctx, db, nickname, committee.ID, m.history); err != nil {
+				return err
+			}
+			if len(m.roles) == 0 {
+				continue
+			}
+			status := models.Member
+			if n := len(m.history); n > 0 {
+				status = m.history[n-1].Status
+			}
+			updated = append(updated, &models.Membership{
+				Committee: committee,
+				Status:    status,
+				Roles:     m.roles,
+			})
+		}
+		// Keep the memberships in committees not mentioned in the CSV.
+		for _, ms := range user.Memberships {
+			if !slices.Contains(imported, ms.Committee.ID) {
+				updated = append(updated, ms)
+			}
+		}
+		// The histories are stored before so that no new status is recorded.
+		if err := models.UpdateMemberships(ctx, db, nickname, slices.Values(updated)); err != nil {
+			return fmt.Errorf("updating memberships of %q failed: %w", nickname, err)
+		}
+	}
+	return nil
+}
+
+// storeHistory appends the entries of a history to the status
+// history of a user in a committee. Entries which are not newer
+// than the already existing history are skipped.
+func storeHistory(
+	ctx context.Context,
+	db *database.Database,
+	nickname string,
+	committeeID int64,
+	history models.UserHistory,
+) error {
+	tx, err := db.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	histories, err := models.LoadUsersHistoriesTx(ctx, tx, committeeID)
+	if err != nil {
+		return err
+	}
+	var last time.Time
+	if existing := histories[nickname]; len(existing) > 0 {
+		last = existing[len(existing)-1].Since
+	}
+	for _, entry := range history {
+		if !entry.Since.After(last) {
+			continue
+		}
+		if err := models.UpdateUserCommitteeStatusTx(
+			ctx, tx,
+			misc.Attribute(misc.Values(nickname), entry.Status),
+			committeeID,
+			entry.Since,
+		); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func main() {
+	var (
+		csvFile     string
+		committee   string
+		databaseURL string
+		doImport    bool
+	)
+	flag.StringVar(&csvFile, "csv", "memberships.csv", "CSV file of the memberships")
+	flag.StringVar(&committee, "committee", "", "Committee whose memberships should be exported (name or short code)")
+	flag.BoolVar(&doImport, "import", false, "Import the memberships from the CSV file instead of exporting them")
+	flag.StringVar(&databaseURL, "database", "oqcd.sqlite", "SQLite database")
+	flag.StringVar(&databaseURL, "d", "oqcd.sqlite", "SQLite database (shorthand)")
+	flag.Parse()
+	if doImport {
+		check(importMemberships(csvFile, databaseURL))
+	} else {
+		check(exportMemberships(csvFile, committee, databaseURL))
+	}
+}
@@ -0,0 +1,131 @@
+// This file is Free Software under the Apache-2.0 License
+// without warranty, see README.md and LICENSE for details.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileCopyrightText: 2025 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2025 Intevation GmbH <https://intevation.de>
+
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/csaf-auxiliary/oasis-quorum-calculator/pkg/config"
+	"github.com/csaf-auxiliary/oasis-quorum-calculator/pkg/database"
+)
+
+// newFixture creates a database with two committees and four users.
+// If members is true the users get memberships and histories.
+func newFixture(t *testing.T, members bool) string {
+	t.Helper()
+	ctx := context.Background()
+	databaseURL := filepath.Join(t.TempDir(), "oqcd.sqlite")
+	db, err := database.NewDatabase(ctx, &config.Database{
+		DatabaseURL:        databaseURL,
+		Driver:             "sqlite3",
+		Migrate:            true,
+		MaxOpenConnections: 1,
+		MaxIdleConnections: 1,
+	})
+	if err != nil {
+		t.Fatalf("creating database failed: %v", err)
+	}
+	defer db.DB.Close()
+	stmts := []string{
+		`INSERT INTO committees (id, name, slug) VALUES (1, 'Technical Committee', 'tc'), (2, 'Subcommittee', 'sc')`,
+		`INSERT INTO users (nickname, password) VALUES ('alice', 'x'), ('bob', 'x'), ('carol', 'x'), ('dave', 'x')`,
+	}
+	if members {
+		stmts = append(stmts,
+			`INSERT INTO committee_roles (nickname, committees_id, committee_role_id) VALUES `+
+				`('alice', 1, 0), ('alice', 1, 1), ('bob', 1, 1), ('bob', 2, 1), ('dave', 1, 2)`,
+			`INSERT INTO member_history (nickname, committees_id, status, since) VALUES `+
+				`('alice', 1, 1, '2024-01-01 10:00:00+00:00'), `+
+				`('alice', 1, 2, '2024-06-01 12:30:00.5+00:00'), `+
+				`('bob', 1, 0, '2024-02-01 10:00:00+00:00'), `+
+				`('bob', 2, 4, '2024-03-01 10:00:00+00:00'), `+
+				// carol is a former member.
+				`('carol', 1, 1, '2024-01-01 10:00:00+00:00'), `+
+				`('carol', 1, 3, '2024-12-01 10:00:00+00:00')`,
+		)
+	}
+	for _, stmt := range stmts {
+		if _, err := db.DB.ExecContext(ctx, stmt); err != nil {
+			t.Fatalf("fixture %q failed: %v", stmt, err)
+		}
+	}
+	return databaseURL
+}
+
+// export exports the memberships and returns the CSV.
+func export(t *testing.T, databaseURL string) string {
+	t.Helper()
+	csvFile := filepath.Join(t.TempDir(), "memberships.csv")
+	if err := exportMemberships(csvFile, "", databaseURL); err != nil {
+		t.Fatalf("exporting failed: %v", err)
+	}
+	data, err := os.ReadFile(csvFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestRoundTrip(t *testing.T) {
+	exported := export(t, newFixture(t, true))
+	for _, want := range []string{
+		"alice,tc,chair;member,voting,2024-01-01T10:00:00Z\n",
+		"alice,tc,chair;member,nonevoting,2024-06-01T12:30:00.5Z\n",
+		"bob,sc,member,observer,2024-03-01T10:00:00Z\n",
+		"carol,tc,,nomember,2024-12-01T10:00:00Z\n",
+		"dave,tc,secretary,,\n",
+	} {
+		if !strings.Contains(exported, want) {
+			t.Errorf("%q not exported:\n%s", want, exported)
+		}
+	}
+
+	csvFile := filepath.Join(t.TempDir(), "memberships.csv")
+	if err := os.WriteFile(csvFile, []byte(exported), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	fresh := newFixture(t, false)
+	for range 2 { // Importing again leaves the state unchanged.
+		if err := importMemberships(csvFile, fresh); err != nil {
+			t.Fatalf("importing failed: %v", err)
+		}
+		if reexported := export(t, fresh); reexported != exported {
+			t.Fatalf("re-export differs:\n%s\nwant:\n%s", reexported, exported)
+		}
+	}
+}
+
+func TestImportErrors(t *testing.T) {
+	csvFile := filepath.Join(t.TempDir(), "memberships.csv")
+	if err := os.WriteFile(csvFile, []byte(
+		"nickname,committee,roles,status,since\n"+
+			"alice,tc,janitor,voting,2024-01-01T10:00:00Z\n"+
+			"bob,tc,member,sometimes,2024-01-01T10:00:00Z\n"+
+			"bob,tc,member,voting,yesterday\n"+
+			",tc,member,,\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	databaseURL := newFixture(t, false)
+	err := importMemberships(csvFile, databaseURL)
+	if err == nil {
+		t.Fatal("invalid file imported")
+	}
+	for _, want := range []string{"line 2:", "line 3:", "line 4:", "line 5:"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("%q not reported:\n%v", want, err)
+		}
+	}
+	if exported := export(t, databaseURL); exported != strings.Join(header, ",")+"\n" {
+		t.Errorf("memberships imported:\n%s", exported)
+	}
+}
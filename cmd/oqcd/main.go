@@ -27,6 +27,8 @@ import (
 	"github.com/csaf-auxiliary/oasis-quorum-calculator/pkg/auth"
 	"github.com/csaf-auxiliary/oasis-quorum-calculator/pkg/config"
 	"github.com/csaf-auxiliary/oasis-quorum-calculator/pkg/database"
+	"github.com/csaf-auxiliary/oasis-quorum-calculator/pkg/mail"
+	"github.com/csaf-auxiliary/oasis-quorum-calculator/pkg/models"
 	"github.com/csaf-auxiliary/oasis-quorum-calculator/pkg/version"
 	"github.com/csaf-auxiliary/oasis-quorum-calculator/pkg/web"
 )
@@ -56,11 +58,28 @@ func run(cfg *config.Config) error {
 	cleaner := auth.NewCleaner(cfg, db)
 	go cleaner.Run(ctx)
 
+	maintainer := database.NewMaintainer(cfg.Database.MaintenanceInterval, db)
+	go maintainer.Run(ctx)
+
+	templates, err := mail.LoadTemplates(cfg.Mail.Templates)
+	if err != nil {
+		return err
+	}
+	reminder := mail.NewReminder(cfg, db, templates)
+	go reminder.Run(ctx)
+
 	ctrl, err := web.NewController(cfg, db)
 	if err != nil {
 		return err
 	}
 
+	if cfg.Meetings.AutoConclude {
+		concluder := models.NewConcluder(
+			cfg.Meetings.OverrunGrace, cfg.Meetings.OverrunCheckInterval, db,
+			ctrl.MeetingConcluded)
+		go concluder.Run(ctx)
+	}
+
 	addr := cfg.Web.Addr()
 	slog.Info("Starting web server", "address", addr)
 	srv := &http.Server{
@@ -112,15 +131,52 @@ func run(cfg *config.Config) error {
 	return err
 }
 
+// isFlagSet checks if a flag was given on the command line.
+func isFlagSet(name string) bool {
+	set := false
+	flag.Visit(func(f *flag.Flag) { set = set || f.Name == name })
+	return set
+}
+
+// reportMigrations reports the pending migrations of the database.
+// It exits with status 0 if there are none and 2 otherwise.
+func reportMigrations(cfg *config.Config) {
+	pending, err := database.PendingMigrations(context.Background(), &cfg.Database)
+	check(err)
+	if len(pending) == 0 {
+		fmt.Println("No pending migrations.")
+		os.Exit(0)
+	}
+	for _, mig := range pending {
+		fmt.Printf("Pending migration %03d: %s\n", mig.Version, mig.Description)
+	}
+	os.Exit(2)
+}
+
+// rollback reverts the migrations of the database down to a version.
+func rollback(cfg *config.Config, version int64) {
+	ctx := context.Background()
+	dbCfg := cfg.Database
+	dbCfg.Migrate = false
+	db, err := database.NewDatabase(ctx, &dbCfg)
+	check(err)
+	defer db.Close(ctx)
+	check(db.Rollback(ctx, version))
+}
+
 func main() {
 	var (
-		cfgFile     string
-		showVersion bool
+		cfgFile         string
+		showVersion     bool
+		checkMigrations bool
+		rollbackVersion int64
 	)
 	flag.StringVar(&cfgFile, "config", config.DefaultConfigFile, "configuration file")
 	flag.StringVar(&cfgFile, "c", config.DefaultConfigFile, "configuration file (shorthand)")
 	flag.BoolVar(&showVersion, "version", false, "show version")
 	flag.BoolVar(&showVersion, "V", false, "show version (shorthand)")
+	flag.BoolVar(&checkMigrations, "check-migrations", false, "report pending migrations and exit")
+	flag.Int64Var(&rollbackVersion, "rollback", -1, "roll back migrations to the given version and exit")
 	flag.Parse()
 	if showVersion {
 		fmt.Printf("%s version: %s\n", os.Args[0], version.SemVersion)
@@ -128,7 +184,15 @@ func main() {
 	}
 	cfg, err := config.Load(cfgFile)
 	check(err)
+	check(cfg.Validate())
 	check(cfg.Log.Config())
 	cfg.PresetDefaults()
+	if checkMigrations {
+		reportMigrations(cfg)
+	}
+	if isFlagSet("rollback") {
+		rollback(cfg, rollbackVersion)
+		return
+	}
 	check(run(cfg))
 }
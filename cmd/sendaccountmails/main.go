@@ -7,33 +7,23 @@
 // Software-Engineering: 2025 Intevation GmbH <https://intevation.de>
 
 // Package main implements sending out emails for new accounts
-// as being read from an CSV file in the format `emailaddress,password`.
-// where `emailaddress` is the username by convention.
+// as being read from an CSV file in the format `username,password[,email]`.
+// If the email address is missing the username is used as it is
+// an email address by convention.
 package main
 
 import (
 	"encoding/csv"
 	"flag"
 	"fmt"
-	"io"
 	"log"
-	"net/smtp"
+	netmail "net/mail"
 	"os"
-	"strings"
 	"text/template"
-)
-
-const templateTxt = `Dear OASIS {{.TCName}} TC member,
-
-an account was created for you at the OQC (https://quorum.oasis-open.org).
 
-username: {{.Recipient}}
-initial password: {{.Password}}
-
-Please change your initial password.
-
-Kind regards,
-Your OQC Tool`
+	"github.com/csaf-auxiliary/oasis-quorum-calculator/pkg/mail"
+	"github.com/csaf-auxiliary/oasis-quorum-calculator/pkg/misc"
+)
 
 func check(err error) {
 	if err != nil {
@@ -41,80 +31,43 @@ func check(err error) {
 	}
 }
 
-func send(host, sender, recipient string,
-	writeBody func(io.Writer) error,
-) error {
-	c, err := smtp.Dial(host)
-	if err != nil {
-		return err
-	}
-	defer c.Close()
-
-	// Set the sender and recipient first
-	if err := c.Mail(sender); err != nil {
-		return err
-	}
-	if err := c.Rcpt(recipient); err != nil {
-		return err
-	}
-
-	// Send the email body.
-	wc, err := c.Data()
-	if err != nil {
-		return err
-	}
-	if err := writeBody(wc); err != nil {
-		return err
-	}
-	if err = wc.Close(); err != nil {
-		return err
-	}
+// defaultBaseURL is the URL of the OQC linked in the emails.
+const defaultBaseURL = "https://quorum.oasis-open.org"
 
-	// Send the QUIT command and close the connection.
-	if err = c.Quit(); err != nil {
-		return err
-	}
-	return nil
+// options are the settings of the emails to be sent.
+type options struct {
+	tcName   string
+	smtpHost string
+	from     string
+	baseURL  string
 }
 
 func sendMail(
 	tmpl *template.Template,
-	recipient, password, TCName, smtpHost string) error {
+	opts *options,
+	username, recipient, password string) error {
 	smtpPort := "25"
-	emailFrom := "OASIS Quorum Calculator <no-reply@quorum.oasis-open.org>"
-	//emailPassword := ""
-
-	subject := "OQC - OASIS Quorum Calculator: Account creation"
 
 	data := struct {
-		Recipient string
-		Password  string
-		TCName    string
+		Username string
+		Password string
+		TCName   string
+		BaseURL  string
 	}{
-		Recipient: recipient,
-		Password:  password,
-		TCName:    TCName,
+		Username: username,
+		Password: password,
+		TCName:   opts.tcName,
+		BaseURL:  opts.baseURL,
 	}
 
-	writeBody := func(body io.Writer) error {
-		fmt.Fprintf(body, "To: %s\r\n", recipient)
-		fmt.Fprintf(body, "From: %s\r\n", emailFrom)
-		fmt.Fprintf(body, "Subject: %s\r\n", subject)
-		fmt.Fprint(body, "MIME-Version: 1.0\r\n")
-		fmt.Fprint(body, "Content-Transfer-Encoding: 8bit\r\n")
-		fmt.Fprint(body, "Content-Type: text/plain; charset=\"UTF-8\"\r\n")
-		fmt.Fprint(body, "\r\n")
-		if err := tmpl.Execute(body, data); err != nil {
-			return err
-		}
-		_, err := fmt.Fprint(body, "\r\n")
-		return err
+	msg := &mail.Message{
+		From:    opts.from,
+		To:      recipient,
+		Subject: "OQC - OASIS Quorum Calculator: Account creation",
+		Body:    mail.TemplateBody(tmpl, data),
 	}
 
-	//auth := smtp.PlainAuth("", emailFrom, emailPassword, smtpHost)
-
-	if err := send(
-		smtpHost+":"+smtpPort, emailFrom, recipient, writeBody); err != nil {
+	if err := mail.Send(opts.smtpHost+":"+smtpPort, msg); err != nil {
 		return fmt.Errorf("failed to send email: %w", err)
 	}
 	log.Printf("Email to %s sent successfully!\n", recipient)
@@ -122,31 +75,45 @@ func sendMail(
 	return nil
 }
 
-func run(tmplText, passwordCSV, TCName, smtpHost string) error {
-	passwordsFile, err := os.Open(passwordCSV)
+func run(templatesDir, committee, passwordCSV string, opts *options) error {
+	if !misc.ValidURL(opts.baseURL) {
+		return fmt.Errorf("base URL %q is not an absolute http(s) URL", opts.baseURL)
+	}
+	if _, err := netmail.ParseAddress(opts.from); err != nil {
+		return fmt.Errorf("sender %q is not a valid address: %w", opts.from, err)
+	}
+	// Check the templates before sending any email.
+	templates, err := mail.LoadTemplates(templatesDir)
 	if err != nil {
 		return err
 	}
-	defer passwordsFile.Close()
+	tmpl := templates.Lookup(mail.AccountTemplate, committee)
 
-	r := csv.NewReader(passwordsFile)
-	records, err := r.ReadAll()
+	passwordsFile, err := os.Open(passwordCSV)
 	if err != nil {
 		return err
 	}
+	defer passwordsFile.Close()
 
-	// make sure that mixed line endings are all \r\n
-	tmplText = strings.ReplaceAll(tmplText, "\r\n", "\n")
-	tmplText = strings.ReplaceAll(tmplText, "\n", "\r\n")
-
-	tmpl, err := template.New("body").Parse(tmplText)
+	r := csv.NewReader(passwordsFile)
+	// The email column is optional.
+	r.FieldsPerRecord = -1
+	records, err := r.ReadAll()
 	if err != nil {
 		return err
 	}
 
-	log.Printf("sending out emails for TC `%s`\n", TCName)
+	log.Printf("sending out emails for TC `%s`\n", opts.tcName)
 	for _, record := range records {
-		if err := sendMail(tmpl, record[0], record[1], TCName, smtpHost); err != nil {
+		if len(record) < 2 {
+			return fmt.Errorf("record %q has not enough columns", record)
+		}
+		// Fall back to the username if there is no email address.
+		username, recipient := record[0], record[0]
+		if len(record) > 2 && record[2] != "" {
+			recipient = record[2]
+		}
+		if err := sendMail(tmpl, opts, username, recipient, record[1]); err != nil {
 			return err
 		}
 	}
@@ -155,16 +122,21 @@ func run(tmplText, passwordCSV, TCName, smtpHost string) error {
 
 func main() {
 	var (
-		passwordCSV string
-		TCName      string
-		smtpHost    string
+		passwordCSV  string
+		templatesDir string
+		committee    string
+		opts         options
 	)
 
 	flag.StringVar(&passwordCSV, "p", "passwords.csv", "CSV file of the list of users and passwords.")
 
-	flag.StringVar(&TCName, "t", "", "Name of the TC to mention in the email.")
-	flag.StringVar(&smtpHost, "h", "localhost", "Name of the smtp server to connect to.")
+	flag.StringVar(&opts.tcName, "t", "", "Name of the TC to mention in the email.")
+	flag.StringVar(&opts.smtpHost, "h", "localhost", "Name of the smtp server to connect to.")
+	flag.StringVar(&opts.from, "from", mail.DefaultSender, "Sender of the emails.")
+	flag.StringVar(&opts.baseURL, "base-url", defaultBaseURL, "URL of the OQC to link in the emails.")
+	flag.StringVar(&templatesDir, "templates", "", "Directory of custom email templates.")
+	flag.StringVar(&committee, "c", "", "Short code of the committee to use the email template of.")
 	flag.Parse()
 
-	check(run(templateTxt, passwordCSV, TCName, smtpHost))
+	check(run(templatesDir, committee, passwordCSV, &opts))
 }
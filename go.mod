@@ -13,5 +13,6 @@ go 1.24.1
 require (
 	github.com/BurntSushi/toml v1.5.0
 	github.com/jmoiron/sqlx v1.4.0
+	github.com/lib/pq v1.10.9
 	github.com/mattn/go-sqlite3 v1.14.28
 )
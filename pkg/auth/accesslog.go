@@ -0,0 +1,85 @@
+// This file is Free Software under the Apache-2.0 License
+// without warranty, see README.md and LICENSE for details.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileCopyrightText: 2025 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2025 Intevation GmbH <https://intevation.de>
+
+package auth
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// accessEntry collects the data of a request which
+// is only known deeper down the handler chain.
+type accessEntry struct {
+	nickname string
+}
+
+// statusRecorder remembers the status code written to the response.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+// WriteHeader implements [http.ResponseWriter].
+func (sr *statusRecorder) WriteHeader(status int) {
+	if sr.status == 0 {
+		sr.status = status
+	}
+	sr.ResponseWriter.WriteHeader(status)
+}
+
+// Write implements [http.ResponseWriter].
+func (sr *statusRecorder) Write(data []byte) (int, error) {
+	if sr.status == 0 {
+		sr.status = http.StatusOK
+	}
+	return sr.ResponseWriter.Write(data)
+}
+
+// Unwrap is used by [http.ResponseController].
+func (sr *statusRecorder) Unwrap() http.ResponseWriter {
+	return sr.ResponseWriter
+}
+
+// setAccessNickname records the nickname of the authenticated user
+// for the access log.
+func setAccessNickname(ctx context.Context, nickname string) {
+	if entry, ok := ctx.Value(accessKey).(*accessEntry); ok {
+		entry.nickname = nickname
+	}
+}
+
+// AccessLog logs method, path, status code, duration and the
+// nickname of the authenticated user of each request with the given level.
+// Query parameters and form values are not logged as they
+// contain session ids and passwords.
+func AccessLog(level slog.Level, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		if !slog.Default().Enabled(ctx, level) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		start := time.Now()
+		entry := new(accessEntry)
+		sr := &statusRecorder{ResponseWriter: w}
+		next.ServeHTTP(sr, r.WithContext(context.WithValue(ctx, accessKey, entry)))
+		status := sr.status
+		if status == 0 {
+			status = http.StatusOK
+		}
+		slog.Log(ctx, level, "request",
+			"method", r.Method,
+			"path", r.URL.Path,
+			"status", status,
+			"duration", time.Since(start),
+			"nickname", entry.nickname)
+	})
+}
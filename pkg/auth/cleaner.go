@@ -18,8 +18,6 @@ import (
 	"github.com/csaf-auxiliary/oasis-quorum-calculator/pkg/database"
 )
 
-const cleanupInterval = 5 * time.Minute
-
 // Cleaner removes stalled sessions from the database.
 type Cleaner struct {
 	cfg *config.Config
@@ -37,7 +35,7 @@ func NewCleaner(cfg *config.Config, db *database.Database) *Cleaner {
 // Run removes stalled session from the database on a schedule.
 func (c *Cleaner) Run(ctx context.Context) {
 	c.cleanup(time.Now())
-	ticker := time.NewTicker(cleanupInterval)
+	ticker := time.NewTicker(c.cfg.Sessions.CleanupInterval)
 	defer ticker.Stop()
 	for {
 		select {
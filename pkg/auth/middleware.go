@@ -28,9 +28,10 @@ const sessionParameter = "SESSIONID"
 
 // Middleware is the middleware to handle authentication.
 type Middleware struct {
-	cfg      *config.Config
-	db       *database.Database
-	redirect string
+	cfg            *config.Config
+	db             *database.Database
+	redirect       string
+	passwordChange string
 }
 
 type contextKeyType int
@@ -38,14 +39,22 @@ type contextKeyType int
 const (
 	sessionKey contextKeyType = iota
 	userKey
+	accessKey
 )
 
 // NewMiddleware returns a new auth middleware.
-func NewMiddleware(cfg *config.Config, db *database.Database, redirect string) *Middleware {
+// Users which have to change their password are redirected
+// to the passwordChange page.
+func NewMiddleware(
+	cfg *config.Config,
+	db *database.Database,
+	redirect, passwordChange string,
+) *Middleware {
 	return &Middleware{
-		cfg:      cfg,
-		db:       db,
-		redirect: redirect,
+		cfg:            cfg,
+		db:             db,
+		redirect:       redirect,
+		passwordChange: passwordChange,
 	}
 }
 
@@ -88,7 +97,24 @@ func (mw *Middleware) Roles(next http.HandlerFunc, roles ...models.Role) http.Ha
 // CommitteeRoles checks if the user has any of the given roles in the committee
 // passed as a form value.
 func (mw *Middleware) CommitteeRoles(next http.HandlerFunc, roles ...models.Role) http.HandlerFunc {
+	return mw.User(committeeRoles(next, roles...))
+}
+
+// AdminOrCommitteeRoles is like CommitteeRoles but lets admins pass, too.
+func (mw *Middleware) AdminOrCommitteeRoles(next http.HandlerFunc, roles ...models.Role) http.HandlerFunc {
+	check := committeeRoles(next, roles...)
 	return mw.User(func(w http.ResponseWriter, r *http.Request) {
+		if user := UserFromContext(r.Context()); user != nil && user.IsAdmin {
+			next(w, r)
+			return
+		}
+		check(w, r)
+	})
+}
+
+// committeeRoles checks the roles of the user in the committee.
+func committeeRoles(next http.HandlerFunc, roles ...models.Role) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
 		committee := r.FormValue("committee")
 		cid, err := misc.Atoi64(committee)
 		if err != nil {
@@ -107,11 +133,22 @@ func (mw *Middleware) CommitteeRoles(next http.HandlerFunc, roles ...models.Role
 			return
 		}
 		next(w, r)
-	})
+	}
 }
 
 // User loads the data of a logged in user and stores it in the context.
+// Users which have to change their password are redirected to do so.
 func (mw *Middleware) User(next http.HandlerFunc) http.HandlerFunc {
+	return mw.user(next, true)
+}
+
+// PasswordChange is like User but lets users pass
+// which have to change their password.
+func (mw *Middleware) PasswordChange(next http.HandlerFunc) http.HandlerFunc {
+	return mw.user(next, false)
+}
+
+func (mw *Middleware) user(next http.HandlerFunc, enforce bool) http.HandlerFunc {
 	return mw.LoggedIn(func(w http.ResponseWriter, r *http.Request) {
 		session := SessionFromContext(r.Context())
 		if session == nil {
@@ -128,6 +165,10 @@ func (mw *Middleware) User(next http.HandlerFunc) http.HandlerFunc {
 			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
 			return
 		}
+		if enforce && user.MustChangePassword {
+			http.Redirect(w, r, session.URL(mw.passwordChange), http.StatusSeeOther)
+			return
+		}
 		nctx := context.WithValue(r.Context(), userKey, user)
 		next(w, r.WithContext(nctx))
 	})
@@ -159,10 +200,22 @@ func (mw *Middleware) Admin(next http.HandlerFunc) http.HandlerFunc {
 	})
 }
 
+// sessionID returns the session id of the request and if it was
+// carried by a cookie. If cookies are configured but not sent
+// the form value is used for compatibility.
+func (mw *Middleware) sessionID(r *http.Request) (string, bool) {
+	if mw.cfg.Sessions.Cookie {
+		if cookie, err := r.Cookie(sessionParameter); err == nil && cookie.Value != "" {
+			return cookie.Value, true
+		}
+	}
+	return r.FormValue(sessionParameter), false
+}
+
 // LoggedIn wraps the middleware around the given next.
 func (mw *Middleware) LoggedIn(next http.HandlerFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		sessionID := r.FormValue(sessionParameter)
+		sessionID, fromCookie := mw.sessionID(r)
 		if sessionID == "" {
 			http.Redirect(w, r, mw.redirect, http.StatusSeeOther)
 			return
@@ -198,7 +251,12 @@ func (mw *Middleware) LoggedIn(next http.HandlerFunc) http.HandlerFunc {
 		session := &Session{
 			nickname: user,
 			id:       sessionID,
+			token:    token,
+		}
+		if fromCookie {
+			session.id = ""
 		}
+		setAccessNickname(r.Context(), user)
 		nctx := context.WithValue(r.Context(), sessionKey, session)
 		defer func() {
 			var sql string
@@ -213,6 +271,9 @@ func (mw *Middleware) LoggedIn(next http.HandlerFunc) http.HandlerFunc {
 					"updating/deleting session failed", "error", err)
 			}
 			if session.delete {
+				if fromCookie {
+					clearCookie(w)
+				}
 				http.Redirect(w, r, mw.redirect, http.StatusSeeOther)
 			}
 		}()
@@ -16,6 +16,10 @@ import (
 	"encoding/base64"
 	"errors"
 	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
 
 	"github.com/csaf-auxiliary/oasis-quorum-calculator/pkg/config"
 	"github.com/csaf-auxiliary/oasis-quorum-calculator/pkg/database"
@@ -25,6 +29,7 @@ import (
 type Session struct {
 	delete   bool
 	id       string
+	token    string
 	nickname string
 }
 
@@ -33,32 +38,110 @@ func (s *Session) Nickname() string {
 	return s.nickname
 }
 
-// ID returns the session id.
+// Token returns the token identifying the session in the database.
+func (s *Session) Token() string {
+	return s.token
+}
+
+// ID returns the session id to be passed as a parameter.
+// It is empty if the session is carried by a cookie.
 func (s *Session) ID() string {
 	return s.id
 }
 
+// URL appends the session id as a parameter to the given path
+// if the session is not carried by a cookie.
+func (s *Session) URL(path string) string {
+	if s.id == "" {
+		return path
+	}
+	sep := "?"
+	if strings.Contains(path, "?") {
+		sep = "&"
+	}
+	return path + sep + sessionParameter + "=" + url.QueryEscape(s.id)
+}
+
+// SetCookie stores the session id in a cookie if configured
+// and hides it from further URLs.
+func (s *Session) SetCookie(w http.ResponseWriter, cfg *config.Config) {
+	if !cfg.Sessions.Cookie {
+		return
+	}
+	http.SetCookie(w, &http.Cookie{
+		Name:     sessionParameter,
+		Value:    s.id,
+		Path:     "/",
+		Secure:   true,
+		HttpOnly: true,
+		SameSite: http.SameSiteStrictMode,
+	})
+	s.id = ""
+}
+
+// clearCookie removes the session cookie.
+func clearCookie(w http.ResponseWriter) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     sessionParameter,
+		Path:     "/",
+		MaxAge:   -1,
+		Secure:   true,
+		HttpOnly: true,
+		SameSite: http.SameSiteStrictMode,
+	})
+}
+
 // Delete marks the session to be deleted.
 func (s *Session) Delete() {
 	s.delete = true
 }
 
+// ErrAccountLocked is returned by [NewSession] if the account
+// is temporarily locked after too many failed logins.
+var ErrAccountLocked = errors.New("account locked")
+
 // NewSession checks nickname and password and returns a new session on success.
+// The nickname is matched case-insensitively. Deactivated users cannot log in.
+// If configured failed logins are counted and the account is locked
+// after too many of them. Logins into locked accounts fail with
+// [ErrAccountLocked].
 func NewSession(
 	ctx context.Context,
 	cfg *config.Config,
 	db *database.Database,
 	nickname, password string,
 ) (*Session, error) {
-	var dbPassword string
-	const passwordSQL = `SELECT password FROM users WHERE nickname = ?`
-	switch err := db.DB.QueryRowContext(
-		ctx, passwordSQL, nickname).Scan(&dbPassword); {
+	tx, err := db.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+	var (
+		dbPassword   string
+		failedLogins int
+		lockedUntil  *time.Time
+		now          = time.Now().UTC()
+	)
+	// Nicknames are matched case-insensitively but
+	// the session uses the nickname as stored.
+	const passwordSQL = `SELECT nickname, password, failed_logins, locked_until FROM users ` +
+		`WHERE lower(nickname) = lower(?) AND deactivated IS NULL ` +
+		`ORDER BY nickname = ? DESC LIMIT 1`
+	switch err := tx.QueryRowContext(
+		ctx, passwordSQL, nickname, nickname).Scan(
+		&nickname, &dbPassword, &failedLogins, &lockedUntil); {
 	case errors.Is(err, sql.ErrNoRows):
 		return nil, nil
 	case err != nil:
 		return nil, err
 	}
+	if lockedUntil != nil {
+		if now.Before(*lockedUntil) {
+			return nil, ErrAccountLocked
+		}
+		// The lock has expired so start counting again.
+		failedLogins = 0
+	}
 	raw, err := base64.URLEncoding.DecodeString(dbPassword)
 	if err != nil {
 		return nil, err
@@ -73,16 +156,40 @@ func NewSession(
 	io.WriteString(hash, password)
 	hashed := hash.Sum(nil)
 	if subtle.ConstantTimeCompare(rest, hashed) == 0 {
-		return nil, nil
+		attempts := cfg.Sessions.LockoutAttempts
+		if attempts <= 0 {
+			return nil, nil
+		}
+		failedLogins++
+		lockedUntil = nil
+		if failedLogins >= attempts {
+			until := now.Add(cfg.Sessions.LockoutDuration)
+			lockedUntil, failedLogins = &until, 0
+		}
+		const failedSQL = `UPDATE users SET failed_logins = ?, locked_until = ? WHERE nickname = ?`
+		if _, err := tx.ExecContext(ctx, failedSQL, failedLogins, lockedUntil, nickname); err != nil {
+			return nil, err
+		}
+		return nil, tx.Commit()
+	}
+	const resetSQL = `UPDATE users SET failed_logins = 0, locked_until = NULL ` +
+		`WHERE nickname = ? AND (failed_logins <> 0 OR locked_until IS NOT NULL)`
+	if _, err := tx.ExecContext(ctx, resetSQL, nickname); err != nil {
+		return nil, err
 	}
 	// Create a new session.
 	stored, sign := cfg.Sessions.GenerateKey()
-	const insertSQL = `INSERT INTO sessions (nickname, token) VALUES (?, ?)`
-	if _, err := db.DB.ExecContext(ctx, insertSQL, nickname, stored); err != nil {
+	const insertSQL = `INSERT INTO sessions (nickname, token, created_at) ` +
+		`VALUES (?, ?, CURRENT_TIMESTAMP)`
+	if _, err := tx.ExecContext(ctx, insertSQL, nickname, stored); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
 		return nil, err
 	}
 	return &Session{
 		id:       stored + ":" + sign,
+		token:    stored,
 		nickname: nickname,
 	}, nil
 }
@@ -0,0 +1,368 @@
+// This file is Free Software under the Apache-2.0 License
+// without warranty, see README.md and LICENSE for details.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileCopyrightText: 2025 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2025 Intevation GmbH <https://intevation.de>
+
+package auth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/csaf-auxiliary/oasis-quorum-calculator/pkg/config"
+	"github.com/csaf-auxiliary/oasis-quorum-calculator/pkg/database"
+	"github.com/csaf-auxiliary/oasis-quorum-calculator/pkg/misc"
+	"github.com/csaf-auxiliary/oasis-quorum-calculator/pkg/models"
+)
+
+// testPassword is the password of the users of [newTestDatabase].
+const testPassword = "secret"
+
+// newTestDatabase creates a database with the users alice and Bob
+// which is removed at the end of the test.
+func newTestDatabase(t *testing.T) (*config.Config, *database.Database) {
+	t.Helper()
+	cfg, err := config.Load("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg.PresetDefaults()
+	cfg.Database = config.Database{
+		DatabaseURL:        filepath.Join(t.TempDir(), "oqcd.sqlite"),
+		Driver:             "sqlite3",
+		Migrate:            true,
+		MaxOpenConnections: 1,
+		MaxIdleConnections: 1,
+	}
+	db, err := database.NewDatabase(context.Background(), &cfg.Database)
+	if err != nil {
+		t.Fatalf("creating database failed: %v", err)
+	}
+	t.Cleanup(func() { db.DB.Close() })
+	password := misc.EncodePassword(testPassword)
+	if _, err := db.DB.Exec(`INSERT INTO users (nickname, password) VALUES (?, ?), (?, ?)`,
+		"alice", password, "Bob", password); err != nil {
+		t.Fatal(err)
+	}
+	return cfg, db
+}
+
+// newSession logs the user in.
+func newSession(t *testing.T, cfg *config.Config, db *database.Database, nickname string) *Session {
+	t.Helper()
+	session, err := NewSession(context.Background(), cfg, db, nickname, testPassword)
+	if err != nil {
+		t.Fatalf("login failed: %v", err)
+	}
+	if session == nil {
+		t.Fatalf("login of %q refused", nickname)
+	}
+	return session
+}
+
+func TestSessionURL(t *testing.T) {
+	s := &Session{id: "a:b"}
+	for _, tc := range []struct {
+		path, want string
+	}{
+		{"/", "/?SESSIONID=a%3Ab"},
+		{"/chair?committee=1", "/chair?committee=1&SESSIONID=a%3Ab"},
+	} {
+		if got := s.URL(tc.path); got != tc.want {
+			t.Errorf("%s: got %q, want %q", tc.path, got, tc.want)
+		}
+	}
+	s.id = ""
+	if got := s.URL("/chair?committee=1"); got != "/chair?committee=1" {
+		t.Errorf("cookie session: got %q", got)
+	}
+}
+
+func TestSetCookie(t *testing.T) {
+	cfg, db := newTestDatabase(t)
+	for _, cookie := range []bool{false, true} {
+		cfg.Sessions.Cookie = cookie
+		session := newSession(t, cfg, db, "alice")
+		id := session.ID()
+		rec := httptest.NewRecorder()
+		session.SetCookie(rec, cfg)
+		cookies := rec.Result().Cookies()
+		if !cookie {
+			if len(cookies) != 0 {
+				t.Errorf("cookie set without cookie mode: %v", cookies)
+			}
+			if session.ID() != id {
+				t.Errorf("session id changed without cookie mode")
+			}
+			continue
+		}
+		if len(cookies) != 1 {
+			t.Fatalf("got %d cookies, want 1", len(cookies))
+		}
+		c := cookies[0]
+		if c.Name != sessionParameter || c.Value != id || !c.Secure || !c.HttpOnly ||
+			c.SameSite != http.SameSiteStrictMode {
+			t.Errorf("unexpected cookie %+v", c)
+		}
+		// The session id is not appended to URLs any more.
+		if session.ID() != "" || session.URL("/") != "/" {
+			t.Errorf("session id still visible: %q", session.URL("/"))
+		}
+	}
+}
+
+func TestLoggedIn(t *testing.T) {
+	cfg, db := newTestDatabase(t)
+	mw := NewMiddleware(cfg, db, "/auth", "/user")
+
+	var seen *Session
+	handler := mw.LoggedIn(func(_ http.ResponseWriter, r *http.Request) {
+		seen = SessionFromContext(r.Context())
+		if r.FormValue("logout") != "" {
+			seen.Delete()
+		}
+	})
+
+	for _, tc := range []struct {
+		name     string
+		cookie   bool
+		inCookie bool
+		inQuery  bool
+		logout   bool
+		status   int
+		url      bool // session id appended to URLs
+	}{
+		{"query", false, false, true, false, http.StatusOK, true},
+		{"cookie", true, true, false, false, http.StatusOK, false},
+		{"query in cookie mode", true, false, true, false, http.StatusOK, true},
+		{"cookie without cookie mode", false, true, false, false, http.StatusSeeOther, false},
+		{"none", true, false, false, false, http.StatusSeeOther, false},
+		{"logout query", false, false, true, true, http.StatusSeeOther, true},
+		{"logout cookie", true, true, false, true, http.StatusSeeOther, false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg.Sessions.Cookie = tc.cookie
+			session := newSession(t, cfg, db, "alice")
+			id := session.ID()
+			query := url.Values{}
+			if tc.inQuery {
+				query.Set(sessionParameter, id)
+			}
+			if tc.logout {
+				query.Set("logout", "true")
+			}
+			req := httptest.NewRequest(http.MethodGet, "/?"+query.Encode(), nil)
+			if tc.inCookie {
+				req.AddCookie(&http.Cookie{Name: sessionParameter, Value: id})
+			}
+			rec := httptest.NewRecorder()
+			seen = nil
+			handler(rec, req)
+
+			if rec.Code != tc.status {
+				t.Errorf("got status %d, want %d", rec.Code, tc.status)
+			}
+			if tc.status == http.StatusSeeOther && !tc.logout {
+				if seen != nil {
+					t.Error("handler called without session")
+				}
+				return
+			}
+			if seen == nil {
+				t.Fatal("handler not called")
+			}
+			if seen.Nickname() != "alice" || seen.Token() != session.Token() {
+				t.Errorf("got session of %q", seen.Nickname())
+			}
+			if got := seen.URL("/") != "/"; got != tc.url {
+				t.Errorf("session id in URL: got %t, want %t", got, tc.url)
+			}
+			if !tc.logout {
+				return
+			}
+			// A deleted session cannot be used any more.
+			var n int
+			if err := db.DB.QueryRow(`SELECT count(*) FROM sessions WHERE token = ?`,
+				session.Token()).Scan(&n); err != nil {
+				t.Fatal(err)
+			}
+			if n != 0 {
+				t.Error("session not deleted")
+			}
+			cleared := false
+			for _, c := range rec.Result().Cookies() {
+				cleared = cleared || (c.Name == sessionParameter && c.MaxAge < 0)
+			}
+			if cleared != tc.inCookie {
+				t.Errorf("cookie cleared: got %t, want %t", cleared, tc.inCookie)
+			}
+		})
+	}
+}
+
+func TestLoggedInForged(t *testing.T) {
+	cfg, db := newTestDatabase(t)
+	mw := NewMiddleware(cfg, db, "/auth", "/user")
+	handler := mw.LoggedIn(func(http.ResponseWriter, *http.Request) {
+		t.Error("handler called with forged session")
+	})
+	stored, _ := cfg.Sessions.GenerateKey()
+	req := httptest.NewRequest(http.MethodGet,
+		"/?"+sessionParameter+"="+url.QueryEscape(stored+":Zm9yZ2Vk"), nil)
+	rec := httptest.NewRecorder()
+	handler(rec, req)
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("got status %d, want %d", rec.Code, http.StatusUnauthorized)
+	}
+}
+
+func TestNewSessionCaseInsensitive(t *testing.T) {
+	cfg, db := newTestDatabase(t)
+	if _, err := db.DB.Exec(`INSERT INTO users (nickname, password, deactivated) `+
+		`VALUES ('carol', ?, CURRENT_TIMESTAMP)`, misc.EncodePassword(testPassword)); err != nil {
+		t.Fatal(err)
+	}
+	for _, tc := range []struct {
+		login, password string
+		want            string
+	}{
+		{"alice", testPassword, "alice"},
+		{"ALICE", testPassword, "alice"},
+		{"AlIcE", testPassword, "alice"},
+		{"bob", testPassword, "Bob"},
+		{"BOB", testPassword, "Bob"},
+		{"alice", "SECRET", ""},
+		{"carol", testPassword, ""},
+		{"dave", testPassword, ""},
+	} {
+		session, err := NewSession(context.Background(), cfg, db, tc.login, tc.password)
+		if err != nil {
+			t.Fatalf("%s: login failed: %v", tc.login, err)
+		}
+		var got string
+		if session != nil {
+			got = session.Nickname()
+		}
+		if got != tc.want {
+			t.Errorf("%s: got session of %q, want %q", tc.login, got, tc.want)
+		}
+	}
+}
+
+// lockState returns the lockout columns of the user.
+func lockState(t *testing.T, db *database.Database, nickname string) (int, *time.Time) {
+	t.Helper()
+	var (
+		failed int
+		until  *time.Time
+	)
+	if err := db.DB.QueryRow(`SELECT failed_logins, locked_until FROM users WHERE nickname = ?`,
+		nickname).Scan(&failed, &until); err != nil {
+		t.Fatal(err)
+	}
+	return failed, until
+}
+
+func TestLockout(t *testing.T) {
+	cfg, db := newTestDatabase(t)
+	cfg.Sessions.LockoutAttempts = 3
+	cfg.Sessions.LockoutDuration = 15 * time.Minute
+	ctx := context.Background()
+
+	login := func(password string) (*Session, error) {
+		t.Helper()
+		return NewSession(ctx, cfg, db, "ALICE", password)
+	}
+	fail := func(n int) {
+		t.Helper()
+		for range n {
+			if session, err := login("wrong"); session != nil || err != nil {
+				t.Fatalf("wrong password: got %v, %v", session, err)
+			}
+		}
+	}
+
+	// Successful logins reset the counter.
+	fail(2)
+	if failed, _ := lockState(t, db, "alice"); failed != 2 {
+		t.Fatalf("got %d failed logins, want 2", failed)
+	}
+	newSession(t, cfg, db, "alice")
+	if failed, until := lockState(t, db, "alice"); failed != 0 || until != nil {
+		t.Fatalf("login did not reset: %d, %v", failed, until)
+	}
+
+	// Too many failures lock the account even for the right password.
+	start := time.Now()
+	fail(3)
+	_, until := lockState(t, db, "alice")
+	if until == nil {
+		t.Fatal("account not locked")
+	}
+	if d := until.Sub(start); d < cfg.Sessions.LockoutDuration ||
+		d > cfg.Sessions.LockoutDuration+time.Minute {
+		t.Errorf("locked for %v, want %v", d, cfg.Sessions.LockoutDuration)
+	}
+	if _, err := login(testPassword); !errors.Is(err, ErrAccountLocked) {
+		t.Fatalf("got error %v, want %v", err, ErrAccountLocked)
+	}
+	// Other accounts are not affected.
+	newSession(t, cfg, db, "Bob")
+
+	// The lock expires.
+	if _, err := db.DB.Exec(`UPDATE users SET locked_until = ? WHERE nickname = 'alice'`,
+		time.Now().UTC().Add(-time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+	newSession(t, cfg, db, "alice")
+	if failed, until := lockState(t, db, "alice"); failed != 0 || until != nil {
+		t.Errorf("expired lock not reset: %d, %v", failed, until)
+	}
+
+	// A failure after an expired lock starts counting again.
+	fail(3)
+	if _, until := lockState(t, db, "alice"); until == nil {
+		t.Fatal("account not locked again")
+	}
+	if _, err := db.DB.Exec(`UPDATE users SET locked_until = ? WHERE nickname = 'alice'`,
+		time.Now().UTC().Add(-time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+	fail(1)
+	if failed, until := lockState(t, db, "alice"); failed != 1 || until != nil {
+		t.Errorf("got %d failed logins and lock %v, want 1 and none", failed, until)
+	}
+
+	// Admins can unlock accounts.
+	fail(2)
+	if _, err := login(testPassword); !errors.Is(err, ErrAccountLocked) {
+		t.Fatalf("got error %v, want %v", err, ErrAccountLocked)
+	}
+	if err := models.UnlockUser(ctx, db, "alice"); err != nil {
+		t.Fatalf("unlocking failed: %v", err)
+	}
+	newSession(t, cfg, db, "alice")
+}
+
+func TestLockoutDisabled(t *testing.T) {
+	cfg, db := newTestDatabase(t)
+	cfg.Sessions.LockoutAttempts = 0
+	for range 10 {
+		if session, err := NewSession(context.Background(), cfg, db, "alice", "wrong"); session != nil || err != nil {
+			t.Fatalf("wrong password: got %v, %v", session, err)
+		}
+	}
+	if failed, until := lockState(t, db, "alice"); failed != 0 || until != nil {
+		t.Errorf("failures counted without lockout: %d, %v", failed, until)
+	}
+	newSession(t, cfg, db, "alice")
+}
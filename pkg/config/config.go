@@ -10,13 +10,17 @@
 package config
 
 import (
+	"errors"
 	"fmt"
 	"log/slog"
 	"net"
+	"os"
+	"path/filepath"
 	"strconv"
 	"time"
 
 	"github.com/BurntSushi/toml"
+	"github.com/csaf-auxiliary/oasis-quorum-calculator/pkg/misc"
 )
 
 // DefaultConfigFile is the name of the default config file.
@@ -27,6 +31,8 @@ const (
 	defaultLogLevel  = slog.LevelInfo
 	defaultLogSource = false
 	defaultLogJSON   = false
+
+	defaultLogAccessLevel = slog.LevelInfo
 )
 
 const (
@@ -44,14 +50,54 @@ const (
 	defaultDatabaseMaxIdleConnections      = 0
 	defaultDatabaseConnMaxLifetime         = 0
 	defaultDatabaseConnMaxIdletime         = 0
+	defaultDatabaseMaintenanceInterval     = 0
+)
+
+const (
+	defaultAbsentMaxDuration = 40 * 24 * time.Hour
 )
 
+const (
+	defaultMeetingsMaxDuration        = 24 * time.Hour
+	defaultMeetingsBlockWithoutVoters = false
+	defaultMeetingsAttendanceGrace    = 10 * time.Minute
+	defaultMeetingsOverrunGrace       = time.Hour
+	defaultMeetingsAutoConclude       = false
+	defaultMeetingsOverrunInterval    = 5 * time.Minute
+)
+
+const (
+	defaultDocumentsDirectory     = "documents"
+	defaultDocumentsMaxUploadSize = 10 * 1024 * 1024
+)
+
+const (
+	defaultWebhooksQuorumReachedURL = ""
+	defaultWebhooksTimeout          = 10 * time.Second
+)
+
+const (
+	defaultMailSMTPHost         = ""
+	defaultMailFrom             = "OASIS Quorum Calculator <no-reply@quorum.oasis-open.org>"
+	defaultMailBaseURL          = ""
+	defaultMailReminderDelay    = 15 * time.Minute
+	defaultMailReminderInterval = time.Minute
+	defaultMailTemplates        = ""
+)
+
+var defaultDocumentsContentTypes = []string{
+	"application/pdf",
+	"text/markdown",
+	"text/plain",
+}
+
 // Log are the config options for the logging.
 type Log struct {
-	File   string     `toml:"file"`
-	Level  slog.Level `toml:"level"`
-	Source bool       `toml:"source"`
-	JSON   bool       `toml:"json"`
+	File        string     `toml:"file"`
+	Level       slog.Level `toml:"level"`
+	Source      bool       `toml:"source"`
+	JSON        bool       `toml:"json"`
+	AccessLevel slog.Level `toml:"access_level"`
 }
 
 // Web are the config options for the web interface.
@@ -71,14 +117,58 @@ type Database struct {
 	MaxIdleConnections      int           `toml:"max_idle_conns"`
 	ConnMaxLifetime         time.Duration `toml:"conn_max_lifetime"`
 	ConnMaxIdletime         time.Duration `toml:"conn_max_idletime"`
+	MaintenanceInterval     time.Duration `toml:"maintenance_interval"`
+}
+
+// Absent are the config options for excused absents of members.
+type Absent struct {
+	MaxDuration time.Duration `toml:"max_duration"`
+}
+
+// Meetings are the config options for meetings.
+type Meetings struct {
+	MaxDuration           time.Duration `toml:"max_duration"`
+	BlockWithoutVoters    bool          `toml:"block_without_voters"`
+	AttendanceGracePeriod time.Duration `toml:"attendance_grace_period"`
+	OverrunGrace          time.Duration `toml:"overrun_grace"`
+	AutoConclude          bool          `toml:"auto_conclude"`
+	OverrunCheckInterval  time.Duration `toml:"overrun_check_interval"`
+}
+
+// Documents are the config options for documents attached to meetings.
+type Documents struct {
+	Directory     string   `toml:"directory"`
+	MaxUploadSize int64    `toml:"max_upload_size"`
+	ContentTypes  []string `toml:"content_types"`
+}
+
+// Webhooks are the config options for webhooks notified about events.
+type Webhooks struct {
+	QuorumReachedURL string        `toml:"quorum_reached_url"`
+	Timeout          time.Duration `toml:"timeout"`
+}
+
+// Mail are the config options for sending emails.
+type Mail struct {
+	SMTPHost         string        `toml:"smtp_host"`
+	From             string        `toml:"from"`
+	BaseURL          string        `toml:"base_url"`
+	ReminderDelay    time.Duration `toml:"reminder_delay"`
+	ReminderInterval time.Duration `toml:"reminder_interval"`
+	Templates        string        `toml:"templates"`
 }
 
 // Config are all the configuration options.
 type Config struct {
-	Log      Log      `toml:"log"`
-	Web      Web      `toml:"web"`
-	Database Database `toml:"database"`
-	Sessions Sessions `toml:"sessions"`
+	Log       Log       `toml:"log"`
+	Web       Web       `toml:"web"`
+	Database  Database  `toml:"database"`
+	Sessions  Sessions  `toml:"sessions"`
+	Absent    Absent    `toml:"absent"`
+	Meetings  Meetings  `toml:"meetings"`
+	Documents Documents `toml:"documents"`
+	Webhooks  Webhooks  `toml:"webhooks"`
+	Mail      Mail      `toml:"mail"`
 }
 
 // Addr returns the combined address the web server should bind to.
@@ -91,10 +181,11 @@ func (w *Web) Addr() string {
 func Load(file string) (*Config, error) {
 	cfg := &Config{
 		Log: Log{
-			File:   defaultLogFile,
-			Level:  defaultLogLevel,
-			Source: defaultLogSource,
-			JSON:   defaultLogJSON,
+			File:        defaultLogFile,
+			Level:       defaultLogLevel,
+			Source:      defaultLogSource,
+			JSON:        defaultLogJSON,
+			AccessLevel: defaultLogAccessLevel,
 		},
 		Web: Web{
 			Host: defaultWebHost,
@@ -110,10 +201,42 @@ func Load(file string) (*Config, error) {
 			MaxIdleConnections:      defaultDatabaseMaxIdleConnections,
 			ConnMaxLifetime:         defaultDatabaseConnMaxLifetime,
 			ConnMaxIdletime:         defaultDatabaseConnMaxIdletime,
+			MaintenanceInterval:     defaultDatabaseMaintenanceInterval,
 		},
 		Sessions: Sessions{
-			Secret: nil,
-			MaxAge: defaultSessionMaxAge,
+			Secret:          nil,
+			MaxAge:          defaultSessionMaxAge,
+			CleanupInterval: defaultSessionCleanupInterval,
+			LockoutAttempts: defaultSessionLockoutAttempts,
+			LockoutDuration: defaultSessionLockoutDuration,
+		},
+		Absent: Absent{
+			MaxDuration: defaultAbsentMaxDuration,
+		},
+		Meetings: Meetings{
+			MaxDuration:           defaultMeetingsMaxDuration,
+			BlockWithoutVoters:    defaultMeetingsBlockWithoutVoters,
+			AttendanceGracePeriod: defaultMeetingsAttendanceGrace,
+			OverrunGrace:          defaultMeetingsOverrunGrace,
+			AutoConclude:          defaultMeetingsAutoConclude,
+			OverrunCheckInterval:  defaultMeetingsOverrunInterval,
+		},
+		Documents: Documents{
+			Directory:     defaultDocumentsDirectory,
+			MaxUploadSize: defaultDocumentsMaxUploadSize,
+			ContentTypes:  defaultDocumentsContentTypes,
+		},
+		Webhooks: Webhooks{
+			QuorumReachedURL: defaultWebhooksQuorumReachedURL,
+			Timeout:          defaultWebhooksTimeout,
+		},
+		Mail: Mail{
+			SMTPHost:         defaultMailSMTPHost,
+			From:             defaultMailFrom,
+			BaseURL:          defaultMailBaseURL,
+			ReminderDelay:    defaultMailReminderDelay,
+			ReminderInterval: defaultMailReminderInterval,
+			Templates:        defaultMailTemplates,
 		},
 	}
 	if file != "" {
@@ -137,28 +260,132 @@ func (cfg *Config) PresetDefaults() {
 	cfg.Sessions.presetDefaults()
 }
 
+// Validate checks the configuration for values which cannot work.
+// All problems found are reported together.
+func (cfg *Config) Validate() error {
+	var errs []error
+	invalid := func(key string, format string, args ...any) {
+		errs = append(errs, fmt.Errorf("config: %s: %s", key, fmt.Sprintf(format, args...)))
+	}
+	// The port is only a placeholder for unix domain sockets.
+	if port := cfg.Web.Port; !filepath.IsAbs(cfg.Web.Host) && (port < 1 || port > 65535) {
+		invalid("web.port", "%d is not in the range 1-65535", port)
+	}
+	switch fi, err := os.Stat(filepath.Join(cfg.Web.Root, "templates")); {
+	case err != nil:
+		invalid("web.root", "%q needs a templates directory: %v", cfg.Web.Root, err)
+	case !fi.IsDir():
+		invalid("web.root", "%q has no templates directory", cfg.Web.Root)
+	}
+	if cfg.Sessions.MaxAge <= 0 {
+		invalid("sessions.max_age", "%s is not positive", cfg.Sessions.MaxAge)
+	}
+	if n := cfg.Sessions.LockoutAttempts; n < 0 {
+		invalid("sessions.lockout_attempts", "%d is negative", n)
+	}
+	if d := cfg.Sessions.LockoutDuration; cfg.Sessions.LockoutAttempts > 0 && d <= 0 {
+		invalid("sessions.lockout_duration", "%s is not positive", d)
+	}
+	if d := cfg.Database.Driver; d != "sqlite3" && d != "postgres" {
+		invalid("database.driver", "%q is not one of sqlite3 or postgres", d)
+	}
+	if n := cfg.Database.MaxOpenConnections; n < 0 {
+		invalid("database.max_open_conns", "%d is negative", n)
+	}
+	if n := cfg.Database.MaxIdleConnections; n < 0 {
+		invalid("database.max_idle_conns", "%d is negative", n)
+	}
+	if d := cfg.Database.ConnMaxLifetime; d < 0 {
+		invalid("database.conn_max_lifetime", "%s is negative", d)
+	}
+	if d := cfg.Database.ConnMaxIdletime; d < 0 {
+		invalid("database.conn_max_idletime", "%s is negative", d)
+	}
+	if d := cfg.Database.MaintenanceInterval; d < 0 {
+		invalid("database.maintenance_interval", "%s is negative", d)
+	}
+	if d := cfg.Absent.MaxDuration; d <= 0 {
+		invalid("absent.max_duration", "%s is not positive", d)
+	}
+	if d := cfg.Meetings.MaxDuration; d <= 0 {
+		invalid("meetings.max_duration", "%s is not positive", d)
+	}
+	if d := cfg.Meetings.AttendanceGracePeriod; d < 0 {
+		invalid("meetings.attendance_grace_period", "%s is negative", d)
+	}
+	if d := cfg.Meetings.OverrunGrace; d < 0 {
+		invalid("meetings.overrun_grace", "%s is negative", d)
+	}
+	if d := cfg.Meetings.OverrunCheckInterval; d <= 0 {
+		invalid("meetings.overrun_check_interval", "%s is not positive", d)
+	}
+	if n := cfg.Documents.MaxUploadSize; n <= 0 {
+		invalid("documents.max_upload_size", "%d is not positive", n)
+	}
+	if d := cfg.Webhooks.Timeout; d <= 0 {
+		invalid("webhooks.timeout", "%s is not positive", d)
+	}
+	if d := cfg.Mail.ReminderDelay; d < 0 {
+		invalid("mail.reminder_delay", "%s is negative", d)
+	}
+	if d := cfg.Mail.ReminderInterval; d <= 0 {
+		invalid("mail.reminder_interval", "%s is not positive", d)
+	}
+	if u := cfg.Mail.BaseURL; u != "" && !misc.ValidURL(u) {
+		invalid("mail.base_url", "%q is not an absolute http(s) URL", u)
+	}
+	return errors.Join(errs...)
+}
+
 func (cfg *Config) fillFromEnv() error {
 	var (
 		storeString   = store(noparse)
 		storeInt      = store(strconv.Atoi)
 		storeBool     = store(strconv.ParseBool)
 		storeLevel    = store(storeLevel)
+		storeInt64    = store(parseInt64)
 		storeDuration = store(time.ParseDuration)
+		storeStrings  = store(parseStrings)
 	)
 	return storeFromEnv(
 		envStore{"OQC_LOG_FILE", storeString(&cfg.Log.File)},
 		envStore{"OQC_LOG_LEVEL", storeLevel(&cfg.Log.Level)},
 		envStore{"OQC_LOG_JSON", storeBool(&cfg.Log.JSON)},
 		envStore{"OQC_LOG_SOURCE", storeBool(&cfg.Log.Source)},
+		envStore{"OQC_LOG_ACCESS_LEVEL", storeLevel(&cfg.Log.AccessLevel)},
 		envStore{"OQC_WEB_HOST", storeString(&cfg.Web.Host)},
 		envStore{"OQC_WEB_PORT", storeInt(&cfg.Web.Port)},
 		envStore{"OQC_WEB_ROOT", storeString(&cfg.Web.Root)},
 		envStore{"OQC_DB_URL", storeString(&cfg.Database.DatabaseURL)},
+		envStore{"OQC_DB_DRIVER", storeString(&cfg.Database.Driver)},
 		envStore{"OQC_DB_MIGRATE", storeBool(&cfg.Database.Migrate)},
 		envStore{"OQC_DB_TERMINATE_AFTER_MIGRATION", storeBool(&cfg.Database.TerminateAfterMigration)},
 		envStore{"OQC_DB_MAX_OPEN_CONNS", storeInt(&cfg.Database.MaxOpenConnections)},
 		envStore{"OQC_DB_MAX_IDLE_CONNS", storeInt(&cfg.Database.MaxIdleConnections)},
 		envStore{"OQC_DB_CONN_MAX_LIFETIME", storeDuration(&cfg.Database.ConnMaxLifetime)},
 		envStore{"OQC_DB_CONN_MAX_IDLETIME", storeDuration(&cfg.Database.ConnMaxIdletime)},
+		envStore{"OQC_DB_MAINTENANCE_INTERVAL", storeDuration(&cfg.Database.MaintenanceInterval)},
+		envStore{"OQC_SESSIONS_CLEANUP_INTERVAL", storeDuration(&cfg.Sessions.CleanupInterval)},
+		envStore{"OQC_SESSIONS_COOKIE", storeBool(&cfg.Sessions.Cookie)},
+		envStore{"OQC_SESSIONS_LOCKOUT_ATTEMPTS", storeInt(&cfg.Sessions.LockoutAttempts)},
+		envStore{"OQC_SESSIONS_LOCKOUT_DURATION", storeDuration(&cfg.Sessions.LockoutDuration)},
+		envStore{"OQC_ABSENT_MAX_DURATION", storeDuration(&cfg.Absent.MaxDuration)},
+		envStore{"OQC_MEETINGS_MAX_DURATION", storeDuration(&cfg.Meetings.MaxDuration)},
+		envStore{"OQC_MEETINGS_BLOCK_WITHOUT_VOTERS", storeBool(&cfg.Meetings.BlockWithoutVoters)},
+		envStore{"OQC_MEETINGS_ATTENDANCE_GRACE_PERIOD", storeDuration(&cfg.Meetings.AttendanceGracePeriod)},
+		envStore{"OQC_MEETINGS_OVERRUN_GRACE", storeDuration(&cfg.Meetings.OverrunGrace)},
+		envStore{"OQC_MEETINGS_AUTO_CONCLUDE", storeBool(&cfg.Meetings.AutoConclude)},
+		envStore{"OQC_MEETINGS_OVERRUN_CHECK_INTERVAL", storeDuration(&cfg.Meetings.OverrunCheckInterval)},
+		envStore{"OQC_DOCUMENTS_DIR", storeString(&cfg.Documents.Directory)},
+		envStore{"OQC_DOCUMENTS_MAX_UPLOAD_SIZE", storeInt64(&cfg.Documents.MaxUploadSize)},
+		envStore{"OQC_DOCUMENTS_CONTENT_TYPES", storeStrings(&cfg.Documents.ContentTypes)},
+		envStore{"OQC_WEBHOOKS_QUORUM_REACHED_URL", storeString(&cfg.Webhooks.QuorumReachedURL)},
+		envStore{"OQC_WEBHOOKS_TIMEOUT", storeDuration(&cfg.Webhooks.Timeout)},
+		envStore{"OQC_MAIL_SMTP_HOST", storeString(&cfg.Mail.SMTPHost)},
+		envStore{"OQC_MAIL_FROM", storeString(&cfg.Mail.From)},
+		envStore{"OQC_MAIL_BASE_URL", storeString(&cfg.Mail.BaseURL)},
+		envStore{"OQC_MAIL_REMINDER_DELAY", storeDuration(&cfg.Mail.ReminderDelay)},
+		envStore{"OQC_MAIL_REMINDER_INTERVAL", storeDuration(&cfg.Mail.ReminderInterval)},
+		envStore{"OQC_MAIL_TEMPLATES", storeString(&cfg.Mail.Templates)},
 	)
 }
@@ -0,0 +1,105 @@
+// This file is Free Software under the Apache-2.0 License
+// without warranty, see README.md and LICENSE for details.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileCopyrightText: 2025 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2025 Intevation GmbH <https://intevation.de>
+
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"testing"
+)
+
+// configKey extracts the keys from the validation errors.
+var configKey = regexp.MustCompile(`(?m)^config: ([a-z_.]+): `)
+
+// loadConfig loads the config from the given TOML. If no web root
+// is configured one with a templates directory is used.
+func loadConfig(t *testing.T, content string) (*Config, error) {
+	t.Helper()
+	dir := t.TempDir()
+	file := filepath.Join(dir, "oqcd.toml")
+	if err := os.WriteFile(file, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := Load(file)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Web.Root == defaultWebRoot {
+		cfg.Web.Root = filepath.Join(dir, "web")
+		if err := os.MkdirAll(filepath.Join(cfg.Web.Root, "templates"), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return cfg, nil
+}
+
+func TestValidate(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		content string
+		invalid []string
+	}{
+		{"defaults", ``, nil},
+		{"unix socket", "[web]\nhost = \"/run/oqcd.sock\"\nport = 0\n", nil},
+		{"lockout", "[sessions]\nlockout_attempts = 5\nlockout_duration = \"30m\"\n", nil},
+		{"postgres", "[database]\ndriver = \"postgres\"\n", nil},
+		{"base url", "[mail]\nbase_url = \"https://oqc.example.com\"\n", nil},
+		{"port zero", "[web]\nport = 0\n", []string{"web.port"}},
+		{"port too large", "[web]\nport = 65536\n", []string{"web.port"}},
+		{"relative socket", "[web]\nhost = \"oqcd.sock\"\nport = 0\n", []string{"web.port"}},
+		{"no templates", "[web]\nroot = \"/nonexistent\"\n", []string{"web.root"}},
+		{"max age", "[sessions]\nmax_age = \"0s\"\n", []string{"sessions.max_age"}},
+		{"negative lockout", "[sessions]\nlockout_attempts = -1\n", []string{"sessions.lockout_attempts"}},
+		{"lockout duration", "[sessions]\nlockout_attempts = 3\nlockout_duration = \"0s\"\n",
+			[]string{"sessions.lockout_duration"}},
+		{"driver", "[database]\ndriver = \"mysql\"\n", []string{"database.driver"}},
+		{"connections", "[database]\nmax_open_conns = -1\nmax_idle_conns = -1\n",
+			[]string{"database.max_open_conns", "database.max_idle_conns"}},
+		{"connection times", "[database]\nconn_max_lifetime = \"-1s\"\n" +
+			"conn_max_idletime = \"-1s\"\nmaintenance_interval = \"-1s\"\n",
+			[]string{"database.conn_max_lifetime", "database.conn_max_idletime", "database.maintenance_interval"}},
+		{"absent", "[absent]\nmax_duration = \"0s\"\n", []string{"absent.max_duration"}},
+		{"meetings", "[meetings]\nmax_duration = \"0s\"\nattendance_grace_period = \"-1m\"\n" +
+			"overrun_grace = \"-1m\"\noverrun_check_interval = \"0s\"\n",
+			[]string{"meetings.max_duration", "meetings.attendance_grace_period",
+				"meetings.overrun_grace", "meetings.overrun_check_interval"}},
+		{"upload size", "[documents]\nmax_upload_size = 0\n", []string{"documents.max_upload_size"}},
+		{"webhooks", "[webhooks]\ntimeout = \"0s\"\n", []string{"webhooks.timeout"}},
+		{"reminders", "[mail]\nreminder_delay = \"-1h\"\nreminder_interval = \"0s\"\n",
+			[]string{"mail.reminder_delay", "mail.reminder_interval"}},
+		{"relative base url", "[mail]\nbase_url = \"oqc.example.com\"\n", []string{"mail.base_url"}},
+		{"ftp base url", "[mail]\nbase_url = \"ftp://oqc.example.com\"\n", []string{"mail.base_url"}},
+		{"several sections", "[web]\nport = 0\n[sessions]\nmax_age = \"0s\"\n",
+			[]string{"web.port", "sessions.max_age"}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg, err := loadConfig(t, tc.content)
+			if err != nil {
+				t.Fatalf("loading config failed: %v", err)
+			}
+			var invalid []string
+			if err := cfg.Validate(); err != nil {
+				for _, m := range configKey.FindAllStringSubmatch(err.Error(), -1) {
+					invalid = append(invalid, m[1])
+				}
+			}
+			if !slices.Equal(invalid, tc.invalid) {
+				t.Errorf("got invalid keys %q, want %q", invalid, tc.invalid)
+			}
+		})
+	}
+}
+
+func TestLoadUnknownKey(t *testing.T) {
+	if _, err := loadConfig(t, "[sessions]\nmax_ages = \"1h\"\n"); err == nil {
+		t.Error("unknown key accepted")
+	}
+}
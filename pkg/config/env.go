@@ -11,6 +11,8 @@ package config
 import (
 	"log/slog"
 	"os"
+	"strconv"
+	"strings"
 )
 
 // envStore maps an env to a store function.
@@ -29,6 +31,22 @@ func noparse(s string) (string, error) {
 	return s, nil
 }
 
+// parseInt64 parses a string to an int64.
+func parseInt64(s string) (int64, error) {
+	return strconv.ParseInt(s, 10, 64)
+}
+
+// parseStrings parses a comma separated list of strings.
+func parseStrings(s string) ([]string, error) {
+	var list []string
+	for v := range strings.SplitSeq(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			list = append(list, v)
+		}
+	}
+	return list, nil
+}
+
 // store returns a function to parse a string to return a function to store a value.
 func store[T any](parse func(string) (T, error)) func(*T) func(string) error {
 	return func(dst *T) func(string) error {
@@ -20,15 +20,29 @@ import (
 	"time"
 )
 
-const defaultSessionMaxAge = time.Hour
+const (
+	defaultSessionMaxAge          = time.Hour
+	defaultSessionCleanupInterval = 5 * time.Minute
+	defaultSessionLockoutAttempts = 0
+	defaultSessionLockoutDuration = 15 * time.Minute
+)
 
 // HexBytes is a hex encoded string.
 type HexBytes []byte
 
 // Sessions are the config options of the session management.
 type Sessions struct {
-	MaxAge time.Duration `toml:"max_age"`
-	Secret HexBytes      `toml:"secret"`
+	MaxAge          time.Duration `toml:"max_age"`
+	CleanupInterval time.Duration `toml:"cleanup_interval"`
+	Secret          HexBytes      `toml:"secret"`
+	// Cookie carries the session id in a cookie
+	// instead of an URL parameter.
+	Cookie bool `toml:"cookie"`
+	// LockoutAttempts is the number of failed logins after
+	// which an account is locked. Zero disables the lockout.
+	LockoutAttempts int `toml:"lockout_attempts"`
+	// LockoutDuration is how long an account stays locked.
+	LockoutDuration time.Duration `toml:"lockout_duration"`
 }
 
 // UnmarshalText implements [encoding.TextUnmarshaler].
@@ -49,6 +63,12 @@ func (s *Sessions) presetDefaults() {
 		slog.Info("Generated new secret session key. "+
 			"Store in config to reuse it.", "secret", skey)
 	}
+	if s.CleanupInterval <= 0 {
+		slog.Warn("Session cleanup interval is not positive. Using default.",
+			"cleanup_interval", s.CleanupInterval,
+			"default", defaultSessionCleanupInterval)
+		s.CleanupInterval = defaultSessionCleanupInterval
+	}
 }
 
 // GenerateKey generates a new session key signed by the session secret.
@@ -25,9 +25,16 @@ import (
 // was done and the configuration forces a termination.
 var ErrTerminateMigration = errors.New("terminate migration")
 
+// Migration describes a migration of the database schema.
+type Migration struct {
+	Version     int64
+	Description string
+}
+
 // Database implements the handling with the database connection pool.
 type Database struct {
-	DB *sqlx.DB
+	DB  *sqlx.DB
+	cfg *config.Database
 }
 
 func sqlite3URL(url string) string {
@@ -37,62 +44,164 @@ func sqlite3URL(url string) string {
 	return url
 }
 
-// NewDatabase creates a new connection pool.
-func NewDatabase(ctx context.Context, cfg *config.Database) (*Database, error) {
+// migrationsDir returns the directory of the migrations for a driver.
+func migrationsDir(driver string) (string, error) {
+	switch driver {
+	case "sqlite3":
+		return "migrations", nil
+	case "postgres":
+		return "migrations/postgres", nil
+	default:
+		return "", fmt.Errorf("database driver %q is not supported", driver)
+	}
+}
 
-	if cfg.Driver != "sqlite3" {
-		return nil, fmt.Errorf("database driver %q is not supported", cfg.Driver)
+// connect opens the database and reports if it needs to be created.
+// A not existing SQLite database is only created if mayCreate is true.
+// Otherwise the returned connection pool is nil.
+func connect(ctx context.Context, cfg *config.Database, mayCreate bool) (*sqlx.DB, bool, error) {
+	switch cfg.Driver {
+	case "sqlite3":
+		create, err := needsCreation(cfg.DatabaseURL)
+		if err != nil {
+			return nil, false, err
+		}
+		if create && !mayCreate {
+			return nil, true, nil
+		}
+		url := sqlite3URL(cfg.DatabaseURL)
+		db, err := sqlx.ConnectContext(ctx, "sqlite3", url)
+		if err != nil {
+			return nil, false, fmt.Errorf("cannot connect to database %q: %w", url, err)
+		}
+		return db, create, nil
+	case "postgres":
+		db, err := openPostgres(ctx, cfg.DatabaseURL)
+		if err != nil {
+			return nil, false, fmt.Errorf("cannot connect to database: %w", err)
+		}
+		create, err := needsPostgresCreation(ctx, db)
+		if err != nil {
+			db.Close()
+			return nil, false, err
+		}
+		return db, create, nil
+	default:
+		return nil, false, fmt.Errorf("database driver %q is not supported", cfg.Driver)
 	}
+}
 
-	create, err := needsCreation(cfg.DatabaseURL)
+// NewDatabase creates a new connection pool.
+func NewDatabase(ctx context.Context, cfg *config.Database) (*Database, error) {
+	dir, err := migrationsDir(cfg.Driver)
+	if err != nil {
+		return nil, err
+	}
+	db, create, err := connect(ctx, cfg, cfg.Migrate)
 	if err != nil {
 		return nil, err
 	}
-
 	if !cfg.Migrate && create {
+		if db != nil {
+			db.Close()
+		}
 		return nil, errors.New("setup migration needed")
 	}
-
-	url := sqlite3URL(cfg.DatabaseURL)
-
-	db, err := sqlx.ConnectContext(ctx, "sqlite3", url)
-	if err != nil {
-		return nil, fmt.Errorf("cannot connect to database %q: %w", url, err)
-	}
-
 	db.SetMaxOpenConns(cfg.MaxOpenConnections)
 	db.SetMaxIdleConns(cfg.MaxIdleConnections)
 	db.SetConnMaxLifetime(cfg.ConnMaxLifetime)
 	db.SetConnMaxIdleTime(cfg.ConnMaxIdletime)
-
-	migs, err := listMigrations()
+	migs, err := listMigrations(dir)
 	if err != nil {
 		return nil, err
 	}
-
 	if create {
 		if err := createDatabase(ctx, cfg, db, migs); err != nil {
-			return nil, fmt.Errorf("creating database %q failed: %w", url, err)
+			return nil, fmt.Errorf("creating database failed: %w", err)
 		}
 		if cfg.TerminateAfterMigration {
 			return nil, ErrTerminateMigration
 		}
-		return &Database{DB: db}, nil
+		return &Database{DB: db, cfg: cfg}, nil
 	}
-
-	database := &Database{DB: db}
-
+	database := &Database{DB: db, cfg: cfg}
 	if err := database.applyMigrations(ctx, cfg, migs); err != nil {
 		return nil, err
 	}
-
 	if cfg.Migrate && cfg.TerminateAfterMigration {
 		return nil, ErrTerminateMigration
 	}
-
 	return database, nil
 }
 
+// PendingMigrations returns the migrations which would be applied
+// to the database on the next start. The database is not modified.
+// If the database does not exist yet the setup migration is returned.
+func PendingMigrations(ctx context.Context, cfg *config.Database) ([]Migration, error) {
+	dir, err := migrationsDir(cfg.Driver)
+	if err != nil {
+		return nil, err
+	}
+	migs, err := listMigrations(dir)
+	if err != nil {
+		return nil, err
+	}
+	db, create, err := connect(ctx, cfg, false)
+	if err != nil {
+		return nil, err
+	}
+	if db != nil {
+		defer db.Close()
+	}
+	if create {
+		return []Migration{{
+			Version:     migs[len(migs)-1].version,
+			Description: "setup",
+		}}, nil
+	}
+	version, err := currentVersion(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+	var pending []Migration
+	for _, mig := range pendingMigrations(migs, version) {
+		pending = append(pending, Migration{
+			Version:     mig.version,
+			Description: mig.description,
+		})
+	}
+	return pending, nil
+}
+
+// Stats are the usage statistics of the connection pool.
+type Stats struct {
+	MaxOpenConnections int    `json:"max_open_connections"`
+	OpenConnections    int    `json:"open_connections"`
+	InUse              int    `json:"in_use"`
+	Idle               int    `json:"idle"`
+	WaitCount          int64  `json:"wait_count"`
+	WaitDuration       string `json:"wait_duration"`
+	MaxIdleClosed      int64  `json:"max_idle_closed"`
+	MaxIdleTimeClosed  int64  `json:"max_idle_time_closed"`
+	MaxLifetimeClosed  int64  `json:"max_lifetime_closed"`
+}
+
+// Stats returns the usage statistics of the connection pool.
+func (db *Database) Stats() Stats {
+	s := db.DB.Stats()
+	return Stats{
+		MaxOpenConnections: s.MaxOpenConnections,
+		OpenConnections:    s.OpenConnections,
+		InUse:              s.InUse,
+		Idle:               s.Idle,
+		WaitCount:          s.WaitCount,
+		WaitDuration:       s.WaitDuration.String(),
+		MaxIdleClosed:      s.MaxIdleClosed,
+		MaxIdleTimeClosed:  s.MaxIdleTimeClosed,
+		MaxLifetimeClosed:  s.MaxLifetimeClosed,
+	}
+}
+
 // Close closes the connection pool.
 func (db *Database) Close(context.Context) {
 	// Currently not needed.
@@ -0,0 +1,78 @@
+// This file is Free Software under the Apache-2.0 License
+// without warranty, see README.md and LICENSE for details.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileCopyrightText: 2025 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2025 Intevation GmbH <https://intevation.de>
+
+package database
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/csaf-auxiliary/oasis-quorum-calculator/pkg/config"
+)
+
+func TestPendingMigrations(t *testing.T) {
+	ctx := context.Background()
+	cfg := &config.Database{
+		DatabaseURL:        filepath.Join(t.TempDir(), "oqcd.sqlite"),
+		Driver:             "sqlite3",
+		Migrate:            true,
+		MaxOpenConnections: 1,
+		MaxIdleConnections: 1,
+	}
+	migs, err := listMigrations("migrations")
+	if err != nil {
+		t.Fatal(err)
+	}
+	latest := migs[len(migs)-1]
+
+	// A missing database would be created but is not.
+	pending, err := PendingMigrations(ctx, cfg)
+	if err != nil {
+		t.Fatalf("checking missing database failed: %v", err)
+	}
+	if want := []Migration{{latest.version, "setup"}}; !slices.Equal(pending, want) {
+		t.Errorf("got %v for missing database, want %v", pending, want)
+	}
+	if _, err := os.Stat(cfg.DatabaseURL); !os.IsNotExist(err) {
+		t.Errorf("database created by check: %v", err)
+	}
+
+	db, err := NewDatabase(ctx, cfg)
+	if err != nil {
+		t.Fatalf("creating database failed: %v", err)
+	}
+	defer db.DB.Close()
+	if pending, err := PendingMigrations(ctx, cfg); err != nil || len(pending) != 0 {
+		t.Errorf("got %v, %v for current database, want none", pending, err)
+	}
+
+	// A database at an older version.
+	const older = 22
+	if err := db.Rollback(ctx, older); err != nil {
+		t.Fatalf("rolling back failed: %v", err)
+	}
+	if pending, err = PendingMigrations(ctx, cfg); err != nil {
+		t.Fatalf("checking old database failed: %v", err)
+	}
+	var want []Migration
+	for _, mig := range migs {
+		if mig.version > older {
+			want = append(want, Migration{mig.version, mig.description})
+		}
+	}
+	if len(want) == 0 || !slices.Equal(pending, want) {
+		t.Errorf("got %v for old database, want %v", pending, want)
+	}
+	// The check does not migrate.
+	if version, err := currentVersion(ctx, db.DB); err != nil || version != older {
+		t.Errorf("got version %d, %v after check, want %d", version, err, older)
+	}
+}
@@ -0,0 +1,195 @@
+// This file is Free Software under the Apache-2.0 License
+// without warranty, see README.md and LICENSE for details.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileCopyrightText: 2025 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2025 Intevation GmbH <https://intevation.de>
+
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/csaf-auxiliary/oasis-quorum-calculator/pkg/misc"
+)
+
+// fixup is a data migration which cannot be expressed in portable SQL.
+// It runs in the transaction of its migration right before the script.
+type fixup func(ctx context.Context, tx *sql.Tx) error
+
+// fixups are the fixups by the versions of their migrations.
+var fixups = map[int64]fixup{
+	15: backfillCommitteeSlugs,
+	20: foldNicknames,
+}
+
+// backfillCommitteeSlugs adds the slugs of the committees following
+// the rules of [misc.Slugify]. The committee with the lowest id keeps
+// a slug derived from its name. The others get a counter appended
+// which does not collide with any other slug.
+func backfillCommitteeSlugs(ctx context.Context, tx *sql.Tx) error {
+	const alterSQL = `ALTER TABLE committees ADD COLUMN slug VARCHAR NOT NULL DEFAULT ''`
+	if _, err := tx.ExecContext(ctx, alterSQL); err != nil {
+		return fmt.Errorf("adding committee slugs failed: %w", err)
+	}
+	rows, err := tx.QueryContext(ctx, `SELECT id, name FROM committees ORDER BY id`)
+	if err != nil {
+		return fmt.Errorf("loading committees failed: %w", err)
+	}
+	type committee struct {
+		id   int64
+		slug string
+	}
+	var committees []committee
+	for rows.Next() {
+		var (
+			c    committee
+			name string
+		)
+		if err := rows.Scan(&c.id, &name); err != nil {
+			rows.Close()
+			return fmt.Errorf("scanning committees failed: %w", err)
+		}
+		if c.slug = misc.Slugify(name); c.slug == "" {
+			c.slug = "committee"
+		}
+		committees = append(committees, c)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("loading committees failed: %w", err)
+	}
+	// All derived slugs are reserved so that a counter
+	// never takes the slug of a later committee.
+	taken := map[string]bool{}
+	for _, c := range committees {
+		taken[c.slug] = true
+	}
+	first := map[string]bool{}
+	const updateSQL = `UPDATE committees SET slug = ? WHERE id = ?`
+	for _, c := range committees {
+		slug := c.slug
+		if first[slug] {
+			for i := 2; taken[slug]; i++ {
+				slug = fmt.Sprintf("%s-%d", c.slug, i)
+			}
+			taken[slug] = true
+		}
+		first[c.slug] = true
+		if _, err := tx.ExecContext(ctx, updateSQL, slug, c.id); err != nil {
+			return fmt.Errorf("storing committee slug failed: %w", err)
+		}
+	}
+	return nil
+}
+
+// foldNicknames merges the users whose nicknames only differ in case.
+// The user whose nickname sorts first is kept. The others are merged
+// into it and removed. The kept user stays an admin if one of the
+// merged users was an admin.
+func foldNicknames(ctx context.Context, tx *sql.Tx) error {
+	rows, err := tx.QueryContext(ctx, `SELECT nickname FROM users ORDER BY nickname`)
+	if err != nil {
+		return fmt.Errorf("loading nicknames failed: %w", err)
+	}
+	var nicknames []string
+	for rows.Next() {
+		var nickname string
+		if err := rows.Scan(&nickname); err != nil {
+			rows.Close()
+			return fmt.Errorf("scanning nicknames failed: %w", err)
+		}
+		nicknames = append(nicknames, nickname)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("loading nicknames failed: %w", err)
+	}
+	keeps := map[string]string{}
+	for _, nickname := range nicknames {
+		lower := strings.ToLower(nickname)
+		keep, ok := keeps[lower]
+		if !ok {
+			keeps[lower] = nickname
+			continue
+		}
+		slog.WarnContext(ctx, "Merging users with nicknames only differing in case",
+			"keep", keep,
+			"remove", nickname)
+		if err := mergeNicknameTx(ctx, tx, keep, nickname); err != nil {
+			return fmt.Errorf("merging %q into %q failed: %w", nickname, keep, err)
+		}
+	}
+	return nil
+}
+
+// mergeNicknameTx moves the data of the user remove to the user keep
+// and deletes the user remove. It only works on the tables existing
+// before the nicknames became case insensitive.
+func mergeNicknameTx(ctx context.Context, tx *sql.Tx, keep, remove string) error {
+	for _, merge := range []struct {
+		query string
+		args  []any
+	}{{
+		`UPDATE users SET is_admin = true WHERE nickname = ? ` +
+			`AND EXISTS (SELECT 1 FROM users WHERE nickname = ? AND is_admin)`,
+		[]any{keep, remove},
+	}, {
+		`INSERT INTO attendees (meetings_id, nickname, voting_allowed, non_voting) ` +
+			`SELECT meetings_id, ?, voting_allowed, non_voting FROM attendees WHERE nickname = ? ` +
+			`ON CONFLICT (meetings_id, nickname) DO NOTHING`,
+		[]any{keep, remove},
+	}, {
+		`INSERT INTO committee_roles (nickname, committees_id, committee_role_id) ` +
+			`SELECT ?, committees_id, committee_role_id FROM committee_roles WHERE nickname = ? ` +
+			`ON CONFLICT (nickname, committee_role_id, committees_id) DO NOTHING`,
+		[]any{keep, remove},
+	}, {
+		`INSERT INTO member_history (nickname, committees_id, status, since) ` +
+			`SELECT ?, committees_id, status, since FROM member_history WHERE nickname = ? ` +
+			`ON CONFLICT (nickname, committees_id, since) DO NOTHING`,
+		[]any{keep, remove},
+	}, {
+		`INSERT INTO member_absent (nickname, committee_id, start_time, stop_time, status) ` +
+			`SELECT ?, committee_id, start_time, stop_time, status FROM member_absent WHERE nickname = ? ` +
+			`ON CONFLICT (nickname, committee_id, start_time) DO NOTHING`,
+		[]any{keep, remove},
+	}, {
+		`INSERT INTO attendance_reminders (meetings_id, nickname, sent) ` +
+			`SELECT meetings_id, ?, sent FROM attendance_reminders WHERE nickname = ? ` +
+			`ON CONFLICT (meetings_id, nickname) DO NOTHING`,
+		[]any{keep, remove},
+	}, {
+		`UPDATE meeting_documents SET uploaded_by = ? WHERE uploaded_by = ?`,
+		[]any{keep, remove},
+	}, {
+		`INSERT INTO meeting_snapshot_voters (meetings_id, nickname) ` +
+			`SELECT meetings_id, ? FROM meeting_snapshot_voters WHERE nickname = ? ` +
+			`ON CONFLICT (meetings_id, nickname) DO NOTHING`,
+		[]any{keep, remove},
+	}, {
+		// The attendees are deleted before the user as their
+		// delete trigger records changes referring to the user.
+		`DELETE FROM attendees WHERE nickname = ?`,
+		[]any{remove},
+	}, {
+		`DELETE FROM member_history WHERE nickname = ?`,
+		[]any{remove},
+	}, {
+		`DELETE FROM meeting_snapshot_voters WHERE nickname = ?`,
+		[]any{remove},
+	}, {
+		`DELETE FROM users WHERE nickname = ?`,
+		[]any{remove},
+	}} {
+		if _, err := tx.ExecContext(ctx, merge.query, merge.args...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
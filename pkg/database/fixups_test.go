@@ -0,0 +1,147 @@
+// This file is Free Software under the Apache-2.0 License
+// without warranty, see README.md and LICENSE for details.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileCopyrightText: 2025 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2025 Intevation GmbH <https://intevation.de>
+
+package database
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/csaf-auxiliary/oasis-quorum-calculator/pkg/config"
+	"github.com/csaf-auxiliary/oasis-quorum-calculator/pkg/misc"
+)
+
+// migrateWithFixture creates a database, rolls it back to the given
+// version, fills it with the fixture and migrates it to the latest version.
+func migrateWithFixture(t *testing.T, version int64, fixture ...string) *Database {
+	t.Helper()
+	ctx := context.Background()
+	cfg := &config.Database{
+		DatabaseURL:        filepath.Join(t.TempDir(), "oqcd.sqlite"),
+		Driver:             "sqlite3",
+		Migrate:            true,
+		MaxOpenConnections: 1,
+		MaxIdleConnections: 1,
+	}
+	db, err := NewDatabase(ctx, cfg)
+	if err != nil {
+		t.Fatalf("creating database failed: %v", err)
+	}
+	if err := db.Rollback(ctx, version); err != nil {
+		t.Fatalf("rolling back to version %d failed: %v", version, err)
+	}
+	for _, stmt := range fixture {
+		if _, err := db.DB.ExecContext(ctx, stmt); err != nil {
+			t.Fatalf("fixture %q failed: %v", stmt, err)
+		}
+	}
+	db.DB.Close()
+	if db, err = NewDatabase(ctx, cfg); err != nil {
+		t.Fatalf("migrating database failed: %v", err)
+	}
+	t.Cleanup(func() { db.DB.Close() })
+	return db
+}
+
+func TestFoldNicknames(t *testing.T) {
+	db := migrateWithFixture(t, 19,
+		`INSERT INTO users (nickname, password, is_admin) VALUES `+
+			`('alice', 'x', true), ('Alice', 'y', false), ('bob', 'z', false)`,
+		`INSERT INTO committees (id, name, slug) VALUES (1, 'TC', 'tc')`,
+		`INSERT INTO committee_roles (nickname, committees_id, committee_role_id) VALUES `+
+			`('alice', 1, 1), ('Alice', 1, 1), ('Alice', 1, 0)`,
+		`INSERT INTO meetings (id, committees_id, start_time, stop_time) VALUES `+
+			`(1, 1, '2025-01-01 10:00:00+00:00', '2025-01-01 11:00:00+00:00')`,
+		`INSERT INTO attendees (meetings_id, nickname) VALUES (1, 'alice')`,
+	)
+	ctx := context.Background()
+
+	rows, err := db.DB.QueryContext(ctx, `SELECT nickname, is_admin FROM users ORDER BY nickname`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+	var users []string
+	for rows.Next() {
+		var (
+			nickname string
+			isAdmin  bool
+		)
+		if err := rows.Scan(&nickname, &isAdmin); err != nil {
+			t.Fatal(err)
+		}
+		users = append(users, nickname)
+		if nickname == "Alice" && !isAdmin {
+			t.Error("merged user lost admin rights")
+		}
+	}
+	if len(users) != 3 || users[0] != "Alice" || users[1] != "admin" || users[2] != "bob" {
+		t.Fatalf("got users %v, want [Alice admin bob]", users)
+	}
+
+	var roles, attendees int
+	if err := db.DB.QueryRowContext(ctx,
+		`SELECT count(*) FROM committee_roles WHERE nickname = 'Alice'`).Scan(&roles); err != nil {
+		t.Fatal(err)
+	}
+	if roles != 2 {
+		t.Errorf("got %d roles, want 2", roles)
+	}
+	if err := db.DB.QueryRowContext(ctx,
+		`SELECT count(*) FROM attendees WHERE nickname = 'Alice'`).Scan(&attendees); err != nil {
+		t.Fatal(err)
+	}
+	if attendees != 1 {
+		t.Errorf("got %d attendees, want 1", attendees)
+	}
+
+	if _, err := db.DB.ExecContext(ctx,
+		`INSERT INTO users (nickname, password) VALUES ('ALICE', 'x')`); err == nil {
+		t.Error("nicknames differing in case are not rejected")
+	}
+}
+
+func TestBackfillCommitteeSlugs(t *testing.T) {
+	db := migrateWithFixture(t, 14,
+		`INSERT INTO committees (id, name) VALUES `+
+			`(1, 'Foo & Bar'), (2, 'foo/bar'), (3, 'Foo  Bar 2'), `+
+			`(4, 'Über  Ausschuss'), (5, '!!!'), (6, '???')`,
+	)
+	want := map[int64]string{
+		1: "foo-bar",
+		2: "foo-bar-3",
+		3: "foo-bar-2",
+		4: "ber-ausschuss",
+		5: "committee",
+		6: "committee-2",
+	}
+	rows, err := db.DB.QueryContext(context.Background(), `SELECT id, slug FROM committees`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var (
+			id   int64
+			slug string
+		)
+		if err := rows.Scan(&id, &slug); err != nil {
+			t.Fatal(err)
+		}
+		if slug != want[id] {
+			t.Errorf("committee %d: got slug %q, want %q", id, slug, want[id])
+		}
+		if misc.Slugify(slug) != slug {
+			t.Errorf("committee %d: slug %q is not valid", id, slug)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatal(err)
+	}
+}
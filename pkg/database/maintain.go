@@ -0,0 +1,103 @@
+// This file is Free Software under the Apache-2.0 License
+// without warranty, see README.md and LICENSE for details.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileCopyrightText: 2025 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2025 Intevation GmbH <https://intevation.de>
+
+package database
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+// Maintain defragments the database and updates the statistics
+// of the query planner. It logs the reclaimed size.
+func (db *Database) Maintain(ctx context.Context) error {
+	// VACUUM cannot run inside a transaction so a dedicated
+	// connection is used which is in auto commit mode.
+	conn, err := db.DB.Connx(ctx)
+	if err != nil {
+		return fmt.Errorf("maintenance connection failed: %w", err)
+	}
+	defer conn.Close()
+
+	var (
+		sizeSQL string
+		stmts   []string
+	)
+	switch db.cfg.Driver {
+	case "postgres":
+		sizeSQL = `SELECT pg_database_size(current_database())`
+		stmts = []string{`VACUUM (ANALYZE)`}
+	default:
+		sizeSQL = `SELECT page_count * page_size FROM pragma_page_count(), pragma_page_size()`
+		stmts = []string{
+			`PRAGMA optimize`,
+			`VACUUM`,
+			`PRAGMA wal_checkpoint(TRUNCATE)`,
+		}
+	}
+	sizeOfDB := func() (int64, error) {
+		var size int64
+		err := conn.QueryRowContext(ctx, sizeSQL).Scan(&size)
+		return size, err
+	}
+	start := time.Now()
+	before, err := sizeOfDB()
+	if err != nil {
+		return fmt.Errorf("determining database size failed: %w", err)
+	}
+	for _, stmt := range stmts {
+		if _, err := conn.ExecContext(ctx, stmt); err != nil {
+			return fmt.Errorf("database maintenance %q failed: %w", stmt, err)
+		}
+	}
+	after, err := sizeOfDB()
+	if err != nil {
+		return fmt.Errorf("determining database size failed: %w", err)
+	}
+	slog.InfoContext(ctx, "Database maintained",
+		"size", after,
+		"reclaimed", before-after,
+		"duration", time.Since(start))
+	return nil
+}
+
+// Maintainer runs the maintenance of the database on a schedule.
+type Maintainer struct {
+	interval time.Duration
+	db       *Database
+}
+
+// NewMaintainer creates a new maintainer.
+// A non positive interval disables the maintenance.
+func NewMaintainer(interval time.Duration, db *Database) *Maintainer {
+	return &Maintainer{
+		interval: interval,
+		db:       db,
+	}
+}
+
+// Run maintains the database on a schedule.
+func (m *Maintainer) Run(ctx context.Context) {
+	if m.interval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := m.db.Maintain(ctx); err != nil {
+				slog.ErrorContext(ctx, "maintaining database failed", "error", err)
+			}
+		}
+	}
+}
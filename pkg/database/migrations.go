@@ -37,6 +37,7 @@ type migration struct {
 	version     int64
 	description string
 	path        string
+	down        string // Path of the optional down script.
 }
 
 func needsCreation(url string) (bool, error) {
@@ -53,6 +54,17 @@ func needsCreation(url string) (bool, error) {
 	return false, nil
 }
 
+// needsPostgresCreation checks if the versions table
+// exists in the PostgreSQL database.
+func needsPostgresCreation(ctx context.Context, db *sqlx.DB) (bool, error) {
+	var exists bool
+	if err := db.QueryRowContext(ctx,
+		`SELECT to_regclass('versions') IS NOT NULL`).Scan(&exists); err != nil {
+		return false, fmt.Errorf("unable to examine database: %w", err)
+	}
+	return !exists, nil
+}
+
 func sqlQuote(s string) string {
 	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
 }
@@ -77,35 +89,61 @@ func createFuncMap() template.FuncMap {
 }
 
 func (m *migration) load(cfg *config.Database, funcs template.FuncMap) (string, error) {
-	data, err := migrations.ReadFile(m.path)
+	return loadScript(m.path, cfg, funcs)
+}
+
+func (m *migration) loadDown(cfg *config.Database, funcs template.FuncMap) (string, error) {
+	return loadScript(m.down, cfg, funcs)
+}
+
+func loadScript(path string, cfg *config.Database, funcs template.FuncMap) (string, error) {
+	data, err := migrations.ReadFile(path)
 	if err != nil {
-		return "", fmt.Errorf("loading migration %q failed: %w", m.path, err)
+		return "", fmt.Errorf("loading migration %q failed: %w", path, err)
 	}
 	tmpl, err := template.New("sql").Funcs(funcs).Parse(string(data))
 	if err != nil {
-		return "", fmt.Errorf("parsing migration %q failed: %w", m.path, err)
+		return "", fmt.Errorf("parsing migration %q failed: %w", path, err)
 	}
 	var script bytes.Buffer
 	if err := tmpl.Execute(&script, cfg); err != nil {
-		return "", fmt.Errorf("templating migration %q failed: %w", m.path, err)
+		return "", fmt.Errorf("templating migration %q failed: %w", path, err)
 	}
 	return script.String(), nil
 }
 
-func (db *Database) applyMigrations(ctx context.Context, cfg *config.Database, migs []migration) error {
-	slog.InfoContext(ctx, "Applying migrations", "num", len(migs)-1)
+// currentVersion returns the version of the last applied migration.
+func currentVersion(ctx context.Context, db *sqlx.DB) (int64, error) {
 	var version int64
-	if err := db.DB.QueryRowContext(
+	if err := db.QueryRowContext(
 		ctx, "SELECT max(version) FROM VERSIONS").Scan(&version); err != nil {
-		return fmt.Errorf("current migration version not found: %w", err)
+		return 0, fmt.Errorf("current migration version not found: %w", err)
+	}
+	return version, nil
+}
+
+// pendingMigrations returns the migrations newer than the given version.
+func pendingMigrations(migs []migration, version int64) []migration {
+	idx := slices.IndexFunc(migs, func(m migration) bool {
+		return m.version > version
+	})
+	if idx == -1 {
+		return nil
+	}
+	return migs[idx:]
+}
+
+func (db *Database) applyMigrations(ctx context.Context, cfg *config.Database, migs []migration) error {
+	slog.InfoContext(ctx, "Applying migrations", "num", len(migs)-1)
+	version, err := currentVersion(ctx, db.DB)
+	if err != nil {
+		return err
 	}
 	slog.DebugContext(ctx, "current migration version", "version", version)
 	funcMap := createFuncMap()
-	for i := range migs {
-		mig := &migs[i]
-		if mig.version <= version {
-			continue
-		}
+	pending := pendingMigrations(migs, version)
+	for i := range pending {
+		mig := &pending[i]
 		script, err := mig.load(cfg, funcMap)
 		if err != nil {
 			return fmt.Errorf("loading migration %q failed: %w", mig.path, err)
@@ -115,7 +153,13 @@ func (db *Database) applyMigrations(ctx context.Context, cfg *config.Database, m
 		if err != nil {
 			return fmt.Errorf("cannot start migrations: %w", err)
 		}
-		if _, err := tx.ExecContext(ctx, script); err != nil {
+		if fix := fixups[mig.version]; fix != nil {
+			if err := fix(ctx, tx); err != nil {
+				tx.Rollback()
+				return fmt.Errorf("fixing up migration %q failed: %w", mig.path, err)
+			}
+		}
+		if err := execScript(ctx, tx, script); err != nil {
 			tx.Rollback()
 			return fmt.Errorf("applying migration %q failed: %w", mig.path, err)
 		}
@@ -136,8 +180,88 @@ func (db *Database) applyMigrations(ctx context.Context, cfg *config.Database, m
 	return nil
 }
 
+// Rollback reverts the migrations newer than toVersion in reverse
+// order by applying their down scripts. It fails without changing
+// the database if one of these migrations has no down script.
+func (db *Database) Rollback(ctx context.Context, toVersion int64) error {
+	dir, err := migrationsDir(db.cfg.Driver)
+	if err != nil {
+		return err
+	}
+	migs, err := listMigrations(dir)
+	if err != nil {
+		return err
+	}
+	if len(migs) == 0 {
+		return errors.New("no migrations found")
+	}
+	if toVersion < migs[0].version {
+		return fmt.Errorf("cannot roll back past version %d", migs[0].version)
+	}
+	version, err := currentVersion(ctx, db.DB)
+	if err != nil {
+		return err
+	}
+	// Index of the first migration to be reverted.
+	first := slices.IndexFunc(migs, func(m migration) bool {
+		return m.version > toVersion
+	})
+	if first == -1 || toVersion >= version {
+		slog.InfoContext(ctx, "No migrations to roll back", "version", version)
+		return nil
+	}
+	var steps []int
+	for i := len(migs) - 1; i >= first; i-- {
+		if migs[i].version > version {
+			continue
+		}
+		if migs[i].down == "" {
+			return fmt.Errorf("migration %q has no down script", migs[i].path)
+		}
+		steps = append(steps, i)
+	}
+	slog.InfoContext(ctx, "Rolling back migrations", "num", len(steps), "version", toVersion)
+	funcMap := createFuncMap()
+	for _, i := range steps {
+		mig, prev := &migs[i], &migs[i-1]
+		script, err := mig.loadDown(db.cfg, funcMap)
+		if err != nil {
+			return err
+		}
+		slog.DebugContext(ctx, "rolling back migration", "path", mig.down)
+		if err := func() error {
+			tx, err := db.DB.BeginTx(ctx, nil)
+			if err != nil {
+				return fmt.Errorf("cannot start rollback: %w", err)
+			}
+			defer tx.Rollback()
+			if err := execScript(ctx, tx, script); err != nil {
+				return fmt.Errorf("rolling back migration %q failed: %w", mig.down, err)
+			}
+			if _, err := tx.ExecContext(ctx,
+				"DELETE FROM versions WHERE version >= ?", mig.version,
+			); err != nil {
+				return fmt.Errorf("deleting version of migration %q failed: %w", mig.path, err)
+			}
+			// A created database only knows the version it was created with.
+			if _, err := tx.ExecContext(ctx,
+				"INSERT INTO versions (version, description) VALUES (?, ?) "+
+					"ON CONFLICT (version) DO NOTHING",
+				prev.version, prev.description,
+			); err != nil {
+				return fmt.Errorf("inserting version of migration %q failed: %w", prev.path, err)
+			}
+			return tx.Commit()
+		}(); err != nil {
+			return err
+		}
+	}
+	slog.InfoContext(ctx, "Rollback done", "version", toVersion)
+	return nil
+}
+
 func createDatabase(ctx context.Context, cfg *config.Database, db *sqlx.DB, migs []migration) error {
-	slog.InfoContext(ctx, "Creating database", "url", cfg.DatabaseURL)
+	slog.InfoContext(ctx, "Creating database", "driver", cfg.Driver)
 	script, err := migs[0].load(cfg, createFuncMap())
 	if err != nil {
 		return err
@@ -147,8 +271,8 @@ func createDatabase(ctx context.Context, cfg *config.Database, db *sqlx.DB, migs
 		return err
 	}
 	defer tx.Rollback()
-	if _, err := tx.ExecContext(ctx, script); err != nil {
-		return err
+	if err := execScript(ctx, tx, script); err != nil {
+		return fmt.Errorf("applying migration %q failed: %w", migs[0].path, err)
 	}
 	if _, err := tx.ExecContext(ctx,
 		"INSERT INTO versions (version, description) VALUES (?, ?)",
@@ -160,12 +284,12 @@ func createDatabase(ctx context.Context, cfg *config.Database, db *sqlx.DB, migs
 	if err := tx.Commit(); err != nil {
 		return err
 	}
-	slog.InfoContext(ctx, "Creating database done", "url", cfg.DatabaseURL)
+	slog.InfoContext(ctx, "Creating database done", "driver", cfg.Driver)
 	return nil
 }
 
-func listMigrations() ([]migration, error) {
-	entries, err := migrations.ReadDir("migrations")
+func listMigrations(dir string) ([]migration, error) {
+	entries, err := migrations.ReadDir(dir)
 	if err != nil {
 		return nil, err
 	}
@@ -173,6 +297,12 @@ func listMigrations() ([]migration, error) {
 	if err != nil {
 		return nil, err
 	}
+	downs := map[string]string{}
+	for _, entry := range entries {
+		if name, ok := strings.CutSuffix(entry.Name(), ".down.sql"); ok && entry.Type().IsRegular() {
+			downs[name+".sql"] = dir + "/" + entry.Name()
+		}
+	}
 	var migs []migration
 	for _, entry := range entries {
 		if !entry.Type().IsRegular() {
@@ -187,11 +317,12 @@ func listMigrations() ([]migration, error) {
 			return nil, err
 		}
 		description := m[2]
-		path := "migrations/" + entry.Name()
+		path := dir + "/" + entry.Name()
 		migs = append(migs, migration{
 			version:     version,
 			description: description,
 			path:        path,
+			down:        downs[entry.Name()],
 		})
 	}
 	slices.SortFunc(migs, func(a, b migration) int {
@@ -13,25 +13,43 @@ CREATE TABLE versions (
 );
 
 CREATE TABLE users (
-    nickname  VARCHAR PRIMARY KEY,
-    password  VARCHAR NOT NULL,
-    firstname VARCHAR,
-    lastname  VARCHAR,
-    is_admin  BOOLEAN NOT NULL DEFAULT FALSE
+    nickname             VARCHAR PRIMARY KEY,
+    password             VARCHAR NOT NULL,
+    firstname            VARCHAR,
+    lastname             VARCHAR,
+    is_admin             BOOLEAN NOT NULL DEFAULT FALSE,
+    timezone             VARCHAR,
+    deactivated          TIMESTAMP,
+    must_change_password BOOLEAN NOT NULL DEFAULT FALSE,
+    email                VARCHAR,
+    default_view         VARCHAR,
+    failed_logins        INTEGER NOT NULL DEFAULT 0,
+    locked_until         TIMESTAMP
 );
 
+CREATE UNIQUE INDEX users_nickname_lower ON users(lower(nickname));
+
 CREATE TABLE sessions (
     token       VARCHAR   PRIMARY KEY,
     nickname    VARCHAR   NOT NULL REFERENCES users(nickname) ON DELETE CASCADE,
-    last_access timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP
+    last_access timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    created_at  TIMESTAMP
 );
 
 CREATE TABLE committees (
-    id          INTEGER PRIMARY KEY AUTOINCREMENT,
-    name        VARCHAR NOT NULL,
-    description VARCHAR
+    id                   INTEGER PRIMARY KEY AUTOINCREMENT,
+    name                 VARCHAR NOT NULL,
+    description          VARCHAR,
+    created_at           TIMESTAMP,
+    updated_at           TIMESTAMP,
+    archived             BOOLEAN NOT NULL DEFAULT FALSE,
+    slug                 VARCHAR NOT NULL,
+    attendance_reminders BOOLEAN NOT NULL DEFAULT FALSE,
+    version              INTEGER NOT NULL DEFAULT 0
 );
 
+CREATE UNIQUE INDEX committees_slug ON committees(slug);
+
 CREATE TABLE committee_role (
     id          INTEGER PRIMARY KEY,
     name        VARCHAR NOT NULL UNIQUE,
@@ -39,8 +57,8 @@ CREATE TABLE committee_role (
 );
 
 INSERT INTO committee_role (id, name, description) VALUES
-    (0, 'member', 'Regular committee member'),
-    (1, 'chair', 'Committee chair'),
+    (0, 'chair', 'Committee chair'),
+    (1, 'member', 'Regular committee member'),
     (2, 'secretary', 'Committee secretary'),
     (3, 'staff', 'Committee staff');
 
@@ -54,7 +72,8 @@ INSERT INTO member_status (id, name, description) VALUES
     (0, 'member', 'Regular committee member'),
     (1, 'voting', 'Voting member'),
     (2, 'nonevoting', 'Persistent none voting member'),
-    (3, 'nomember', 'Not a member');
+    (3, 'nomember', 'Not a member'),
+    (4, 'observer', 'Observer without voting rights');
 
 CREATE TABLE member_history (
     nickname      VARCHAR   NOT NULL,
@@ -90,6 +109,11 @@ CREATE TABLE meetings (
     start_time    TIMESTAMP NOT NULL,
     stop_time     TIMESTAMP NOT NULL,
     description   VARCHAR,
+    agenda        VARCHAR,
+    created_at    TIMESTAMP,
+    updated_at    TIMESTAMP,
+    concluded_at  TIMESTAMP,
+    version       INTEGER   NOT NULL DEFAULT 0,
     UNIQUE(committees_id, start_time),
     CHECK (strftime('%s', start_time) <= strftime('%s', stop_time))
 );
@@ -98,6 +122,7 @@ CREATE TABLE attendees (
     meetings_id    INTEGER NOT NULL REFERENCES meetings(id)    ON DELETE CASCADE,
     nickname       VARCHAR NOT NULL REFERENCES users(nickname) ON DELETE CASCADE,
     voting_allowed BOOLEAN NOT NULL DEFAULT FALSE,
+    non_voting     BOOLEAN NOT NULL DEFAULT FALSE,
     UNIQUE(meetings_id, nickname)
 );
 
@@ -135,11 +160,58 @@ END;
 INSERT INTO users (nickname, password, lastname, is_admin)
     VALUES ('admin', {{ generatePassword "admin" | sqlQuote }}, 'Administrator', true);
 
+CREATE TABLE absent_status (
+    id          INTEGER PRIMARY KEY,
+    name        VARCHAR NOT NULL UNIQUE,
+    description VARCHAR NOT NULL
+);
+
+INSERT INTO absent_status (id, name, description) VALUES
+    (0, 'requested', 'Requested by the member'),
+    (1, 'approved', 'Approved excused absent'),
+    (2, 'rejected', 'Rejected excused absent');
+
 CREATE TABLE member_absent (
     nickname       VARCHAR NOT NULL REFERENCES users(nickname)    ON DELETE CASCADE,
     start_time     TIMESTAMP NOT NULL,
     stop_time      TIMESTAMP NOT NULL,
     committee_id  INTEGER NOT NULL REFERENCES committees(id)     ON DELETE CASCADE,
+    status         INTEGER NOT NULL DEFAULT 1 REFERENCES absent_status(id), -- approved
     CHECK (start_time < stop_time),
     UNIQUE (nickname, committee_id, start_time)
 );
+
+CREATE TABLE meeting_documents (
+    id           INTEGER   PRIMARY KEY AUTOINCREMENT,
+    meetings_id  INTEGER   NOT NULL REFERENCES meetings(id)    ON DELETE CASCADE,
+    filename     VARCHAR   NOT NULL,
+    content_type VARCHAR   NOT NULL,
+    uploaded_by  VARCHAR            REFERENCES users(nickname) ON DELETE SET NULL,
+    uploaded     TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE meeting_snapshots (
+    meetings_id      INTEGER PRIMARY KEY REFERENCES meetings(id) ON DELETE CASCADE,
+    voting           INTEGER NOT NULL,
+    attending_voting INTEGER NOT NULL,
+    taken            TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE meeting_snapshot_voters (
+    meetings_id INTEGER NOT NULL REFERENCES meeting_snapshots(meetings_id) ON DELETE CASCADE,
+    nickname    VARCHAR NOT NULL,
+    UNIQUE(meetings_id, nickname)
+);
+
+CREATE TABLE meeting_expected (
+    meetings_id INTEGER NOT NULL REFERENCES meetings(id)    ON DELETE CASCADE,
+    nickname    VARCHAR NOT NULL REFERENCES users(nickname) ON DELETE CASCADE,
+    UNIQUE(meetings_id, nickname)
+);
+
+CREATE TABLE attendance_reminders (
+    meetings_id INTEGER   NOT NULL REFERENCES meetings(id)    ON DELETE CASCADE,
+    nickname    VARCHAR   NOT NULL REFERENCES users(nickname) ON DELETE CASCADE,
+    sent        TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(meetings_id, nickname)
+);
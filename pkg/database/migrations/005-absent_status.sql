@@ -0,0 +1,21 @@
+-- This file is Free Software under the Apache-2.0 License
+-- without warranty, see README.md and LICENSE for details.
+--
+-- SPDX-License-Identifier: Apache-2.0
+--
+-- SPDX-FileCopyrightText: 2025 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+-- Software-Engineering: 2025 Intevation GmbH <https://intevation.de>
+
+CREATE TABLE absent_status (
+    id          INTEGER PRIMARY KEY,
+    name        VARCHAR NOT NULL UNIQUE,
+    description VARCHAR NOT NULL
+);
+
+INSERT INTO absent_status (id, name, description) VALUES
+    (0, 'requested', 'Requested by the member'),
+    (1, 'approved', 'Approved excused absent'),
+    (2, 'rejected', 'Rejected excused absent');
+
+ALTER TABLE member_absent
+    ADD COLUMN status INTEGER NOT NULL DEFAULT 1; -- approved
@@ -0,0 +1,16 @@
+-- This file is Free Software under the Apache-2.0 License
+-- without warranty, see README.md and LICENSE for details.
+--
+-- SPDX-License-Identifier: Apache-2.0
+--
+-- SPDX-FileCopyrightText: 2025 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+-- Software-Engineering: 2025 Intevation GmbH <https://intevation.de>
+
+CREATE TABLE meeting_documents (
+    id           INTEGER   PRIMARY KEY AUTOINCREMENT,
+    meetings_id  INTEGER   NOT NULL REFERENCES meetings(id)    ON DELETE CASCADE,
+    filename     VARCHAR   NOT NULL,
+    content_type VARCHAR   NOT NULL,
+    uploaded_by  VARCHAR            REFERENCES users(nickname) ON DELETE SET NULL,
+    uploaded     TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
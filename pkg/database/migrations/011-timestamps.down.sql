@@ -0,0 +1,12 @@
+-- This file is Free Software under the Apache-2.0 License
+-- without warranty, see README.md and LICENSE for details.
+--
+-- SPDX-License-Identifier: Apache-2.0
+--
+-- SPDX-FileCopyrightText: 2025 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+-- Software-Engineering: 2025 Intevation GmbH <https://intevation.de>
+
+ALTER TABLE meetings DROP COLUMN created_at;
+ALTER TABLE meetings DROP COLUMN updated_at;
+ALTER TABLE committees DROP COLUMN created_at;
+ALTER TABLE committees DROP COLUMN updated_at;
@@ -0,0 +1,14 @@
+-- This file is Free Software under the Apache-2.0 License
+-- without warranty, see README.md and LICENSE for details.
+--
+-- SPDX-License-Identifier: Apache-2.0
+--
+-- SPDX-FileCopyrightText: 2025 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+-- Software-Engineering: 2025 Intevation GmbH <https://intevation.de>
+
+
+-- Existing rows keep NULL as their creation time is unknown.
+ALTER TABLE meetings ADD COLUMN created_at TIMESTAMP;
+ALTER TABLE meetings ADD COLUMN updated_at TIMESTAMP;
+ALTER TABLE committees ADD COLUMN created_at TIMESTAMP;
+ALTER TABLE committees ADD COLUMN updated_at TIMESTAMP;
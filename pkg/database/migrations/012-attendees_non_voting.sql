@@ -0,0 +1,11 @@
+-- This file is Free Software under the Apache-2.0 License
+-- without warranty, see README.md and LICENSE for details.
+--
+-- SPDX-License-Identifier: Apache-2.0
+--
+-- SPDX-FileCopyrightText: 2025 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+-- Software-Engineering: 2025 Intevation GmbH <https://intevation.de>
+
+
+-- Attendees which are present but do not take part in votes.
+ALTER TABLE attendees ADD COLUMN non_voting BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1,24 @@
+-- This file is Free Software under the Apache-2.0 License
+-- without warranty, see README.md and LICENSE for details.
+--
+-- SPDX-License-Identifier: Apache-2.0
+--
+-- SPDX-FileCopyrightText: 2025 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+-- Software-Engineering: 2025 Intevation GmbH <https://intevation.de>
+
+
+-- The quorum of a meeting at the time of its conclusion.
+CREATE TABLE meeting_snapshots (
+    meetings_id      INTEGER PRIMARY KEY REFERENCES meetings(id) ON DELETE CASCADE,
+    voting           INTEGER NOT NULL,
+    attending_voting INTEGER NOT NULL,
+    taken            TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- The voting members of a meeting at the time of its conclusion.
+-- No reference to users as the roster should outlive deleted users.
+CREATE TABLE meeting_snapshot_voters (
+    meetings_id INTEGER NOT NULL REFERENCES meeting_snapshots(meetings_id) ON DELETE CASCADE,
+    nickname    VARCHAR NOT NULL,
+    UNIQUE(meetings_id, nickname)
+);
@@ -0,0 +1,13 @@
+-- This file is Free Software under the Apache-2.0 License
+-- without warranty, see README.md and LICENSE for details.
+--
+-- SPDX-License-Identifier: Apache-2.0
+--
+-- SPDX-FileCopyrightText: 2025 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+-- Software-Engineering: 2025 Intevation GmbH <https://intevation.de>
+
+
+-- Stable identifiers of committees which survive renames.
+-- The column is added and filled by the fixup backfillCommitteeSlugs
+-- as the rules of the slugs are not expressible in portable SQL.
+CREATE UNIQUE INDEX committees_slug ON committees(slug);
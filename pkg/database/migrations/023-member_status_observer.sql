@@ -0,0 +1,11 @@
+-- This file is Free Software under the Apache-2.0 License
+-- without warranty, see README.md and LICENSE for details.
+--
+-- SPDX-License-Identifier: Apache-2.0
+--
+-- SPDX-FileCopyrightText: 2025 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+-- Software-Engineering: 2025 Intevation GmbH <https://intevation.de>
+
+-- Observers attend meetings but never count toward the quorum.
+INSERT INTO member_status (id, name, description) VALUES
+    (4, 'observer', 'Observer without voting rights');
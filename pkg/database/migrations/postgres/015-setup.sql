@@ -0,0 +1,222 @@
+-- This file is Free Software under the Apache-2.0 License
+-- without warranty, see README.md and LICENSE for details.
+--
+-- SPDX-License-Identifier: Apache-2.0
+--
+-- SPDX-FileCopyrightText: 2025 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+-- Software-Engineering: 2025 Intevation GmbH <https://intevation.de>
+
+
+-- PostgreSQL support starts with the schema of version 15.
+-- Later migrations have to be added here and in the SQLite
+-- migrations with the same version number.
+
+-- The queries are written in the SQLite dialect.
+CREATE FUNCTION unixepoch(t timestamptz) RETURNS bigint
+    AS $$ SELECT extract(epoch FROM t)::bigint $$
+    LANGUAGE SQL IMMUTABLE;
+
+CREATE TABLE versions (
+    version     int         PRIMARY KEY,
+    description text        NOT NULL,
+    time        timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE users (
+    nickname             VARCHAR PRIMARY KEY,
+    password             VARCHAR NOT NULL,
+    firstname            VARCHAR,
+    lastname             VARCHAR,
+    is_admin             BOOLEAN NOT NULL DEFAULT FALSE,
+    timezone             VARCHAR,
+    deactivated          TIMESTAMPTZ,
+    must_change_password BOOLEAN NOT NULL DEFAULT FALSE,
+    email                VARCHAR,
+    default_view         VARCHAR,
+    failed_logins        INTEGER NOT NULL DEFAULT 0,
+    locked_until         TIMESTAMPTZ
+);
+
+CREATE UNIQUE INDEX users_nickname_lower ON users(lower(nickname));
+
+CREATE TABLE sessions (
+    token       VARCHAR     PRIMARY KEY,
+    nickname    VARCHAR     NOT NULL REFERENCES users(nickname) ON DELETE CASCADE,
+    last_access timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    created_at  TIMESTAMPTZ
+);
+
+CREATE TABLE committees (
+    id                   SERIAL  PRIMARY KEY,
+    name                 VARCHAR NOT NULL,
+    description          VARCHAR,
+    created_at           TIMESTAMPTZ,
+    updated_at           TIMESTAMPTZ,
+    archived             BOOLEAN NOT NULL DEFAULT FALSE,
+    slug                 VARCHAR NOT NULL,
+    attendance_reminders BOOLEAN NOT NULL DEFAULT FALSE,
+    version              INTEGER NOT NULL DEFAULT 0
+);
+
+CREATE UNIQUE INDEX committees_slug ON committees(slug);
+
+CREATE TABLE committee_role (
+    id          INTEGER PRIMARY KEY,
+    name        VARCHAR NOT NULL UNIQUE,
+    description VARCHAR NOT NULL
+);
+
+INSERT INTO committee_role (id, name, description) VALUES
+    (0, 'chair', 'Committee chair'),
+    (1, 'member', 'Regular committee member'),
+    (2, 'secretary', 'Committee secretary'),
+    (3, 'staff', 'Committee staff');
+
+CREATE TABLE member_status (
+    id          INTEGER PRIMARY KEY,
+    name        VARCHAR NOT NULL UNIQUE,
+    description VARCHAR NOT NULL
+);
+
+INSERT INTO member_status (id, name, description) VALUES
+    (0, 'member', 'Regular committee member'),
+    (1, 'voting', 'Voting member'),
+    (2, 'nonevoting', 'Persistent none voting member'),
+    (3, 'nomember', 'Not a member'),
+    (4, 'observer', 'Observer without voting rights');
+
+CREATE TABLE member_history (
+    nickname      VARCHAR     NOT NULL,
+    committees_id INTEGER     NOT NULL REFERENCES committees(id) ON DELETE CASCADE,
+    status        INTEGER     NOT NULL DEFAULT 0 REFERENCES member_status(id) ON DELETE CASCADE,
+    since         TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(nickname, committees_id, since)
+);
+
+CREATE TABLE committee_roles (
+    nickname          VARCHAR NOT NULL REFERENCES users(nickname)    ON DELETE CASCADE,
+    committee_role_id INTEGER NOT NULL REFERENCES committee_role(id) ON DELETE CASCADE,
+    committees_id     INTEGER NOT NULL REFERENCES committees(id)     ON DELETE CASCADE,
+    UNIQUE(nickname, committee_role_id, committees_id)
+);
+
+CREATE TABLE meeting_status (
+    id          INTEGER PRIMARY KEY,
+    name        VARCHAR NOT NULL UNIQUE,
+    description VARCHAR
+);
+
+INSERT INTO meeting_status (id, name, description) VALUES
+    (0, 'onhold',  'Waiting to get started or paused'),
+    (1, 'running', 'In progress'),
+    (2, 'concluded', 'Finalized');
+
+CREATE TABLE meetings (
+    id            SERIAL      PRIMARY KEY,
+    committees_id INTEGER     NOT NULL REFERENCES committees(id) ON DELETE CASCADE,
+    gathering     BOOLEAN     NOT NULL DEFAULT FALSE,
+    status        INTEGER     NOT NULL DEFAULT 0 REFERENCES meeting_status(id) ON DELETE CASCADE, -- on hold
+    start_time    TIMESTAMPTZ NOT NULL,
+    stop_time     TIMESTAMPTZ NOT NULL,
+    description   VARCHAR,
+    agenda        VARCHAR,
+    created_at    TIMESTAMPTZ,
+    updated_at    TIMESTAMPTZ,
+    concluded_at  TIMESTAMPTZ,
+    version       INTEGER     NOT NULL DEFAULT 0,
+    UNIQUE(committees_id, start_time),
+    CHECK (start_time <= stop_time)
+);
+
+CREATE TABLE attendees (
+    meetings_id    INTEGER NOT NULL REFERENCES meetings(id)    ON DELETE CASCADE,
+    nickname       VARCHAR NOT NULL REFERENCES users(nickname) ON DELETE CASCADE,
+    voting_allowed BOOLEAN NOT NULL DEFAULT FALSE,
+    non_voting     BOOLEAN NOT NULL DEFAULT FALSE,
+    UNIQUE(meetings_id, nickname)
+);
+
+CREATE TABLE attendees_changes (
+    time        TIMESTAMPTZ NOT NULL,
+    meetings_id INTEGER     NOT NULL REFERENCES meetings(id) ON DELETE CASCADE,
+    nickname    VARCHAR     NOT NULL REFERENCES users(nickname) ON DELETE CASCADE,
+    UNIQUE(meetings_id, nickname)
+);
+
+CREATE FUNCTION attendees_changed() RETURNS trigger AS $$
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        INSERT INTO attendees_changes (time, meetings_id, nickname)
+        VALUES (CURRENT_TIMESTAMP, OLD.meetings_id, OLD.nickname)
+        ON CONFLICT (meetings_id, nickname) DO UPDATE SET time = CURRENT_TIMESTAMP;
+        RETURN OLD;
+    END IF;
+    INSERT INTO attendees_changes (time, meetings_id, nickname)
+    VALUES (CURRENT_TIMESTAMP, NEW.meetings_id, NEW.nickname)
+    ON CONFLICT (meetings_id, nickname) DO UPDATE SET time = CURRENT_TIMESTAMP;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER attendees_changes_after_change
+AFTER INSERT OR UPDATE OR DELETE ON attendees
+FOR EACH ROW EXECUTE FUNCTION attendees_changed();
+
+INSERT INTO users (nickname, password, lastname, is_admin)
+    VALUES ('admin', {{ generatePassword "admin" | sqlQuote }}, 'Administrator', true);
+
+CREATE TABLE absent_status (
+    id          INTEGER PRIMARY KEY,
+    name        VARCHAR NOT NULL UNIQUE,
+    description VARCHAR NOT NULL
+);
+
+INSERT INTO absent_status (id, name, description) VALUES
+    (0, 'requested', 'Requested by the member'),
+    (1, 'approved', 'Approved excused absent'),
+    (2, 'rejected', 'Rejected excused absent');
+
+CREATE TABLE member_absent (
+    nickname      VARCHAR     NOT NULL REFERENCES users(nickname) ON DELETE CASCADE,
+    start_time    TIMESTAMPTZ NOT NULL,
+    stop_time     TIMESTAMPTZ NOT NULL,
+    committee_id  INTEGER     NOT NULL REFERENCES committees(id)  ON DELETE CASCADE,
+    status        INTEGER     NOT NULL DEFAULT 1 REFERENCES absent_status(id), -- approved
+    CHECK (start_time < stop_time),
+    UNIQUE (nickname, committee_id, start_time)
+);
+
+CREATE TABLE meeting_documents (
+    id           SERIAL      PRIMARY KEY,
+    meetings_id  INTEGER     NOT NULL REFERENCES meetings(id)    ON DELETE CASCADE,
+    filename     VARCHAR     NOT NULL,
+    content_type VARCHAR     NOT NULL,
+    uploaded_by  VARCHAR              REFERENCES users(nickname) ON DELETE SET NULL,
+    uploaded     TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE meeting_snapshots (
+    meetings_id      INTEGER     PRIMARY KEY REFERENCES meetings(id) ON DELETE CASCADE,
+    voting           INTEGER     NOT NULL,
+    attending_voting INTEGER     NOT NULL,
+    taken            TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE meeting_snapshot_voters (
+    meetings_id INTEGER NOT NULL REFERENCES meeting_snapshots(meetings_id) ON DELETE CASCADE,
+    nickname    VARCHAR NOT NULL,
+    UNIQUE(meetings_id, nickname)
+);
+
+CREATE TABLE meeting_expected (
+    meetings_id INTEGER NOT NULL REFERENCES meetings(id)    ON DELETE CASCADE,
+    nickname    VARCHAR NOT NULL REFERENCES users(nickname) ON DELETE CASCADE,
+    UNIQUE(meetings_id, nickname)
+);
+
+CREATE TABLE attendance_reminders (
+    meetings_id INTEGER     NOT NULL REFERENCES meetings(id)    ON DELETE CASCADE,
+    nickname    VARCHAR     NOT NULL REFERENCES users(nickname) ON DELETE CASCADE,
+    sent        TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(meetings_id, nickname)
+);
@@ -0,0 +1,20 @@
+-- This file is Free Software under the Apache-2.0 License
+-- without warranty, see README.md and LICENSE for details.
+--
+-- SPDX-License-Identifier: Apache-2.0
+--
+-- SPDX-FileCopyrightText: 2025 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+-- Software-Engineering: 2025 Intevation GmbH <https://intevation.de>
+
+-- Email addresses of the users as the nicknames are not reliably ones.
+ALTER TABLE users ADD COLUMN email VARCHAR;
+
+-- Committees opt in to remind their members to mark their attendance.
+ALTER TABLE committees ADD COLUMN attendance_reminders BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE TABLE attendance_reminders (
+    meetings_id INTEGER     NOT NULL REFERENCES meetings(id)    ON DELETE CASCADE,
+    nickname    VARCHAR     NOT NULL REFERENCES users(nickname) ON DELETE CASCADE,
+    sent        TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(meetings_id, nickname)
+);
@@ -0,0 +1,11 @@
+-- This file is Free Software under the Apache-2.0 License
+-- without warranty, see README.md and LICENSE for details.
+--
+-- SPDX-License-Identifier: Apache-2.0
+--
+-- SPDX-FileCopyrightText: 2025 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+-- Software-Engineering: 2025 Intevation GmbH <https://intevation.de>
+
+-- Versions detect concurrent edits of the same record.
+ALTER TABLE committees ADD COLUMN version INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE meetings ADD COLUMN version INTEGER NOT NULL DEFAULT 0;
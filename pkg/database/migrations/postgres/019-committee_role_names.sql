@@ -0,0 +1,14 @@
+-- This file is Free Software under the Apache-2.0 License
+-- without warranty, see README.md and LICENSE for details.
+--
+-- SPDX-License-Identifier: Apache-2.0
+--
+-- SPDX-FileCopyrightText: 2025 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+-- Software-Engineering: 2025 Intevation GmbH <https://intevation.de>
+
+
+-- The names of the chair and member roles were swapped
+-- compared to the role ids used by the application.
+UPDATE committee_role SET name = 'swapping' WHERE id = 0;
+UPDATE committee_role SET name = 'member', description = 'Regular committee member' WHERE id = 1;
+UPDATE committee_role SET name = 'chair', description = 'Committee chair' WHERE id = 0;
@@ -0,0 +1,13 @@
+-- This file is Free Software under the Apache-2.0 License
+-- without warranty, see README.md and LICENSE for details.
+--
+-- SPDX-License-Identifier: Apache-2.0
+--
+-- SPDX-FileCopyrightText: 2025 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+-- Software-Engineering: 2025 Intevation GmbH <https://intevation.de>
+
+
+-- Nicknames only differing in case would be ambiguous at login.
+-- Users with such nicknames are merged by the fixup foldNicknames
+-- before the index is created.
+CREATE UNIQUE INDEX users_nickname_lower ON users(lower(nickname));
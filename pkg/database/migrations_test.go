@@ -0,0 +1,113 @@
+// This file is Free Software under the Apache-2.0 License
+// without warranty, see README.md and LICENSE for details.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileCopyrightText: 2025 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2025 Intevation GmbH <https://intevation.de>
+
+package database
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/csaf-auxiliary/oasis-quorum-calculator/pkg/config"
+)
+
+// hasColumn checks if the table has the given column.
+func hasColumn(t *testing.T, db *Database, table, column string) bool {
+	t.Helper()
+	var n int
+	if err := db.DB.QueryRow(
+		`SELECT count(*) FROM pragma_table_info(?) WHERE name = ?`, table, column,
+	).Scan(&n); err != nil {
+		t.Fatal(err)
+	}
+	return n > 0
+}
+
+// checkVersion checks the version of the database.
+func checkVersion(t *testing.T, db *Database, want int64) {
+	t.Helper()
+	version, err := currentVersion(context.Background(), db.DB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if version != want {
+		t.Errorf("got version %d, want %d", version, want)
+	}
+}
+
+func TestRollback(t *testing.T) {
+	ctx := context.Background()
+	db, err := NewDatabase(ctx, &config.Database{
+		DatabaseURL:        filepath.Join(t.TempDir(), "oqcd.sqlite"),
+		Driver:             "sqlite3",
+		Migrate:            true,
+		MaxOpenConnections: 1,
+		MaxIdleConnections: 1,
+	})
+	if err != nil {
+		t.Fatalf("creating database failed: %v", err)
+	}
+	defer db.DB.Close()
+	migs, err := listMigrations("migrations")
+	if err != nil {
+		t.Fatal(err)
+	}
+	latest := migs[len(migs)-1].version
+
+	// Columns added by 021-user_lockout and 025-meeting_concluded_at.
+	columns := []struct {
+		version       int64
+		table, column string
+	}{
+		{21, "users", "locked_until"},
+		{25, "meetings", "concluded_at"},
+	}
+	checkColumns := func(version int64) {
+		t.Helper()
+		for _, c := range columns {
+			if got, want := hasColumn(t, db, c.table, c.column), c.version <= version; got != want {
+				t.Errorf("version %d: column %s.%s exists: %t, want %t",
+					version, c.table, c.column, got, want)
+			}
+		}
+	}
+	checkVersion(t, db, latest)
+	checkColumns(latest)
+
+	for _, version := range []int64{24, 20} {
+		if err := db.Rollback(ctx, version); err != nil {
+			t.Fatalf("rolling back to %d failed: %v", version, err)
+		}
+		checkVersion(t, db, version)
+		checkColumns(version)
+	}
+
+	// Rolling back to the current or a newer version does nothing.
+	for _, version := range []int64{20, latest} {
+		if err := db.Rollback(ctx, version); err != nil {
+			t.Errorf("rolling back to %d failed: %v", version, err)
+		}
+		checkVersion(t, db, 20)
+	}
+
+	// Migrations without down scripts and negative versions cannot be reverted.
+	for _, version := range []int64{3, -1} {
+		if err := db.Rollback(ctx, version); err == nil {
+			t.Errorf("rolling back to %d succeeded", version)
+		}
+		checkVersion(t, db, 20)
+		checkColumns(20)
+	}
+
+	// Migrating again restores the schema.
+	if err := db.applyMigrations(ctx, db.cfg, migs); err != nil {
+		t.Fatalf("migrating failed: %v", err)
+	}
+	checkVersion(t, db, latest)
+	checkColumns(latest)
+}
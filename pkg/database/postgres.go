@@ -0,0 +1,179 @@
+// This file is Free Software under the Apache-2.0 License
+// without warranty, see README.md and LICENSE for details.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileCopyrightText: 2025 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2025 Intevation GmbH <https://intevation.de>
+
+package database
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"strconv"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// The queries of the models are written in the SQLite dialect.
+// For PostgreSQL they are rewritten on the fly at the driver level:
+// Placeholders '?' become '$n' and LIKE becomes ILIKE as LIKE
+// is case insensitive in SQLite. unixepoch is provided as a
+// function by the PostgreSQL setup migration.
+
+// postgresRebind rewrites a query from the SQLite dialect to PostgreSQL.
+// String literals and quoted identifiers are left untouched.
+func postgresRebind(query string) string {
+	var (
+		b     strings.Builder
+		n     int
+		quote byte
+	)
+	b.Grow(len(query) + 8)
+	for i := 0; i < len(query); i++ {
+		c := query[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		case c == '?':
+			n++
+			b.WriteByte('$')
+			b.WriteString(strconv.Itoa(n))
+			continue
+		case c == 'L' && strings.HasPrefix(query[i:], "LIKE") &&
+			(i == 0 || !isIdentChar(query[i-1])) &&
+			(i+4 == len(query) || !isIdentChar(query[i+4])):
+			b.WriteString("ILIKE")
+			i += len("LIKE") - 1
+			continue
+		}
+		b.WriteByte(c)
+	}
+	return b.String()
+}
+
+func isIdentChar(c byte) bool {
+	return c == '_' ||
+		'a' <= c && c <= 'z' ||
+		'A' <= c && c <= 'Z' ||
+		'0' <= c && c <= '9'
+}
+
+// openPostgres opens a connection pool to a PostgreSQL database
+// which accepts queries in the SQLite dialect.
+func openPostgres(ctx context.Context, url string) (*sqlx.DB, error) {
+	connector, err := pq.NewConnector(url)
+	if err != nil {
+		return nil, err
+	}
+	db := sqlx.NewDb(sql.OpenDB(rebindConnector{connector}), "postgres")
+	if err := db.PingContext(ctx); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return db, nil
+}
+
+// rebindConnector wraps the connections of a connector
+// to rewrite the queries.
+type rebindConnector struct {
+	driver.Connector
+}
+
+// rebindConn rewrites the queries before passing them
+// to the wrapped connection.
+type rebindConn struct {
+	driver.Conn
+}
+
+// Connect implements [driver.Connector].
+func (rc rebindConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := rc.Connector.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return rebindConn{conn}, nil
+}
+
+// Prepare implements [driver.Conn].
+func (rc rebindConn) Prepare(query string) (driver.Stmt, error) {
+	return rc.Conn.Prepare(postgresRebind(query))
+}
+
+// PrepareContext implements [driver.ConnPrepareContext].
+func (rc rebindConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	if pc, ok := rc.Conn.(driver.ConnPrepareContext); ok {
+		return pc.PrepareContext(ctx, postgresRebind(query))
+	}
+	return rc.Prepare(query)
+}
+
+// BeginTx implements [driver.ConnBeginTx].
+func (rc rebindConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	if bt, ok := rc.Conn.(driver.ConnBeginTx); ok {
+		return bt.BeginTx(ctx, opts)
+	}
+	return rc.Conn.Begin() //nolint:staticcheck // Fallback for old drivers.
+}
+
+// ExecContext implements [driver.ExecerContext].
+// Queries without arguments like migration scripts are passed unchanged.
+func (rc rebindConn) ExecContext(
+	ctx context.Context,
+	query string,
+	args []driver.NamedValue,
+) (driver.Result, error) {
+	ec, ok := rc.Conn.(driver.ExecerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	if len(args) > 0 {
+		query = postgresRebind(query)
+	}
+	return ec.ExecContext(ctx, query, args)
+}
+
+// QueryContext implements [driver.QueryerContext].
+func (rc rebindConn) QueryContext(
+	ctx context.Context,
+	query string,
+	args []driver.NamedValue,
+) (driver.Rows, error) {
+	qc, ok := rc.Conn.(driver.QueryerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	return qc.QueryContext(ctx, postgresRebind(query), args)
+}
+
+// Ping implements [driver.Pinger].
+func (rc rebindConn) Ping(ctx context.Context) error {
+	if p, ok := rc.Conn.(driver.Pinger); ok {
+		return p.Ping(ctx)
+	}
+	return nil
+}
+
+// ResetSession implements [driver.SessionResetter].
+func (rc rebindConn) ResetSession(ctx context.Context) error {
+	if sr, ok := rc.Conn.(driver.SessionResetter); ok {
+		return sr.ResetSession(ctx)
+	}
+	return nil
+}
+
+// IsValid implements [driver.Validator].
+func (rc rebindConn) IsValid() bool {
+	if v, ok := rc.Conn.(driver.Validator); ok {
+		return v.IsValid()
+	}
+	return true
+}
@@ -0,0 +1,141 @@
+// This file is Free Software under the Apache-2.0 License
+// without warranty, see README.md and LICENSE for details.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileCopyrightText: 2025 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2025 Intevation GmbH <https://intevation.de>
+
+package database
+
+import (
+	"context"
+	"net/url"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/csaf-auxiliary/oasis-quorum-calculator/pkg/config"
+	"github.com/csaf-auxiliary/oasis-quorum-calculator/pkg/misc"
+)
+
+// postgresDSNEnv names the environment variable with the connection URL
+// of a PostgreSQL database used by the integration tests.
+// The tests are skipped if it is not set.
+const postgresDSNEnv = "OQC_TEST_POSTGRES"
+
+func TestPostgresRebind(t *testing.T) {
+	for _, tc := range []struct {
+		query, want string
+	}{
+		{`SELECT 1`, `SELECT 1`},
+		{`SELECT * FROM t WHERE a = ? AND b = ?`, `SELECT * FROM t WHERE a = $1 AND b = $2`},
+		{`SELECT '?' FROM t WHERE a = ?`, `SELECT '?' FROM t WHERE a = $1`},
+		{`SELECT "a?" FROM t WHERE a = ?`, `SELECT "a?" FROM t WHERE a = $1`},
+		{`WHERE name LIKE ? ESCAPE '\'`, `WHERE name ILIKE $1 ESCAPE '\'`},
+		{`WHERE name NOT LIKE 'LIKE'`, `WHERE name NOT ILIKE 'LIKE'`},
+		{`SELECT LIKELY, UNLIKE FROM t`, `SELECT LIKELY, UNLIKE FROM t`},
+	} {
+		if got := postgresRebind(tc.query); got != tc.want {
+			t.Errorf("%q: got %q, want %q", tc.query, got, tc.want)
+		}
+	}
+}
+
+// newPostgresDatabase creates the schema in a fresh PostgreSQL
+// schema which is dropped at the end of the test.
+func newPostgresDatabase(t *testing.T) *Database {
+	t.Helper()
+	dsn := os.Getenv(postgresDSNEnv)
+	if dsn == "" {
+		t.Skipf("%s not set", postgresDSNEnv)
+	}
+	ctx := context.Background()
+	admin, err := openPostgres(ctx, dsn)
+	if err != nil {
+		t.Fatalf("connecting to PostgreSQL failed: %v", err)
+	}
+	t.Cleanup(func() { admin.Close() })
+	schema := "oqc_test_" + misc.RandomStringFrom(8, "abcdefghijklmnopqrstuvwxyz")
+	if _, err := admin.ExecContext(ctx, `CREATE SCHEMA `+schema); err != nil {
+		t.Fatalf("creating schema failed: %v", err)
+	}
+	t.Cleanup(func() {
+		if _, err := admin.ExecContext(ctx, `DROP SCHEMA `+schema+` CASCADE`); err != nil {
+			t.Errorf("dropping schema failed: %v", err)
+		}
+	})
+	u, err := url.Parse(dsn)
+	if err != nil {
+		t.Fatalf("invalid %s: %v", postgresDSNEnv, err)
+	}
+	query := u.Query()
+	query.Set("search_path", schema)
+	u.RawQuery = query.Encode()
+	db, err := NewDatabase(ctx, &config.Database{
+		DatabaseURL:        u.String(),
+		Driver:             "postgres",
+		Migrate:            true,
+		MaxOpenConnections: 2,
+		MaxIdleConnections: 2,
+	})
+	if err != nil {
+		t.Fatalf("creating database failed: %v", err)
+	}
+	t.Cleanup(func() { db.DB.Close() })
+	return db
+}
+
+func TestPostgres(t *testing.T) {
+	db := newPostgresDatabase(t)
+	ctx := context.Background()
+
+	for _, stmt := range []string{
+		`INSERT INTO committees (id, name, slug) VALUES (1, 'Technical Committee', 'tc')`,
+		`INSERT INTO users (nickname, password) VALUES ('alice', 'x')`,
+		`INSERT INTO committee_roles (nickname, committees_id, committee_role_id) VALUES ('alice', 1, 1)`,
+	} {
+		if _, err := db.DB.ExecContext(ctx, stmt); err != nil {
+			t.Fatalf("%q failed: %v", stmt, err)
+		}
+	}
+	start := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+	var id int64
+	if err := db.DB.QueryRowContext(ctx,
+		`INSERT INTO meetings (committees_id, start_time, stop_time) VALUES (?, ?, ?) RETURNING id`,
+		1, start, start.Add(time.Hour),
+	).Scan(&id); err != nil {
+		t.Fatalf("inserting meeting failed: %v", err)
+	}
+	// unixepoch and the case insensitive LIKE of SQLite.
+	var found int64
+	if err := db.DB.QueryRowContext(ctx,
+		`SELECT m.id FROM meetings m JOIN committees c ON m.committees_id = c.id `+
+			`WHERE c.name LIKE ? AND unixepoch(m.start_time) = ?`,
+		"%technical%", start.Unix(),
+	).Scan(&found); err != nil {
+		t.Fatalf("querying meeting failed: %v", err)
+	}
+	if found != id {
+		t.Errorf("found meeting %d, want %d", found, id)
+	}
+
+	// All migrations can be rolled back and applied again.
+	migs, err := listMigrations("migrations/postgres")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Rollback(ctx, migs[0].version); err != nil {
+		t.Fatalf("rolling back failed: %v", err)
+	}
+	if err := db.applyMigrations(ctx, db.cfg, migs); err != nil {
+		t.Fatalf("migrating again failed: %v", err)
+	}
+	version, err := currentVersion(ctx, db.DB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := migs[len(migs)-1].version; version != want {
+		t.Errorf("got version %d, want %d", version, want)
+	}
+}
@@ -0,0 +1,126 @@
+// This file is Free Software under the Apache-2.0 License
+// without warranty, see README.md and LICENSE for details.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileCopyrightText: 2025 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2025 Intevation GmbH <https://intevation.de>
+
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// execScript executes the statements of a migration script one by one
+// so that a failure can be traced back to the offending statement.
+// The statement is reported on a single line.
+func execScript(ctx context.Context, tx *sql.Tx, script string) error {
+	for i, stmt := range splitStatements(script) {
+		if _, err := tx.ExecContext(ctx, stmt); err != nil {
+			return fmt.Errorf("statement %d failed: %w: %s",
+				i+1, err, strings.Join(strings.Fields(stmt), " "))
+		}
+	}
+	return nil
+}
+
+// splitStatements splits a SQL script into its statements.
+// Semicolons in quotes, comments, dollar quoted bodies and
+// in the BEGIN ... END blocks of triggers do not end a statement.
+// Statements only consisting of comments are dropped.
+func splitStatements(script string) []string {
+	var (
+		stmts   []string
+		start   int
+		content bool   // Statement has more than comments and spaces.
+		first   string // First keyword of the statement.
+		trigger bool   // Statement creates a trigger.
+		depth   int    // Nesting of BEGIN/CASE ... END in triggers.
+	)
+	// mark remembers the begin of a statement at its first token.
+	mark := func(i int) {
+		if !content {
+			start, content = i, true
+		}
+	}
+	for i := 0; i < len(script); i++ {
+		switch c := script[i]; {
+		case c == '\'' || c == '"':
+			mark(i)
+			if end := strings.IndexByte(script[i+1:], c); end != -1 {
+				i += end + 1
+			} else {
+				i = len(script)
+			}
+		case c == '-' && strings.HasPrefix(script[i:], "--"):
+			if end := strings.IndexByte(script[i:], '\n'); end != -1 {
+				i += end
+			} else {
+				i = len(script)
+			}
+		case c == '/' && strings.HasPrefix(script[i:], "/*"):
+			if end := strings.Index(script[i+2:], "*/"); end != -1 {
+				i += end + 3
+			} else {
+				i = len(script)
+			}
+		case c == '$':
+			// Dollar quoting like $$ ... $$ or $tag$ ... $tag$.
+			mark(i)
+			tagEnd := strings.IndexByte(script[i+1:], '$')
+			if tagEnd == -1 || !isIdent(script[i+1:i+1+tagEnd]) {
+				continue
+			}
+			tag := script[i : i+tagEnd+2]
+			if end := strings.Index(script[i+len(tag):], tag); end != -1 {
+				i += len(tag) + end + len(tag) - 1
+			} else {
+				i = len(script)
+			}
+		case c == ';' && depth == 0:
+			if content {
+				stmts = append(stmts, script[start:i+1])
+			}
+			content, first, trigger = false, "", false
+		case isIdentChar(c) && (i == 0 || !isIdentChar(script[i-1])):
+			j := i + 1
+			for j < len(script) && isIdentChar(script[j]) {
+				j++
+			}
+			word := strings.ToUpper(script[i:j])
+			if !content {
+				first = word
+			}
+			mark(i)
+			switch {
+			case word == "TRIGGER" && first == "CREATE":
+				trigger = true
+			case trigger && (word == "BEGIN" || word == "CASE"):
+				depth++
+			case trigger && word == "END" && depth > 0:
+				depth--
+			}
+			i = j - 1
+		case c > ' ':
+			mark(i)
+		}
+	}
+	if content {
+		stmts = append(stmts, strings.TrimSpace(script[start:]))
+	}
+	return stmts
+}
+
+// isIdent checks if s is empty or an identifier without quotes.
+func isIdent(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if !isIdentChar(s[i]) {
+			return false
+		}
+	}
+	return true
+}
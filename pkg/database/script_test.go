@@ -0,0 +1,99 @@
+// This file is Free Software under the Apache-2.0 License
+// without warranty, see README.md and LICENSE for details.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileCopyrightText: 2025 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2025 Intevation GmbH <https://intevation.de>
+
+package database
+
+import (
+	"context"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+)
+
+func TestSplitStatements(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		script string
+		want   []string
+	}{
+		{"simple", "CREATE TABLE a (x INT);\nCREATE TABLE b (y INT);\n",
+			[]string{"CREATE TABLE a (x INT);", "CREATE TABLE b (y INT);"}},
+		{"no trailing semicolon", "SELECT 1;\n  SELECT 2  \n",
+			[]string{"SELECT 1;", "SELECT 2"}},
+		{"quotes", `INSERT INTO a VALUES ('x;y'); SELECT "a;b" FROM a;`,
+			[]string{`INSERT INTO a VALUES ('x;y');`, `SELECT "a;b" FROM a;`}},
+		{"escaped quotes", `INSERT INTO a VALUES ('it''s; fine');`,
+			[]string{`INSERT INTO a VALUES ('it''s; fine');`}},
+		{"comments", "-- header; with semicolon\n/* block; */\nSELECT 1; -- trailing;\n",
+			[]string{"SELECT 1;"}},
+		{"only comments", "-- nothing here;\n/* ; */\n", nil},
+		{"trigger", "CREATE TRIGGER t AFTER INSERT ON a BEGIN\n" +
+			"  UPDATE a SET x = CASE WHEN x > 0 THEN 1 ELSE 0 END;\n" +
+			"  DELETE FROM b;\nEND;\nSELECT 1;",
+			[]string{"CREATE TRIGGER t AFTER INSERT ON a BEGIN\n" +
+				"  UPDATE a SET x = CASE WHEN x > 0 THEN 1 ELSE 0 END;\n" +
+				"  DELETE FROM b;\nEND;", "SELECT 1;"}},
+		{"transaction", "BEGIN; SELECT 1; END;",
+			[]string{"BEGIN;", "SELECT 1;", "END;"}},
+		{"dollar quotes", "CREATE FUNCTION f() RETURNS int AS $$ SELECT 1; $$ LANGUAGE sql;\n" +
+			"DO $body$ BEGIN PERFORM 1; END $body$;",
+			[]string{"CREATE FUNCTION f() RETURNS int AS $$ SELECT 1; $$ LANGUAGE sql;",
+				"DO $body$ BEGIN PERFORM 1; END $body$;"}},
+		{"placeholders", "SELECT $1; SELECT 2;", []string{"SELECT $1;", "SELECT 2;"}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := splitStatements(tc.script); !slices.Equal(got, tc.want) {
+				t.Errorf("got %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestExecScriptBrokenStatement(t *testing.T) {
+	ctx := context.Background()
+	db, err := sqlx.ConnectContext(ctx, "sqlite3", filepath.Join(t.TempDir(), "test.sqlite"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = execScript(ctx, tx, "-- Broken migration.\n"+
+		"CREATE TABLE a (x INT);\n"+
+		"INSERT INTO missing\n  VALUES (1);\n"+
+		"CREATE TABLE b (y INT);\n")
+	tx.Rollback()
+	if err == nil {
+		t.Fatal("broken script succeeded")
+	}
+	msg := err.Error()
+	for _, want := range []string{
+		"statement 2 failed",
+		"no such table: missing",
+		"INSERT INTO missing VALUES (1);",
+	} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("error %q does not contain %q", msg, want)
+		}
+	}
+	// Nothing of the script is left after the rollback.
+	var tables int
+	if err := db.QueryRowContext(ctx,
+		`SELECT count(*) FROM sqlite_master WHERE type = 'table'`).Scan(&tables); err != nil {
+		t.Fatal(err)
+	}
+	if tables != 0 {
+		t.Errorf("got %d tables, want none", tables)
+	}
+}
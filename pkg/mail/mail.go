@@ -0,0 +1,102 @@
+// This file is Free Software under the Apache-2.0 License
+// without warranty, see README.md and LICENSE for details.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileCopyrightText: 2025 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2025 Intevation GmbH <https://intevation.de>
+
+// Package mail implements sending out plain text emails.
+package mail
+
+import (
+	"fmt"
+	"io"
+	"net/smtp"
+	"strings"
+	"text/template"
+)
+
+// DefaultSender is the default sender of the emails.
+const DefaultSender = "OASIS Quorum Calculator <no-reply@quorum.oasis-open.org>"
+
+// Message is a plain text email.
+type Message struct {
+	From    string
+	To      string
+	Subject string
+	Body    func(io.Writer) error
+}
+
+// ParseTemplate parses a template for mail bodies.
+// Mixed line endings are normalized to \r\n.
+func ParseTemplate(name, text string) (*template.Template, error) {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	text = strings.ReplaceAll(text, "\n", "\r\n")
+	return template.New(name).Parse(text)
+}
+
+// TemplateBody returns a body writer which executes the template with the data.
+func TemplateBody(tmpl *template.Template, data any) func(io.Writer) error {
+	return func(w io.Writer) error {
+		return tmpl.Execute(w, data)
+	}
+}
+
+// write writes the headers and the body of the message.
+func (m *Message) write(w io.Writer) error {
+	fmt.Fprintf(w, "To: %s\r\n", m.To)
+	fmt.Fprintf(w, "From: %s\r\n", m.From)
+	fmt.Fprintf(w, "Subject: %s\r\n", m.Subject)
+	fmt.Fprint(w, "MIME-Version: 1.0\r\n")
+	fmt.Fprint(w, "Content-Transfer-Encoding: 8bit\r\n")
+	fmt.Fprint(w, "Content-Type: text/plain; charset=\"UTF-8\"\r\n")
+	fmt.Fprint(w, "\r\n")
+	if err := m.Body(w); err != nil {
+		return err
+	}
+	_, err := fmt.Fprint(w, "\r\n")
+	return err
+}
+
+// Send sends the message via the SMTP server at host (host:port).
+func Send(host string, msg *Message) error {
+	c, err := smtp.Dial(host)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+
+	// Set the sender and recipient first
+	if err := c.Mail(extractAddress(msg.From)); err != nil {
+		return err
+	}
+	if err := c.Rcpt(extractAddress(msg.To)); err != nil {
+		return err
+	}
+
+	// Send the email body.
+	wc, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if err := msg.write(wc); err != nil {
+		return err
+	}
+	if err = wc.Close(); err != nil {
+		return err
+	}
+
+	// Send the QUIT command and close the connection.
+	return c.Quit()
+}
+
+// extractAddress returns the bare address of "Name <address>".
+func extractAddress(s string) string {
+	if start := strings.LastIndexByte(s, '<'); start != -1 {
+		if end := strings.IndexByte(s[start:], '>'); end != -1 {
+			return s[start+1 : start+end]
+		}
+	}
+	return s
+}
@@ -0,0 +1,108 @@
+// This file is Free Software under the Apache-2.0 License
+// without warranty, see README.md and LICENSE for details.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileCopyrightText: 2025 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2025 Intevation GmbH <https://intevation.de>
+
+package mail
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/csaf-auxiliary/oasis-quorum-calculator/pkg/config"
+	"github.com/csaf-auxiliary/oasis-quorum-calculator/pkg/database"
+	"github.com/csaf-auxiliary/oasis-quorum-calculator/pkg/models"
+)
+
+// Reminder reminds members by email to mark their
+// attendance at running meetings.
+type Reminder struct {
+	cfg       *config.Config
+	db        *database.Database
+	templates *Templates
+}
+
+// NewReminder creates a new reminder.
+func NewReminder(cfg *config.Config, db *database.Database, templates *Templates) *Reminder {
+	return &Reminder{
+		cfg:       cfg,
+		db:        db,
+		templates: templates,
+	}
+}
+
+// Run sends the due attendance reminders on a schedule.
+// It does nothing if no SMTP host is configured.
+func (r *Reminder) Run(ctx context.Context) {
+	if r.cfg.Mail.SMTPHost == "" {
+		return
+	}
+	ticker := time.NewTicker(r.cfg.Mail.ReminderInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case t := <-ticker.C:
+			r.remind(ctx, t)
+		}
+	}
+}
+
+// remind sends the attendance reminders due at the given time.
+func (r *Reminder) remind(ctx context.Context, now time.Time) {
+	reminders, err := models.DueAttendanceReminders(ctx, r.db, r.cfg.Mail.ReminderDelay, now)
+	if err != nil {
+		slog.ErrorContext(ctx, "loading attendance reminders failed", "error", err)
+		return
+	}
+	for _, reminder := range reminders {
+		recipient := reminder.Recipient()
+		if recipient == "" {
+			continue
+		}
+		if err := r.send(reminder, recipient); err != nil {
+			slog.ErrorContext(ctx, "sending attendance reminder failed",
+				"nickname", reminder.Nickname,
+				"meeting", reminder.MeetingID,
+				"error", err)
+			continue
+		}
+		if err := models.MarkAttendanceReminded(
+			ctx, r.db, reminder.MeetingID, reminder.Nickname,
+		); err != nil {
+			slog.ErrorContext(ctx, "storing attendance reminder failed", "error", err)
+			return
+		}
+		slog.DebugContext(ctx, "attendance reminder sent",
+			"nickname", reminder.Nickname,
+			"meeting", reminder.MeetingID)
+	}
+}
+
+// send sends the reminder email to a member.
+func (r *Reminder) send(reminder *models.AttendanceReminder, recipient string) error {
+	startTime := reminder.StartTime
+	if loc, err := time.LoadLocation(reminder.PreferredTimezone()); err == nil {
+		startTime = startTime.In(loc)
+	}
+	data := struct {
+		CommitteeName string
+		StartTime     string
+		BaseURL       string
+	}{
+		CommitteeName: reminder.CommitteeName,
+		StartTime:     startTime.Format("2006-01-02 15:04 MST"),
+		BaseURL:       r.cfg.Mail.BaseURL,
+	}
+	return Send(r.cfg.Mail.SMTPHost, &Message{
+		From:    r.cfg.Mail.From,
+		To:      recipient,
+		Subject: "OQC - OASIS Quorum Calculator: Please mark your attendance",
+		Body:    TemplateBody(r.templates.Lookup(ReminderTemplate, reminder.CommitteeSlug), data),
+	})
+}
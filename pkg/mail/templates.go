@@ -0,0 +1,134 @@
+// This file is Free Software under the Apache-2.0 License
+// without warranty, see README.md and LICENSE for details.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileCopyrightText: 2025 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2025 Intevation GmbH <https://intevation.de>
+
+package mail
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"text/template"
+)
+
+// Names of the email templates.
+const (
+	AccountTemplate  = "account"
+	ReminderTemplate = "reminder"
+)
+
+// templateExt is the file extension of the email templates.
+const templateExt = ".txt"
+
+const accountTxt = `Dear OASIS {{ .TCName }} TC member,
+
+an account was created for you at the OQC ({{ .BaseURL }}).
+
+username: {{ .Username }}
+initial password: {{ .Password }}
+
+Please change your initial password.
+
+Kind regards,
+Your OQC Tool`
+
+const reminderTxt = `Dear OASIS {{ .CommitteeName }} member,
+
+the meeting of {{ .CommitteeName }} which started at
+{{ .StartTime }} is running
+and you have not marked your attendance yet.
+{{ if .BaseURL }}
+Please mark your attendance at {{ .BaseURL }}
+{{ end }}
+Kind regards,
+Your OQC Tool`
+
+// defaultTemplates are the texts of the built-in email templates.
+var defaultTemplates = map[string]string{
+	AccountTemplate:  accountTxt,
+	ReminderTemplate: reminderTxt,
+}
+
+// Templates are the email templates. The built-in templates can be
+// replaced by files named after the templates with a ".txt" extension
+// in a directory. Templates in sub directories named after the short
+// code of a committee are only used for this committee.
+type Templates struct {
+	defaults   map[string]*template.Template
+	committees map[string]map[string]*template.Template
+}
+
+// LoadTemplates loads the email templates from the given directory.
+// An empty directory results in the built-in templates.
+func LoadTemplates(dir string) (*Templates, error) {
+	ts := &Templates{
+		defaults:   make(map[string]*template.Template, len(defaultTemplates)),
+		committees: map[string]map[string]*template.Template{},
+	}
+	for name, text := range defaultTemplates {
+		tmpl, err := ParseTemplate(name, text)
+		if err != nil {
+			return nil, fmt.Errorf("parsing built-in email template %q failed: %w", name, err)
+		}
+		ts.defaults[name] = tmpl
+	}
+	if dir == "" {
+		return ts, nil
+	}
+	if err := loadTemplateFiles(dir, ts.defaults); err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("reading email templates failed: %w", err)
+	}
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		committee := map[string]*template.Template{}
+		if err := loadTemplateFiles(filepath.Join(dir, entry.Name()), committee); err != nil {
+			return nil, err
+		}
+		if len(committee) > 0 {
+			ts.committees[entry.Name()] = committee
+		}
+	}
+	return ts, nil
+}
+
+// loadTemplateFiles parses the template files of the known
+// templates found in a directory and stores them in templates.
+func loadTemplateFiles(dir string, templates map[string]*template.Template) error {
+	for name := range defaultTemplates {
+		path := filepath.Join(dir, name+templateExt)
+		text, err := os.ReadFile(path)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("reading email template failed: %w", err)
+		}
+		tmpl, err := ParseTemplate(name, string(text))
+		if err != nil {
+			return fmt.Errorf("parsing email template %q failed: %w", path, err)
+		}
+		templates[name] = tmpl
+	}
+	return nil
+}
+
+// Lookup returns the template with the given name for a committee.
+// If the committee has no template of its own the default one is returned.
+func (ts *Templates) Lookup(name, committee string) *template.Template {
+	if tmpl := ts.committees[committee][name]; tmpl != nil {
+		return tmpl
+	}
+	return ts.defaults[name]
+}
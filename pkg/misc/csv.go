@@ -0,0 +1,71 @@
+// This file is Free Software under the Apache-2.0 License
+// without warranty, see README.md and LICENSE for details.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileCopyrightText: 2025 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2025 Intevation GmbH <https://intevation.de>
+
+package misc
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"io"
+)
+
+// utf8BOM is the byte order mark some spreadsheet programs
+// put in front of UTF-8 encoded files.
+var utf8BOM = []byte{0xef, 0xbb, 0xbf}
+
+// csvDelimiters are the delimiters recognized by NewCSVReader.
+var csvDelimiters = []byte{',', ';', '\t'}
+
+// NewCSVReader returns a CSV reader which skips a leading UTF-8 BOM
+// and detects the delimiter by looking at the first line.
+// Delimiters are recognized outside of quoted fields and
+// the one found most often wins. Commas are the fallback.
+func NewCSVReader(r io.Reader) (*csv.Reader, error) {
+	br := bufio.NewReader(r)
+	if bom, err := br.Peek(len(utf8BOM)); err == nil && bytes.Equal(bom, utf8BOM) {
+		br.Discard(len(utf8BOM))
+	}
+	// The first line may be longer than the buffer. Only look at what fits.
+	first, err := br.Peek(br.Size())
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, bufio.ErrBufferFull) {
+		return nil, err
+	}
+	cr := csv.NewReader(br)
+	cr.Comma = sniffDelimiter(first)
+	return cr, nil
+}
+
+// sniffDelimiter counts the delimiters in the first line
+// and returns the most frequent one.
+func sniffDelimiter(data []byte) rune {
+	var (
+		counts [256]int
+		quoted bool
+	)
+scan:
+	for _, b := range data {
+		switch {
+		case b == '"':
+			quoted = !quoted
+		case quoted:
+		case b == '\n':
+			break scan
+		default:
+			counts[b]++
+		}
+	}
+	best := byte(',')
+	for _, d := range csvDelimiters {
+		if counts[d] > counts[best] {
+			best = d
+		}
+	}
+	return rune(best)
+}
@@ -0,0 +1,76 @@
+// This file is Free Software under the Apache-2.0 License
+// without warranty, see README.md and LICENSE for details.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileCopyrightText: 2025 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2025 Intevation GmbH <https://intevation.de>
+
+package misc
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestNewCSVReader(t *testing.T) {
+	const bom = "\ufeff"
+	for _, tc := range []struct {
+		name  string
+		input string
+		comma rune
+		want  [][]string
+	}{
+		{"comma", "a,b,c\n1,2,3\n", ',',
+			[][]string{{"a", "b", "c"}, {"1", "2", "3"}}},
+		{"semicolon", "a;b;c\n1,5;2;3\n", ';',
+			[][]string{{"a", "b", "c"}, {"1,5", "2", "3"}}},
+		{"tab", "a\tb\n1\t2\n", '\t',
+			[][]string{{"a", "b"}, {"1", "2"}}},
+		{"bom", bom + "Status,Role,Name\nVoting,Chair,Alice\n", ',',
+			[][]string{{"Status", "Role", "Name"}, {"Voting", "Chair", "Alice"}}},
+		{"bom semicolon", bom + "Status;Role;Name\r\nVoting;Chair;Doe, Jane\r\n", ';',
+			[][]string{{"Status", "Role", "Name"}, {"Voting", "Chair", "Doe, Jane"}}},
+		{"quoted delimiters", "\"a;b;c\",d\n1,2\n", ',',
+			[][]string{{"a;b;c", "d"}, {"1", "2"}}},
+		{"delimiters in later lines", "a\n1;2;3\n", ',',
+			[][]string{{"a"}, {"1;2;3"}}},
+		{"no delimiter", "a\n", ',', [][]string{{"a"}}},
+		{"empty", "", ',', nil},
+		{"only bom", bom, ',', nil},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			r, err := NewCSVReader(strings.NewReader(tc.input))
+			if err != nil {
+				t.Fatalf("creating reader failed: %v", err)
+			}
+			if r.Comma != tc.comma {
+				t.Errorf("got delimiter %q, want %q", r.Comma, tc.comma)
+			}
+			got, err := r.ReadAll()
+			if err != nil {
+				t.Fatalf("reading failed: %v", err)
+			}
+			if !slices.EqualFunc(got, tc.want, slices.Equal) {
+				t.Errorf("got %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestNewCSVReaderLongLine(t *testing.T) {
+	// The header is longer than the sniffing buffer.
+	header := strings.Repeat("x;", 5000) + "x"
+	r, err := NewCSVReader(strings.NewReader(header + "\n"))
+	if err != nil {
+		t.Fatalf("creating reader failed: %v", err)
+	}
+	record, err := r.Read()
+	if err != nil {
+		t.Fatalf("reading failed: %v", err)
+	}
+	if len(record) != 5001 {
+		t.Errorf("got %d columns, want 5001", len(record))
+	}
+}
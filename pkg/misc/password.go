@@ -17,9 +17,14 @@ import (
 	"math/rand/v2"
 )
 
-const alphabet = "abcdefghijklmnopqrstuvwxyz" +
-	"ABCDEFGHIJKLMNOPQRSTUVWXYZ" +
-	"0123456789"
+// Alphabets to be used with [RandomStringFrom].
+const (
+	LowerCaseLetters = "abcdefghijklmnopqrstuvwxyz"
+	UpperCaseLetters = "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+	Digits           = "0123456789"
+	Symbols          = "!#$%&()*+,-./:;<=>?@[]^_{|}~"
+	AlphaNumeric     = LowerCaseLetters + UpperCaseLetters + Digits
+)
 
 type cryptoSource struct{}
 
@@ -29,12 +34,27 @@ func (cryptoSource) Uint64() uint64 {
 	return binary.NativeEndian.Uint64(cs[:])
 }
 
-// RandomString generates a random string of length n.
+// RandomString generates a random alphanumeric string of length n.
 func RandomString(n int) string {
+	return RandomStringFrom(n, AlphaNumeric)
+}
+
+// RandomStringFrom generates a random string of length n
+// with characters drawn from the given alphabet.
+// The characters are uniformly distributed as [rand.Rand.IntN]
+// rejects the samples of the crypto source which would
+// introduce a modulo bias for alphabets whose length
+// is not a power of two.
+// It panics if the alphabet is empty.
+func RandomStringFrom(n int, alphabet string) string {
+	chars := []rune(alphabet)
+	if len(chars) == 0 {
+		panic("empty alphabet")
+	}
 	rnd := rand.New(cryptoSource{})
-	out := make([]byte, n)
+	out := make([]rune, n)
 	for i := range out {
-		out[i] = alphabet[rnd.IntN(len(alphabet))]
+		out[i] = chars[rnd.IntN(len(chars))]
 	}
 	return string(out)
 }
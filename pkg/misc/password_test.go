@@ -0,0 +1,63 @@
+// This file is Free Software under the Apache-2.0 License
+// without warranty, see README.md and LICENSE for details.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileCopyrightText: 2025 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2025 Intevation GmbH <https://intevation.de>
+
+package misc
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestRandomStringFrom(t *testing.T) {
+	// Multibyte and a length which is not a power of two.
+	const alphabet = "abcäöü€!?"
+	counts := map[rune]int{}
+	const samples, n = 1000, 9
+	for range samples {
+		s := RandomStringFrom(n, alphabet)
+		if c := utf8.RuneCountInString(s); c != n {
+			t.Fatalf("%q has %d runes, want %d", s, c, n)
+		}
+		for _, r := range s {
+			if !strings.ContainsRune(alphabet, r) {
+				t.Fatalf("%q contains %q not in the alphabet", s, r)
+			}
+			counts[r]++
+		}
+	}
+	// Each of the 9 characters is expected 1000 times.
+	// Missing or far off ones hint at a broken distribution.
+	for _, r := range alphabet {
+		if c := counts[r]; c < 700 || c > 1300 {
+			t.Errorf("%q drawn %d times, expected about 1000", r, c)
+		}
+	}
+}
+
+func TestRandomString(t *testing.T) {
+	s := RandomString(32)
+	if len(s) != 32 {
+		t.Fatalf("got length %d, want 32", len(s))
+	}
+	if strings.Trim(s, AlphaNumeric) != "" {
+		t.Errorf("%q is not alphanumeric", s)
+	}
+	if RandomString(32) == s {
+		t.Error("two random strings are equal")
+	}
+}
+
+func TestRandomStringFromEmptyAlphabet(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("empty alphabet does not panic")
+		}
+	}()
+	RandomStringFrom(1, "")
+}
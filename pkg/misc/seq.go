@@ -70,6 +70,50 @@ func ParseSeq2[S, K, V any](seq iter.Seq[S], parse func(S) (K, V, error)) iter.S
 	}
 }
 
+// ParseSeqErrors is like [ParseSeq] but collects the errors
+// of the elements which fail to parse instead of ignoring them.
+// The sequence is consumed eagerly.
+func ParseSeqErrors[S, T any](seq iter.Seq[S], parse func(S) (T, error)) (iter.Seq[T], []error) {
+	var (
+		ts   []T
+		errs []error
+	)
+	for s := range seq {
+		if t, err := parse(s); err != nil {
+			errs = append(errs, err)
+		} else {
+			ts = append(ts, t)
+		}
+	}
+	return slices.Values(ts), errs
+}
+
+// ParseSeq2Errors is like [ParseSeq2] but collects the errors
+// of the elements which fail to parse instead of ignoring them.
+// The sequence is consumed eagerly.
+func ParseSeq2Errors[S, K, V any](seq iter.Seq[S], parse func(S) (K, V, error)) (iter.Seq2[K, V], []error) {
+	var (
+		ks   []K
+		vs   []V
+		errs []error
+	)
+	for s := range seq {
+		if k, v, err := parse(s); err != nil {
+			errs = append(errs, err)
+		} else {
+			ks = append(ks, k)
+			vs = append(vs, v)
+		}
+	}
+	return func(yield func(K, V) bool) {
+		for i, k := range ks {
+			if !yield(k, vs[i]) {
+				return
+			}
+		}
+	}, errs
+}
+
 // Attribute returns a sequence attributing the given one with a given attribute.
 func Attribute[S, A any](seq iter.Seq[S], a A) iter.Seq2[S, A] {
 	return func(yield func(S, A) bool) {
@@ -93,3 +137,45 @@ func Join2[K, V any](seqs ...iter.Seq2[K, V]) iter.Seq2[K, V] {
 		}
 	}
 }
+
+// Reduce folds a sequence into a single value by applying f
+// to an accumulator starting with init and each element.
+func Reduce[S, T any](seq iter.Seq[S], init T, f func(T, S) T) T {
+	acc := init
+	for s := range seq {
+		acc = f(acc, s)
+	}
+	return acc
+}
+
+// Count returns the number of elements of a sequence fulfilling pred.
+func Count[S any](seq iter.Seq[S], pred func(S) bool) int {
+	return Reduce(seq, 0, func(n int, s S) int {
+		if pred(s) {
+			n++
+		}
+		return n
+	})
+}
+
+// GroupBy groups the elements of a sequence by the keys
+// derived from them. The order of the elements in the
+// groups follows the order of the sequence.
+func GroupBy[K comparable, V any](seq iter.Seq[V], key func(V) K) map[K][]V {
+	groups := map[K][]V{}
+	for v := range seq {
+		k := key(v)
+		groups[k] = append(groups[k], v)
+	}
+	return groups
+}
+
+// Index maps the elements of a sequence by the keys derived
+// from them. If keys are not unique the last element wins.
+func Index[K comparable, V any](seq iter.Seq[V], key func(V) K) map[K]V {
+	index := map[K]V{}
+	for v := range seq {
+		index[key(v)] = v
+	}
+	return index
+}
@@ -0,0 +1,79 @@
+// This file is Free Software under the Apache-2.0 License
+// without warranty, see README.md and LICENSE for details.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileCopyrightText: 2025 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2025 Intevation GmbH <https://intevation.de>
+
+package misc
+
+import (
+	"maps"
+	"slices"
+	"testing"
+)
+
+func TestReduce(t *testing.T) {
+	sum := func(acc, x int) int { return acc + x }
+	if got := Reduce(Values(1, 2, 3, 4), 0, sum); got != 10 {
+		t.Errorf("sum: got %d, want 10", got)
+	}
+	if got := Reduce(Values[int](), 42, sum); got != 42 {
+		t.Errorf("empty: got %d, want 42", got)
+	}
+	concat := func(acc string, x int) string { return acc + string(rune('a'+x)) }
+	if got := Reduce(Values(0, 1, 2), ">", concat); got != ">abc" {
+		t.Errorf("order: got %q, want \">abc\"", got)
+	}
+}
+
+func TestCount(t *testing.T) {
+	even := func(x int) bool { return x%2 == 0 }
+	if got := Count(Values(1, 2, 3, 4, 6), even); got != 3 {
+		t.Errorf("got %d, want 3", got)
+	}
+	if got := Count(Values(1, 3), even); got != 0 {
+		t.Errorf("none: got %d, want 0", got)
+	}
+	if got := Count(Values[int](), even); got != 0 {
+		t.Errorf("empty: got %d, want 0", got)
+	}
+}
+
+type keyed struct {
+	key   string
+	value int
+}
+
+func TestGroupBy(t *testing.T) {
+	groups := GroupBy(Values(
+		keyed{"a", 1}, keyed{"b", 2}, keyed{"a", 3}, keyed{"a", 4},
+	), func(k keyed) string { return k.key })
+	if keys := slices.Sorted(maps.Keys(groups)); !slices.Equal(keys, []string{"a", "b"}) {
+		t.Fatalf("got keys %v, want [a b]", keys)
+	}
+	// Duplicate keys collect all elements in order.
+	if got := groups["a"]; !slices.Equal(got, []keyed{{"a", 1}, {"a", 3}, {"a", 4}}) {
+		t.Errorf("a: got %v", got)
+	}
+	if got := groups["b"]; !slices.Equal(got, []keyed{{"b", 2}}) {
+		t.Errorf("b: got %v", got)
+	}
+	if got := GroupBy(Values[keyed](), func(k keyed) string { return k.key }); len(got) != 0 {
+		t.Errorf("empty: got %v", got)
+	}
+}
+
+func TestIndex(t *testing.T) {
+	index := Index(Values(
+		keyed{"a", 1}, keyed{"b", 2}, keyed{"a", 3},
+	), func(k keyed) string { return k.key })
+	// Duplicate keys keep the last element.
+	if want := map[string]keyed{"a": {"a", 3}, "b": {"b", 2}}; !maps.Equal(index, want) {
+		t.Errorf("got %v, want %v", index, want)
+	}
+	if got := Index(Values[keyed](), func(k keyed) string { return k.key }); len(got) != 0 {
+		t.Errorf("empty: got %v", got)
+	}
+}
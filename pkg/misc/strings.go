@@ -9,13 +9,27 @@
 package misc
 
 import (
+	"net/mail"
+	"net/url"
 	"strconv"
 	"strings"
+	"unicode"
 	"unicode/utf8"
 )
 
+// shortenEllipsis is appended to shortened strings.
+const shortenEllipsis = "..."
+
 // Shorten shortens a string to max. 40 characters.
 func Shorten(v any) string {
+	return ShortenN(v, 40)
+}
+
+// ShortenN shortens a string to max. n characters including
+// the trailing ellipsis. Combining marks stay with their base
+// characters and the string is cut at a word boundary if this
+// does not remove more than half of it.
+func ShortenN(v any, n int) string {
 	var s string
 	switch x := v.(type) {
 	case *string:
@@ -29,11 +43,97 @@ func Shorten(v any) string {
 		return ""
 	}
 	s = strings.TrimSpace(s)
-	if utf8.RuneCountInString(s) > 40 {
-		runes := []rune(s)
-		return string(runes[:37]) + "..."
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	runes := []rune(s)
+	limit := n - len(shortenEllipsis)
+	if limit < 1 {
+		return string(runes[:max(n, 0)])
+	}
+	// Don't separate characters from their modifiers.
+	cut := limit
+	for cut > 0 && (isModifier(runes[cut]) || runes[cut-1] == '\u200d') {
+		cut--
+	}
+	// Prefer to cut at a word boundary.
+	if !unicode.IsSpace(runes[cut]) {
+		if idx := lastSpace(runes[:cut]); idx > limit/2 {
+			cut = idx
+		}
+	}
+	return strings.TrimRightFunc(string(runes[:cut]), unicode.IsSpace) + shortenEllipsis
+}
+
+// isModifier returns true if the rune modifies the previous one.
+func isModifier(r rune) bool {
+	return unicode.In(r, unicode.Mn, unicode.Me, unicode.Mc) || r == '\u200d'
+}
+
+// lastSpace returns the index of the last white space in runes or -1.
+func lastSpace(runes []rune) int {
+	for i := len(runes) - 1; i >= 0; i-- {
+		if unicode.IsSpace(runes[i]) {
+			return i
+		}
+	}
+	return -1
+}
+
+// SanitizeText removes the control characters except line feeds
+// and tabs from a text entered by a user. Line endings are
+// normalized and surrounding white space is trimmed.
+func SanitizeText(s string) string {
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+	s = strings.Map(func(r rune) rune {
+		if r == '\n' || r == '\t' || !unicode.IsControl(r) {
+			return r
+		}
+		return -1
+	}, s)
+	return strings.TrimSpace(s)
+}
+
+// Slugify turns a name into a lower case identifier which only
+// consists of ASCII letters, digits and single dashes.
+func Slugify(name string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(name) {
+		switch {
+		case 'a' <= r && r <= 'z', '0' <= r && r <= '9':
+			if dash && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			dash = false
+			b.WriteRune(r)
+		default:
+			dash = true
+		}
+	}
+	return b.String()
+}
+
+// ValidURL checks if s is an absolute http or https URL
+// like "https://quorum.oasis-open.org".
+func ValidURL(s string) bool {
+	u, err := url.Parse(s)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+// ValidEmail checks if s is a bare email address like "alice@example.org".
+// Display names, comments and domains without a dot are rejected.
+func ValidEmail(s string) bool {
+	addr, err := mail.ParseAddress(s)
+	if err != nil || addr.Name != "" || addr.Address != s {
+		return false
 	}
-	return s
+	at := strings.LastIndexByte(s, '@')
+	domain := s[at+1:]
+	return at > 0 &&
+		strings.Contains(domain, ".") &&
+		!strings.HasPrefix(domain, ".") &&
+		!strings.HasSuffix(domain, ".")
 }
 
 // Atoi64 is a [strconv.Atoi] like wrapper for int64s.
@@ -59,6 +159,16 @@ func EmptyString(s *string) string {
 	return *s
 }
 
+// likeEscaper escapes the wildcards of SQL LIKE patterns.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// LikeContains returns a SQL LIKE pattern which matches strings
+// containing s. The wildcards in s are escaped with a backslash
+// so the pattern has to be used with ESCAPE '\'.
+func LikeContains(s string) string {
+	return "%" + likeEscaper.Replace(s) + "%"
+}
+
 // CompareEmptyStrings compares to strings.
 func CompareEmptyStrings(a, b *string) int {
 	return strings.Compare(EmptyString(a), EmptyString(b))
@@ -0,0 +1,113 @@
+// This file is Free Software under the Apache-2.0 License
+// without warranty, see README.md and LICENSE for details.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileCopyrightText: 2025 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2025 Intevation GmbH <https://intevation.de>
+
+package misc
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestShortenN(t *testing.T) {
+	cjk := strings.Repeat("会議の議事録", 10)
+	for _, tc := range []struct {
+		name  string
+		input any
+		n     int
+		want  string
+	}{
+		{"short", "hello", 10, "hello"},
+		{"trailing spaces", "  hello   ", 5, "hello"},
+		{"trailing spaces before cut", "hello      world", 10, "hello..."},
+		{"nil", (*string)(nil), 10, ""},
+		{"no string", 42, 10, ""},
+		{"word boundary", "The quick brown fox jumps", 20, "The quick brown..."},
+		{"long word", "Supercalifragilistic expialidocious", 10, "Superca..."},
+		{"cjk", cjk, 10, "会議の議事録会..."},
+		{"cjk exact", cjk[:len("会議の議事録")], 6, "会議の議事録"},
+		{"cjk with space", "会議 の議事録会議の議事録", 10, "会議 の議事録..."},
+		{"combining mark", "cafe\u0301 cafe\u0301 cafe\u0301", 9, "cafe\u0301..."},
+		{"combining mark at cut", "abcde\u0301fgh", 8, "abcd..."},
+		{"zero width joiner", "ab\U0001F469\u200d\U0001F4BBcdefgh", 7, "ab..."},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got := ShortenN(tc.input, tc.n)
+			if got != tc.want {
+				t.Errorf("got %q, want %q", got, tc.want)
+			}
+			if !utf8.ValidString(got) {
+				t.Errorf("%q is not valid UTF-8", got)
+			}
+			if c := utf8.RuneCountInString(got); c > tc.n {
+				t.Errorf("%q has %d > %d runes", got, c, tc.n)
+			}
+		})
+	}
+}
+
+func TestShorten(t *testing.T) {
+	s := strings.Repeat("議", 50)
+	if got, want := Shorten(&s), strings.Repeat("議", 37)+"..."; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestSanitizeText(t *testing.T) {
+	for _, tc := range []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"empty", "", ""},
+		{"plain", "Budget review", "Budget review"},
+		{"surrounding space", " \t\n Budget \n", "Budget"},
+		{"line feeds and tabs", "1.\tWelcome\n2.\tBudget", "1.\tWelcome\n2.\tBudget"},
+		{"carriage returns", "Welcome\r\nBudget\rEnd", "Welcome\nBudgetEnd"},
+		{"control characters", "Wel\x00come\x07\x1b[31m\x7f", "Welcome[31m"},
+		{"c1 control characters", "Bud\u0085get\u009b", "Budget"},
+		{"only control characters", "\x00\x01\x02", ""},
+		{"unicode", "Übliches 会議 \U0001F469‍\U0001F4BB", "Übliches 会議 \U0001F469‍\U0001F4BB"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := SanitizeText(tc.input); got != tc.want {
+				t.Errorf("got %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestValidEmail(t *testing.T) {
+	for _, tc := range []struct {
+		email string
+		want  bool
+	}{
+		{"alice@example.org", true},
+		{"alice.smith+oasis@mail.example.org", true},
+		{"o'brien@example.co.uk", true},
+		{"", false},
+		{"alice", false},
+		{"alice@", false},
+		{"@example.org", false},
+		{"alice@localhost", false},
+		{"alice@.example.org", false},
+		{"alice@example.org.", false},
+		{"alice@@example.org", false},
+		{"alice smith@example.org", false},
+		{" alice@example.org", false},
+		{"Alice <alice@example.org>", false},
+		{"<alice@example.org>", false},
+		{"alice@example.org (Alice)", false},
+		{"alice@example.org, bob@example.org", false},
+		{"alice@example.org\nBcc: eve@example.org", false},
+	} {
+		if got := ValidEmail(tc.email); got != tc.want {
+			t.Errorf("%q: got %t, want %t", tc.email, got, tc.want)
+		}
+	}
+}
@@ -13,13 +13,17 @@ import (
 	"time"
 )
 
-// CalculateEndpoint determines whether time.Now is happening during a duration from a set point in time.
-// If it is, then time.Now() is returned, otherwise the endpoint of the duration is returned.
+// CalculateEndpoint determines the point in time at which an action
+// on a meeting scheduled from begin to end takes effect.
+// If time.Now() is after the end of the meeting, end is returned.
+// Otherwise time.Now() is returned. This includes the cases that
+// the meeting has not started yet, so no point in the future is
+// returned, and that begin is after end, which is an invalid range.
 func CalculateEndpoint(begin time.Time, end time.Time) time.Time {
 	now := time.Now()
 
-	if now.After(begin) && now.Before(end) {
-		return now
+	if !begin.After(end) && now.After(end) {
+		return end
 	}
-	return end
+	return now
 }
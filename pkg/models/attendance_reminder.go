@@ -0,0 +1,131 @@
+// This file is Free Software under the Apache-2.0 License
+// without warranty, see README.md and LICENSE for details.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileCopyrightText: 2025 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2025 Intevation GmbH <https://intevation.de>
+
+package models
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/csaf-auxiliary/oasis-quorum-calculator/pkg/database"
+	"github.com/csaf-auxiliary/oasis-quorum-calculator/pkg/misc"
+)
+
+// AttendanceReminder is a member who should be reminded
+// to mark the attendance at a running meeting.
+type AttendanceReminder struct {
+	MeetingID     int64
+	CommitteeID   int64
+	CommitteeName string
+	CommitteeSlug string
+	StartTime     time.Time
+	Nickname      string
+	Email         *string
+	Timezone      *string
+}
+
+// PreferredTimezone returns the preferred timezone of the reminded member.
+func (ar *AttendanceReminder) PreferredTimezone() string {
+	if ar.Timezone == nil || *ar.Timezone == "" {
+		return "UTC"
+	}
+	return *ar.Timezone
+}
+
+// Recipient returns the email address of the reminded member.
+// The nickname is used if there is no email address and it
+// is an email address by convention. Returns an empty string
+// if the member cannot be reached.
+func (ar *AttendanceReminder) Recipient() string {
+	if ar.Email != nil && *ar.Email != "" {
+		return *ar.Email
+	}
+	if misc.ValidEmail(ar.Nickname) {
+		return ar.Nickname
+	}
+	return ""
+}
+
+// DueAttendanceReminders loads the members of the committees which
+// opted in for reminders who have not marked their attendance at
+// meetings running longer than delay. Members who are already reminded,
+// deactivated, no longer members or excused are left out.
+func DueAttendanceReminders(
+	ctx context.Context,
+	db *database.Database,
+	delay time.Duration,
+	now time.Time,
+) ([]*AttendanceReminder, error) {
+	const dueSQL = `SELECT m.id, m.committees_id, c.name, c.slug, m.start_time, ` +
+		`u.nickname, u.email, u.timezone ` +
+		`FROM meetings m ` +
+		`JOIN committees c ON m.committees_id = c.id ` +
+		`JOIN committee_roles cr ON cr.committees_id = m.committees_id ` +
+		`JOIN users u ON u.nickname = cr.nickname ` +
+		`WHERE m.status = 1 ` + // MeetingRunning
+		`AND c.attendance_reminders AND NOT c.archived ` +
+		`AND unixepoch(coalesce(m.updated_at, m.start_time)) <= unixepoch(?) ` +
+		`AND cr.committee_role_id = ? ` +
+		`AND u.deactivated IS NULL ` +
+		`AND NOT EXISTS (SELECT 1 FROM attendees a ` +
+		`WHERE a.meetings_id = m.id AND a.nickname = u.nickname) ` +
+		`AND NOT EXISTS (SELECT 1 FROM attendance_reminders ar ` +
+		`WHERE ar.meetings_id = m.id AND ar.nickname = u.nickname) ` +
+		`AND NOT EXISTS (SELECT 1 FROM member_absent ma ` +
+		`WHERE ma.nickname = u.nickname AND ma.committee_id = m.committees_id ` +
+		`AND ma.status = 1 ` + // AbsentApproved
+		`AND unixepoch(m.start_time) BETWEEN unixepoch(ma.start_time) AND unixepoch(ma.stop_time)) ` +
+		`AND coalesce((SELECT mh.status FROM member_history mh ` +
+		`WHERE mh.nickname = u.nickname AND mh.committees_id = m.committees_id ` +
+		`ORDER BY unixepoch(mh.since) DESC LIMIT 1), 0) <> 3 ` + // NoMember
+		`ORDER BY m.id, u.nickname`
+	rows, err := db.DB.QueryContext(ctx, dueSQL, now.Add(-delay), MemberRole)
+	if err != nil {
+		return nil, fmt.Errorf("querying due attendance reminders failed: %w", err)
+	}
+	defer rows.Close()
+	var reminders []*AttendanceReminder
+	for rows.Next() {
+		var ar AttendanceReminder
+		if err := rows.Scan(
+			&ar.MeetingID,
+			&ar.CommitteeID,
+			&ar.CommitteeName,
+			&ar.CommitteeSlug,
+			&ar.StartTime,
+			&ar.Nickname,
+			&ar.Email,
+			&ar.Timezone,
+		); err != nil {
+			return nil, fmt.Errorf("scanning due attendance reminders failed: %w", err)
+		}
+		reminders = append(reminders, &ar)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("querying due attendance reminders failed: %w", err)
+	}
+	return reminders, nil
+}
+
+// MarkAttendanceReminded records that a member was reminded
+// to mark the attendance at a meeting.
+func MarkAttendanceReminded(
+	ctx context.Context,
+	db *database.Database,
+	meetingID int64,
+	nickname string,
+) error {
+	const insertSQL = `INSERT INTO attendance_reminders (meetings_id, nickname) ` +
+		`VALUES (?, ?) ` +
+		`ON CONFLICT (meetings_id, nickname) DO NOTHING`
+	if _, err := db.DB.ExecContext(ctx, insertSQL, meetingID, nickname); err != nil {
+		return fmt.Errorf("storing attendance reminder failed: %w", err)
+	}
+	return nil
+}
@@ -14,17 +14,40 @@ import (
 	"errors"
 	"fmt"
 	"iter"
+	"time"
 
 	"github.com/csaf-auxiliary/oasis-quorum-calculator/pkg/database"
+	"github.com/csaf-auxiliary/oasis-quorum-calculator/pkg/misc"
 )
 
 // Committee represents a committee.
 type Committee struct {
 	ID          int64
 	Name        string
+	Slug        string
 	Description *string
+	CreatedAt   *time.Time
+	UpdatedAt   *time.Time
+	Archived    bool
+	// AttendanceReminders is set if the members should be reminded
+	// by email to mark their attendance at running meetings.
+	AttendanceReminders bool
+	// Version is increased with every update to detect concurrent edits.
+	Version int64
 }
 
+var (
+	// ErrCommitteeArchived is returned if an archived committee
+	// should get a new meeting.
+	ErrCommitteeArchived = errors.New("committee archived")
+	// ErrSlugTaken is returned if a slug is already used
+	// by another committee.
+	ErrSlugTaken = errors.New("slug taken")
+	// ErrModified is returned if a committee or a meeting
+	// was modified by someone else since it was loaded.
+	ErrModified = errors.New("modified concurrently")
+)
+
 // DeleteCommitteesByID deletes a list of committees by their ids.
 func DeleteCommitteesByID(ctx context.Context, db *database.Database, ids iter.Seq[int64]) error {
 	tx, err := db.DB.BeginTx(ctx, nil)
@@ -47,23 +70,32 @@ func (c *Committee) GetID() int64 {
 	return c.ID
 }
 
-// LoadCommittees loads all committees ordered by name.
+// LoadCommittees loads all not archived committees ordered by name.
 func LoadCommittees(ctx context.Context, db *database.Database) ([]*Committee, error) {
-	return LoadCommitteesFiltered(ctx, db, "")
+	return LoadCommitteesFiltered(ctx, db, "", false)
 }
 
 // LoadCommitteesFiltered loads all committees ordered by name that can be managed by the specified staff user.
-func LoadCommitteesFiltered(ctx context.Context, db *database.Database, filterStaffUser string) ([]*Committee, error) {
-	loadSQL := `SELECT id, name, description FROM committees `
+// Archived committees are only included if includeArchived is set.
+func LoadCommitteesFiltered(
+	ctx context.Context,
+	db *database.Database,
+	filterStaffUser string,
+	includeArchived bool,
+) ([]*Committee, error) {
+	loadSQL := `SELECT id, name, slug, description, created_at, updated_at, archived FROM committees ` +
+		`WHERE (? OR NOT archived)`
+	args := []any{includeArchived}
 	if filterStaffUser != "" {
-		loadSQL += ` WHERE EXISTS (SELECT 1 FROM committee_roles ` +
+		loadSQL += ` AND EXISTS (SELECT 1 FROM committee_roles ` +
 			`WHERE committee_role_id = ` +
 			`(SELECT id FROM committee_role WHERE name = 'staff') ` +
 			`AND id = committees_id ` +
 			`AND nickname = ?)`
+		args = append(args, filterStaffUser)
 	}
 	loadSQL += ` ORDER BY name`
-	rows, err := db.DB.QueryContext(ctx, loadSQL, filterStaffUser)
+	rows, err := db.DB.QueryContext(ctx, loadSQL, args...)
 	if err != nil {
 		return nil, fmt.Errorf("loading committees failed: %w", err)
 	}
@@ -71,7 +103,10 @@ func LoadCommitteesFiltered(ctx context.Context, db *database.Database, filterSt
 	var committees []*Committee
 	for rows.Next() {
 		var c Committee
-		if err := rows.Scan(&c.ID, &c.Name, &c.Description); err != nil {
+		if err := rows.Scan(
+			&c.ID, &c.Name, &c.Slug, &c.Description,
+			&c.CreatedAt, &c.UpdatedAt, &c.Archived,
+		); err != nil {
 			return nil, fmt.Errorf("scanning committees failed: %w", err)
 		}
 		committees = append(committees, &c)
@@ -82,6 +117,42 @@ func LoadCommitteesFiltered(ctx context.Context, db *database.Database, filterSt
 	return committees, nil
 }
 
+// SearchCommittees loads all committees ordered by name whose name
+// or description contains the given query case-insensitively.
+// Archived committees are only included if includeArchived is set.
+func SearchCommittees(
+	ctx context.Context,
+	db *database.Database,
+	query string,
+	includeArchived bool,
+) ([]*Committee, error) {
+	const searchSQL = `SELECT id, name, slug, description, created_at, updated_at, archived FROM committees ` +
+		`WHERE (name LIKE ? ESCAPE '\' OR slug LIKE ? ESCAPE '\' OR description LIKE ? ESCAPE '\') ` +
+		`AND (? OR NOT archived) ` +
+		`ORDER BY name`
+	pattern := misc.LikeContains(query)
+	rows, err := db.DB.QueryContext(ctx, searchSQL, pattern, pattern, pattern, includeArchived)
+	if err != nil {
+		return nil, fmt.Errorf("searching committees failed: %w", err)
+	}
+	defer rows.Close()
+	var committees []*Committee
+	for rows.Next() {
+		var c Committee
+		if err := rows.Scan(
+			&c.ID, &c.Name, &c.Slug, &c.Description,
+			&c.CreatedAt, &c.UpdatedAt, &c.Archived,
+		); err != nil {
+			return nil, fmt.Errorf("scanning committees failed: %w", err)
+		}
+		committees = append(committees, &c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("searching committees failed: %w", err)
+	}
+	return committees, nil
+}
+
 // CreateCommittee creates a new committee.
 func CreateCommittee(
 	ctx context.Context, db *database.Database,
@@ -101,29 +172,45 @@ func CreateCommittee(
 	if exists {
 		return nil, nil
 	}
-	const insertSQL = `INSERT INTO committees (name, description) VALUES (?, ?) ` +
-		`RETURNING id`
-	var id int64
-	if err := tx.QueryRowContext(ctx, insertSQL, name, description).Scan(&id); err != nil {
+	slug, err := UniqueCommitteeSlugTx(ctx, tx, name)
+	if err != nil {
+		return nil, err
+	}
+	const insertSQL = `INSERT INTO committees (name, slug, description, created_at, updated_at) ` +
+		`VALUES (?, ?, ?, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP) ` +
+		`RETURNING id, created_at, updated_at`
+	committee := Committee{
+		Name:        name,
+		Slug:        slug,
+		Description: description,
+	}
+	if err := tx.QueryRowContext(ctx, insertSQL, name, slug, description).Scan(
+		&committee.ID,
+		&committee.CreatedAt,
+		&committee.UpdatedAt,
+	); err != nil {
 		return nil, fmt.Errorf("inserting committee failed: %w", err)
 	}
 	if err := tx.Commit(); err != nil {
 		return nil, fmt.Errorf("committing committee failed: %w", err)
 	}
-	return &Committee{
-		ID:          id,
-		Name:        name,
-		Description: description,
-	}, nil
+	return &committee, nil
 }
 
 // LoadCommittee loads a committee by its id.
 func LoadCommittee(ctx context.Context, db *database.Database, id int64) (*Committee, error) {
-	const loadSQL = `SELECT name, description FROM committees WHERE id = ?`
+	const loadSQL = `SELECT name, slug, description, created_at, updated_at, archived, attendance_reminders, version ` +
+		`FROM committees WHERE id = ?`
 	committee := Committee{ID: id}
 	switch err := db.DB.QueryRowContext(ctx, loadSQL, id).Scan(
 		&committee.Name,
+		&committee.Slug,
 		&committee.Description,
+		&committee.CreatedAt,
+		&committee.UpdatedAt,
+		&committee.Archived,
+		&committee.AttendanceReminders,
+		&committee.Version,
 	); {
 	case errors.Is(err, sql.ErrNoRows):
 		return nil, nil
@@ -133,11 +220,181 @@ func LoadCommittee(ctx context.Context, db *database.Database, id int64) (*Commi
 	return &committee, nil
 }
 
+// LoadCommitteeBySlug loads a committee by its slug.
+// Returns nil if there is no such committee.
+func LoadCommitteeBySlug(ctx context.Context, db *database.Database, slug string) (*Committee, error) {
+	const loadSQL = `SELECT id, name, description, created_at, updated_at, archived, attendance_reminders, version ` +
+		`FROM committees WHERE slug = ?`
+	committee := Committee{Slug: slug}
+	switch err := db.DB.QueryRowContext(ctx, loadSQL, slug).Scan(
+		&committee.ID,
+		&committee.Name,
+		&committee.Description,
+		&committee.CreatedAt,
+		&committee.UpdatedAt,
+		&committee.Archived,
+		&committee.AttendanceReminders,
+		&committee.Version,
+	); {
+	case errors.Is(err, sql.ErrNoRows):
+		return nil, nil
+	case err != nil:
+		return nil, fmt.Errorf("loading committee by slug failed: %w", err)
+	}
+	return &committee, nil
+}
+
+// UniqueCommitteeSlugTx derives a slug from a committee name which
+// is not used by any other committee. Collisions are resolved by
+// appending a counter.
+func UniqueCommitteeSlugTx(ctx context.Context, tx *sql.Tx, name string) (string, error) {
+	base := misc.Slugify(name)
+	if base == "" {
+		base = "committee"
+	}
+	const existsSQL = `SELECT EXISTS(SELECT 1 FROM committees WHERE slug = ?)`
+	for i := 1; ; i++ {
+		slug := base
+		if i > 1 {
+			slug = fmt.Sprintf("%s-%d", base, i)
+		}
+		var exists bool
+		if err := tx.QueryRowContext(ctx, existsSQL, slug).Scan(&exists); err != nil {
+			return "", fmt.Errorf("checking committee slug failed: %w", err)
+		}
+		if !exists {
+			return slug, nil
+		}
+	}
+}
+
 // Store stores a committee into the database.
+// Returns [ErrSlugTaken] if the slug is used by another committee
+// and [ErrModified] if the committee was updated since it was loaded.
 func (c *Committee) Store(ctx context.Context, db *database.Database) error {
-	const updateSQL = `UPDATE committees SET name = ?, description = ? WHERE id = ?`
-	if _, err := db.DB.ExecContext(ctx, updateSQL, c.Name, c.Description, c.ID); err != nil {
+	tx, err := db.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	const existsSQL = `SELECT EXISTS(SELECT 1 FROM committees WHERE slug = ? AND id <> ?)`
+	var exists bool
+	if err := tx.QueryRowContext(ctx, existsSQL, c.Slug, c.ID).Scan(&exists); err != nil {
+		return fmt.Errorf("checking committee slug failed: %w", err)
+	}
+	if exists {
+		return ErrSlugTaken
+	}
+	const updateSQL = `UPDATE committees SET name = ?, slug = ?, description = ?, ` +
+		`attendance_reminders = ?, updated_at = CURRENT_TIMESTAMP, version = version + 1 ` +
+		`WHERE id = ? AND version = ? ` +
+		`RETURNING updated_at, version`
+	switch err := tx.QueryRowContext(ctx, updateSQL,
+		c.Name, c.Slug, c.Description, c.AttendanceReminders, c.ID, c.Version,
+	).Scan(&c.UpdatedAt, &c.Version); {
+	case errors.Is(err, sql.ErrNoRows):
+		// Committee does not exist any more or was modified.
+		const modifiedSQL = `SELECT EXISTS(SELECT 1 FROM committees WHERE id = ?)`
+		var modified bool
+		if err := tx.QueryRowContext(ctx, modifiedSQL, c.ID).Scan(&modified); err != nil {
+			return fmt.Errorf("checking committee existence failed: %w", err)
+		}
+		if modified {
+			return ErrModified
+		}
+		return nil
+	case err != nil:
 		return fmt.Errorf("storing committee failed: %w", err)
 	}
+	return tx.Commit()
+}
+
+// ArchiveCommittee archives a committee. Archived committees are
+// hidden by default and do not accept new meetings.
+func ArchiveCommittee(ctx context.Context, db *database.Database, id int64) error {
+	return setCommitteeArchived(ctx, db, id, true)
+}
+
+// UnarchiveCommittee brings an archived committee back.
+func UnarchiveCommittee(ctx context.Context, db *database.Database, id int64) error {
+	return setCommitteeArchived(ctx, db, id, false)
+}
+
+// setCommitteeArchived sets the archived flag of a committee.
+func setCommitteeArchived(ctx context.Context, db *database.Database, id int64, archived bool) error {
+	const archiveSQL = `UPDATE committees SET archived = ?, ` +
+		`updated_at = CURRENT_TIMESTAMP ` +
+		`WHERE id = ? AND archived <> ?`
+	if _, err := db.DB.ExecContext(ctx, archiveSQL, archived, id, archived); err != nil {
+		return fmt.Errorf("archiving committee failed: %w", err)
+	}
 	return nil
 }
+
+// MeetingStats are statistics about the meetings of a committee.
+type MeetingStats struct {
+	// Meetings is the number of all meetings.
+	Meetings int64
+	// Concluded is the number of concluded meetings.
+	Concluded int64
+	// AverageAttendance is the average number of
+	// attendees of the concluded meetings.
+	AverageAttendance float64
+	// Quora is the number of concluded voting meetings
+	// with a frozen quorum.
+	Quora int64
+	// QuorumReached is the number of these meetings
+	// which reached the quorum.
+	QuorumReached int64
+}
+
+// QuorumRate returns the percentage of the concluded voting
+// meetings which reached the quorum.
+func (ms *MeetingStats) QuorumRate() float64 {
+	if ms.Quora == 0 {
+		return 0
+	}
+	return 100 * float64(ms.QuorumReached) / float64(ms.Quora)
+}
+
+// CommitteeStats calculates statistics about the meetings of a committee.
+// Committees without meetings result in zeroed statistics.
+func CommitteeStats(ctx context.Context, db *database.Database, committeeID int64) (*MeetingStats, error) {
+	tx, err := db.DB.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+	const (
+		countSQL = `SELECT count(*), ` +
+			`coalesce(sum(CASE WHEN status = 2 THEN 1 ELSE 0 END), 0) ` + // MeetingConcluded
+			`FROM meetings WHERE committees_id = ?`
+		attendanceSQL = `SELECT coalesce(avg(n), 0) FROM (` +
+			`SELECT count(a.nickname) AS n FROM meetings m ` +
+			`LEFT JOIN attendees a ON a.meetings_id = m.id ` +
+			`WHERE m.committees_id = ? AND m.status = 2 ` + // MeetingConcluded
+			`GROUP BY m.id) AS attendance`
+		// Meetings concluded without a snapshot have no reliable quorum.
+		quorumSQL = `SELECT count(*), ` +
+			`coalesce(sum(CASE WHEN s.attending_voting >= 1 + s.voting / 2 THEN 1 ELSE 0 END), 0) ` +
+			`FROM meeting_snapshots s JOIN meetings m ON s.meetings_id = m.id ` +
+			`WHERE m.committees_id = ? AND m.status = 2 AND NOT m.gathering` // MeetingConcluded
+	)
+	var stats MeetingStats
+	if err := tx.QueryRowContext(ctx, countSQL, committeeID).Scan(
+		&stats.Meetings, &stats.Concluded,
+	); err != nil {
+		return nil, fmt.Errorf("counting meetings failed: %w", err)
+	}
+	if err := tx.QueryRowContext(ctx, attendanceSQL, committeeID).Scan(
+		&stats.AverageAttendance,
+	); err != nil {
+		return nil, fmt.Errorf("calculating average attendance failed: %w", err)
+	}
+	if err := tx.QueryRowContext(ctx, quorumSQL, committeeID).Scan(
+		&stats.Quora, &stats.QuorumReached,
+	); err != nil {
+		return nil, fmt.Errorf("counting reached quora failed: %w", err)
+	}
+	return &stats, nil
+}
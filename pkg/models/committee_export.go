@@ -0,0 +1,711 @@
+// This file is Free Software under the Apache-2.0 License
+// without warranty, see README.md and LICENSE for details.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileCopyrightText: 2025 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2025 Intevation GmbH <https://intevation.de>
+
+package models
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/csaf-auxiliary/oasis-quorum-calculator/pkg/database"
+	"github.com/csaf-auxiliary/oasis-quorum-calculator/pkg/misc"
+)
+
+// CommitteeExportFormat is the version of the format
+// written by [ExportCommittee].
+const CommitteeExportFormat = 1
+
+// ErrInvalidImport is returned if a committee import
+// cannot be read or is inconsistent.
+var ErrInvalidImport = errors.New("invalid committee import")
+
+// CommitteeExport is the state of a committee as a single document
+// for backups and migrations between databases.
+// Meeting documents and reminders are not part of it.
+type CommitteeExport struct {
+	Format              int              `json:"format"`
+	Name                string           `json:"name"`
+	Slug                string           `json:"slug"`
+	Description         *string          `json:"description,omitempty"`
+	Archived            bool             `json:"archived"`
+	AttendanceReminders bool             `json:"attendance_reminders"`
+	Members             []*MemberExport  `json:"members"`
+	Meetings            []*MeetingExport `json:"meetings"`
+	Absences            []*AbsenceExport `json:"absences"`
+}
+
+// MemberExport is a user with the roles and the
+// status history in the exported committee.
+type MemberExport struct {
+	Nickname  string           `json:"nickname"`
+	Firstname *string          `json:"firstname,omitempty"`
+	Lastname  *string          `json:"lastname,omitempty"`
+	Email     *string          `json:"email,omitempty"`
+	Roles     []string         `json:"roles,omitempty"`
+	History   []*HistoryExport `json:"history,omitempty"`
+}
+
+// HistoryExport is an entry of the status history of a member.
+type HistoryExport struct {
+	Since  time.Time `json:"since"`
+	Status string    `json:"status"`
+}
+
+// MeetingExport is a meeting with its attendance.
+type MeetingExport struct {
+	StartTime   time.Time         `json:"start_time"`
+	StopTime    time.Time         `json:"stop_time"`
+	Status      string            `json:"status"`
+	Gathering   bool              `json:"gathering"`
+	Description *string           `json:"description,omitempty"`
+	Agenda      *string           `json:"agenda,omitempty"`
+	Attendees   []*AttendeeExport `json:"attendees,omitempty"`
+	Expected    []string          `json:"expected,omitempty"`
+	Snapshot    *SnapshotExport   `json:"snapshot,omitempty"`
+}
+
+// AttendeeExport is a member who attended a meeting.
+type AttendeeExport struct {
+	Nickname      string `json:"nickname"`
+	VotingAllowed bool   `json:"voting_allowed"`
+	NonVoting     bool   `json:"non_voting"`
+}
+
+// SnapshotExport is the quorum stored at the conclusion of a meeting.
+type SnapshotExport struct {
+	Voting          int       `json:"voting"`
+	AttendingVoting int       `json:"attending_voting"`
+	Taken           time.Time `json:"taken"`
+	Voters          []string  `json:"voters,omitempty"`
+}
+
+// AbsenceExport is an excused absence of a member.
+type AbsenceExport struct {
+	Nickname  string    `json:"nickname"`
+	StartTime time.Time `json:"start_time"`
+	StopTime  time.Time `json:"stop_time"`
+	Status    string    `json:"status"`
+}
+
+// ExportCommittee assembles the state of a committee.
+// Returns nil if there is no such committee.
+func ExportCommittee(ctx context.Context, db *database.Database, committeeID int64) (*CommitteeExport, error) {
+	tx, err := db.DB.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+	const committeeSQL = `SELECT name, slug, description, archived, attendance_reminders ` +
+		`FROM committees WHERE id = ?`
+	export := CommitteeExport{Format: CommitteeExportFormat}
+	switch err := tx.QueryRowContext(ctx, committeeSQL, committeeID).Scan(
+		&export.Name,
+		&export.Slug,
+		&export.Description,
+		&export.Archived,
+		&export.AttendanceReminders,
+	); {
+	case errors.Is(err, sql.ErrNoRows):
+		return nil, nil
+	case err != nil:
+		return nil, fmt.Errorf("loading committee failed: %w", err)
+	}
+	members := map[string]*MemberExport{}
+	member := func(nickname string) *MemberExport {
+		m := members[nickname]
+		if m == nil {
+			m = &MemberExport{Nickname: nickname}
+			members[nickname] = m
+		}
+		return m
+	}
+	if err := exportRolesTx(ctx, tx, committeeID, member); err != nil {
+		return nil, err
+	}
+	histories, err := LoadUsersHistoriesTx(ctx, tx, committeeID)
+	if err != nil {
+		return nil, err
+	}
+	for nickname, history := range histories {
+		m := member(nickname)
+		for _, entry := range history {
+			m.History = append(m.History, &HistoryExport{
+				Since:  entry.Since.UTC(),
+				Status: entry.Status.String(),
+			})
+		}
+	}
+	if export.Meetings, err = exportMeetingsTx(ctx, tx, committeeID); err != nil {
+		return nil, err
+	}
+	for _, meeting := range export.Meetings {
+		for _, attendee := range meeting.Attendees {
+			member(attendee.Nickname)
+		}
+		for _, nickname := range meeting.Expected {
+			member(nickname)
+		}
+	}
+	if export.Absences, err = exportAbsencesTx(ctx, tx, committeeID); err != nil {
+		return nil, err
+	}
+	for _, absence := range export.Absences {
+		member(absence.Nickname)
+	}
+	nicknames := slices.Sorted(maps.Keys(members))
+	users, err := loadBasicUsersTx(ctx, tx, nicknames)
+	if err != nil {
+		return nil, err
+	}
+	for _, user := range users {
+		m := members[user.Nickname]
+		m.Firstname, m.Lastname, m.Email = user.Firstname, user.Lastname, user.Email
+	}
+	for _, nickname := range nicknames {
+		export.Members = append(export.Members, members[nickname])
+	}
+	return &export, nil
+}
+
+// exportRolesTx adds the roles in the committee to the members.
+func exportRolesTx(
+	ctx context.Context,
+	tx *sql.Tx,
+	committeeID int64,
+	member func(string) *MemberExport,
+) error {
+	const rolesSQL = `SELECT nickname, committee_role_id FROM committee_roles ` +
+		`WHERE committees_id = ? ` +
+		`ORDER BY nickname, committee_role_id`
+	rows, err := tx.QueryContext(ctx, rolesSQL, committeeID)
+	if err != nil {
+		return fmt.Errorf("loading committee roles failed: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var (
+			nickname string
+			role     Role
+		)
+		if err := rows.Scan(&nickname, &role); err != nil {
+			return fmt.Errorf("scanning committee roles failed: %w", err)
+		}
+		m := member(nickname)
+		m.Roles = append(m.Roles, role.String())
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("loading committee roles failed: %w", err)
+	}
+	return nil
+}
+
+// exportMeetingsTx loads the meetings of a committee
+// together with their attendees, expected users and snapshots.
+func exportMeetingsTx(ctx context.Context, tx *sql.Tx, committeeID int64) ([]*MeetingExport, error) {
+	const (
+		meetingsSQL = `SELECT id, gathering, status, start_time, stop_time, description, agenda ` +
+			`FROM meetings WHERE committees_id = ? ` +
+			`ORDER BY unixepoch(start_time)`
+		attendeesSQL = `SELECT a.meetings_id, a.nickname, a.voting_allowed, a.non_voting ` +
+			`FROM attendees a JOIN meetings m ON a.meetings_id = m.id ` +
+			`WHERE m.committees_id = ? ` +
+			`ORDER BY a.nickname`
+		snapshotsSQL = `SELECT s.meetings_id, s.voting, s.attending_voting, s.taken ` +
+			`FROM meeting_snapshots s JOIN meetings m ON s.meetings_id = m.id ` +
+			`WHERE m.committees_id = ?`
+		expectedSQL = `SELECT e.meetings_id, e.nickname ` +
+			`FROM meeting_expected e JOIN meetings m ON e.meetings_id = m.id ` +
+			`WHERE m.committees_id = ? ` +
+			`ORDER BY e.nickname`
+		votersSQL = `SELECT v.meetings_id, v.nickname ` +
+			`FROM meeting_snapshot_voters v JOIN meetings m ON v.meetings_id = m.id ` +
+			`WHERE m.committees_id = ? ` +
+			`ORDER BY v.nickname`
+	)
+	rows, err := tx.QueryContext(ctx, meetingsSQL, committeeID)
+	if err != nil {
+		return nil, fmt.Errorf("loading meetings failed: %w", err)
+	}
+	defer rows.Close()
+	var (
+		meetings []*MeetingExport
+		byID     = map[int64]*MeetingExport{}
+	)
+	for rows.Next() {
+		var (
+			id      int64
+			status  MeetingStatus
+			meeting MeetingExport
+		)
+		if err := rows.Scan(
+			&id,
+			&meeting.Gathering,
+			&status,
+			&meeting.StartTime,
+			&meeting.StopTime,
+			&meeting.Description,
+			&meeting.Agenda,
+		); err != nil {
+			return nil, fmt.Errorf("scanning meetings failed: %w", err)
+		}
+		meeting.Status = status.String()
+		meeting.StartTime = meeting.StartTime.UTC()
+		meeting.StopTime = meeting.StopTime.UTC()
+		meetings = append(meetings, &meeting)
+		byID[id] = &meeting
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("loading meetings failed: %w", err)
+	}
+
+	if rows, err = tx.QueryContext(ctx, attendeesSQL, committeeID); err != nil {
+		return nil, fmt.Errorf("loading attendees failed: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var (
+			id       int64
+			attendee AttendeeExport
+		)
+		if err := rows.Scan(&id, &attendee.Nickname, &attendee.VotingAllowed, &attendee.NonVoting); err != nil {
+			return nil, fmt.Errorf("scanning attendees failed: %w", err)
+		}
+		meeting := byID[id]
+		meeting.Attendees = append(meeting.Attendees, &attendee)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("loading attendees failed: %w", err)
+	}
+
+	if rows, err = tx.QueryContext(ctx, expectedSQL, committeeID); err != nil {
+		return nil, fmt.Errorf("loading expected users failed: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var (
+			id       int64
+			nickname string
+		)
+		if err := rows.Scan(&id, &nickname); err != nil {
+			return nil, fmt.Errorf("scanning expected users failed: %w", err)
+		}
+		meeting := byID[id]
+		meeting.Expected = append(meeting.Expected, nickname)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("loading expected users failed: %w", err)
+	}
+
+	if rows, err = tx.QueryContext(ctx, snapshotsSQL, committeeID); err != nil {
+		return nil, fmt.Errorf("loading meeting snapshots failed: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var (
+			id       int64
+			snapshot SnapshotExport
+		)
+		if err := rows.Scan(&id, &snapshot.Voting, &snapshot.AttendingVoting, &snapshot.Taken); err != nil {
+			return nil, fmt.Errorf("scanning meeting snapshots failed: %w", err)
+		}
+		snapshot.Taken = snapshot.Taken.UTC()
+		byID[id].Snapshot = &snapshot
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("loading meeting snapshots failed: %w", err)
+	}
+
+	if rows, err = tx.QueryContext(ctx, votersSQL, committeeID); err != nil {
+		return nil, fmt.Errorf("loading meeting snapshot voters failed: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var (
+			id       int64
+			nickname string
+		)
+		if err := rows.Scan(&id, &nickname); err != nil {
+			return nil, fmt.Errorf("scanning meeting snapshot voters failed: %w", err)
+		}
+		if snapshot := byID[id].Snapshot; snapshot != nil {
+			snapshot.Voters = append(snapshot.Voters, nickname)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("loading meeting snapshot voters failed: %w", err)
+	}
+	return meetings, nil
+}
+
+// exportAbsencesTx loads the excused absences of the members of a committee.
+func exportAbsencesTx(ctx context.Context, tx *sql.Tx, committeeID int64) ([]*AbsenceExport, error) {
+	const absencesSQL = `SELECT nickname, start_time, stop_time, status FROM member_absent ` +
+		`WHERE committee_id = ? ` +
+		`ORDER BY nickname, unixepoch(start_time)`
+	rows, err := tx.QueryContext(ctx, absencesSQL, committeeID)
+	if err != nil {
+		return nil, fmt.Errorf("loading member absent failed: %w", err)
+	}
+	defer rows.Close()
+	var absences []*AbsenceExport
+	for rows.Next() {
+		var (
+			absence AbsenceExport
+			status  AbsentStatus
+		)
+		if err := rows.Scan(&absence.Nickname, &absence.StartTime, &absence.StopTime, &status); err != nil {
+			return nil, fmt.Errorf("scanning member absent failed: %w", err)
+		}
+		absence.Status = status.String()
+		absence.StartTime = absence.StartTime.UTC()
+		absence.StopTime = absence.StopTime.UTC()
+		absences = append(absences, &absence)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("loading member absent failed: %w", err)
+	}
+	return absences, nil
+}
+
+// ImportCommitteeJSON recreates the state of a committee written by
+// [ExportCommittee] as JSON. The committee is matched by its slug and
+// its meetings by their start times, so importing the same document
+// again leaves the database unchanged. Roles, histories, attendance and
+// absences are replaced by the imported ones. Meetings not contained
+// in the document are kept. Unknown users are created deactivated
+// with a random password. They keep counting as members but cannot
+// log in until an administrator reactivates them and sets a password.
+// Imported meetings have to be valid and must not overlap other
+// meetings of the committee.
+// Returns the id of the committee. Errors in the document are
+// reported as [ErrInvalidImport].
+func ImportCommitteeJSON(ctx context.Context, db *database.Database, r io.Reader) (int64, error) {
+	var export CommitteeExport
+	if err := json.NewDecoder(r).Decode(&export); err != nil {
+		return 0, fmt.Errorf("%w: %w", ErrInvalidImport, err)
+	}
+	switch {
+	case export.Format != CommitteeExportFormat:
+		return 0, fmt.Errorf("%w: unsupported format %d", ErrInvalidImport, export.Format)
+	case export.Name == "":
+		return 0, fmt.Errorf("%w: missing name", ErrInvalidImport)
+	case export.Slug == "" || misc.Slugify(export.Slug) != export.Slug:
+		return 0, fmt.Errorf("%w: invalid slug %q", ErrInvalidImport, export.Slug)
+	}
+	tx, err := db.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+	committeeID, err := importCommitteeTx(ctx, tx, &export)
+	if err != nil {
+		return 0, err
+	}
+	nicknames, err := importMembersTx(ctx, tx, committeeID, export.Members)
+	if err != nil {
+		return 0, err
+	}
+	// Maps the nicknames of the document to the ones in the database.
+	canonical := func(nickname string) (string, error) {
+		if c, ok := nicknames[nickname]; ok {
+			return c, nil
+		}
+		return "", fmt.Errorf("%w: unknown member %q", ErrInvalidImport, nickname)
+	}
+	if err := importMeetingsTx(ctx, tx, committeeID, export.Meetings, canonical); err != nil {
+		return 0, err
+	}
+	if err := importAbsencesTx(ctx, tx, committeeID, export.Absences, canonical); err != nil {
+		return 0, err
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("committing committee import failed: %w", err)
+	}
+	return committeeID, nil
+}
+
+// importCommitteeTx creates or updates the committee with the slug of the import.
+func importCommitteeTx(ctx context.Context, tx *sql.Tx, export *CommitteeExport) (int64, error) {
+	const (
+		idSQL     = `SELECT id FROM committees WHERE slug = ?`
+		updateSQL = `UPDATE committees SET name = ?, description = ?, archived = ?, ` +
+			`attendance_reminders = ?, updated_at = CURRENT_TIMESTAMP, version = version + 1 ` +
+			`WHERE id = ?`
+		insertSQL = `INSERT INTO committees ` +
+			`(name, slug, description, archived, attendance_reminders, created_at, updated_at) ` +
+			`VALUES (?, ?, ?, ?, ?, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP) ` +
+			`RETURNING id`
+	)
+	var id int64
+	switch err := tx.QueryRowContext(ctx, idSQL, export.Slug).Scan(&id); {
+	case errors.Is(err, sql.ErrNoRows):
+		if err := tx.QueryRowContext(ctx, insertSQL,
+			export.Name, export.Slug, export.Description,
+			export.Archived, export.AttendanceReminders,
+		).Scan(&id); err != nil {
+			return 0, fmt.Errorf("inserting committee failed: %w", err)
+		}
+	case err != nil:
+		return 0, fmt.Errorf("loading committee by slug failed: %w", err)
+	default:
+		if _, err := tx.ExecContext(ctx, updateSQL,
+			export.Name, export.Description,
+			export.Archived, export.AttendanceReminders, id,
+		); err != nil {
+			return 0, fmt.Errorf("updating committee failed: %w", err)
+		}
+	}
+	return id, nil
+}
+
+// importMembersTx creates the missing users and replaces the roles and
+// the histories in the committee. It returns a map from the nicknames
+// of the import to the nicknames in the database.
+func importMembersTx(
+	ctx context.Context,
+	tx *sql.Tx,
+	committeeID int64,
+	members []*MemberExport,
+) (map[string]string, error) {
+	const (
+		insertUserSQL = `INSERT INTO users ` +
+			`(nickname, firstname, lastname, email, password, must_change_password, deactivated) ` +
+			`VALUES (?, ?, ?, ?, ?, true, CURRENT_TIMESTAMP)`
+		deleteRolesSQL   = `DELETE FROM committee_roles WHERE committees_id = ?`
+		insertRoleSQL    = `INSERT INTO committee_roles (nickname, committee_role_id, committees_id) VALUES (?, ?, ?)`
+		deleteHistorySQL = `DELETE FROM member_history WHERE committees_id = ?`
+		insertHistorySQL = `INSERT INTO member_history (nickname, committees_id, status, since) VALUES (?, ?, ?, ?)`
+	)
+	if _, err := tx.ExecContext(ctx, deleteRolesSQL, committeeID); err != nil {
+		return nil, fmt.Errorf("deleting committee roles failed: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, deleteHistorySQL, committeeID); err != nil {
+		return nil, fmt.Errorf("deleting member history failed: %w", err)
+	}
+	nicknames := make(map[string]string, len(members))
+	for _, member := range members {
+		if member.Nickname == "" {
+			return nil, fmt.Errorf("%w: member without nickname", ErrInvalidImport)
+		}
+		nickname, err := canonicalNicknameTx(ctx, tx, member.Nickname)
+		if err != nil {
+			return nil, err
+		}
+		if nickname == "" {
+			nickname = member.Nickname
+			encoded := misc.EncodePassword(misc.RandomString(12))
+			if _, err := tx.ExecContext(ctx, insertUserSQL,
+				nickname, member.Firstname, member.Lastname, member.Email, encoded,
+			); err != nil {
+				return nil, fmt.Errorf("inserting user failed: %w", err)
+			}
+		}
+		nicknames[member.Nickname] = nickname
+		for _, r := range member.Roles {
+			role, err := ParseRole(r)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %w", ErrInvalidImport, err)
+			}
+			if _, err := tx.ExecContext(ctx, insertRoleSQL, nickname, role, committeeID); err != nil {
+				return nil, fmt.Errorf("inserting committee role failed: %w", err)
+			}
+		}
+		for _, entry := range member.History {
+			status, err := ParseMemberStatus(entry.Status)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %w", ErrInvalidImport, err)
+			}
+			if _, err := tx.ExecContext(ctx, insertHistorySQL,
+				nickname, committeeID, status, entry.Since,
+			); err != nil {
+				return nil, fmt.Errorf("inserting member history failed: %w", err)
+			}
+		}
+	}
+	return nicknames, nil
+}
+
+// importMeetingsTx creates or updates the meetings of the committee
+// and replaces their attendees, expected users and snapshots.
+func importMeetingsTx(
+	ctx context.Context,
+	tx *sql.Tx,
+	committeeID int64,
+	meetings []*MeetingExport,
+	canonical func(string) (string, error),
+) error {
+	const (
+		existingSQL = `SELECT id, start_time, stop_time FROM meetings WHERE committees_id = ?`
+		updateSQL   = `UPDATE meetings SET gathering = ?, status = ?, stop_time = ?, ` +
+			`description = ?, agenda = ?, updated_at = CURRENT_TIMESTAMP, version = version + 1 ` +
+			`WHERE id = ?`
+		insertSQL = `INSERT INTO meetings ` +
+			`(committees_id, gathering, status, start_time, stop_time, description, agenda, created_at, updated_at) ` +
+			`VALUES (?, ?, ?, ?, ?, ?, ?, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP) ` +
+			`RETURNING id`
+		deleteAttendeesSQL = `DELETE FROM attendees WHERE meetings_id = ?`
+		insertAttendeeSQL  = `INSERT INTO attendees (meetings_id, nickname, voting_allowed, non_voting) ` +
+			`VALUES (?, ?, ?, ?)`
+		deleteExpectedSQL = `DELETE FROM meeting_expected WHERE meetings_id = ?`
+		insertExpectedSQL = `INSERT INTO meeting_expected (meetings_id, nickname) VALUES (?, ?)`
+		deleteSnapshotSQL = `DELETE FROM meeting_snapshots WHERE meetings_id = ?`
+	)
+	rows, err := tx.QueryContext(ctx, existingSQL, committeeID)
+	if err != nil {
+		return fmt.Errorf("loading meetings failed: %w", err)
+	}
+	defer rows.Close()
+	// The meetings of the committee by their start times.
+	existing := map[int64]*Meeting{}
+	for rows.Next() {
+		m := Meeting{CommitteeID: committeeID}
+		if err := rows.Scan(&m.ID, &m.StartTime, &m.StopTime); err != nil {
+			return fmt.Errorf("scanning meetings failed: %w", err)
+		}
+		existing[m.StartTime.Unix()] = &m
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("loading meetings failed: %w", err)
+	}
+	for _, meeting := range meetings {
+		status, err := ParseMeetingStatus(meeting.Status)
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrInvalidImport, err)
+		}
+		m := &Meeting{
+			CommitteeID: committeeID,
+			StartTime:   meeting.StartTime,
+			StopTime:    meeting.StopTime,
+		}
+		if err := m.Validate(); err != nil {
+			return fmt.Errorf("%w: meeting at %s: %w",
+				ErrInvalidImport, meeting.StartTime.Format(time.RFC3339), err)
+		}
+		var id int64
+		if old := existing[meeting.StartTime.Unix()]; old != nil {
+			id = old.ID
+		}
+		overlap := OverlapFilter(m.StartTime, m.StopTime, id)
+		for other := range maps.Values(existing) {
+			if overlap(other) {
+				return fmt.Errorf("%w: meeting at %s overlaps meeting at %s",
+					ErrInvalidImport,
+					meeting.StartTime.Format(time.RFC3339),
+					other.StartTime.UTC().Format(time.RFC3339))
+			}
+		}
+		if id != 0 {
+			if _, err := tx.ExecContext(ctx, updateSQL,
+				meeting.Gathering, status, meeting.StopTime,
+				meeting.Description, meeting.Agenda, id,
+			); err != nil {
+				return fmt.Errorf("updating meeting failed: %w", err)
+			}
+		} else {
+			if err := tx.QueryRowContext(ctx, insertSQL,
+				committeeID, meeting.Gathering, status, meeting.StartTime, meeting.StopTime,
+				meeting.Description, meeting.Agenda,
+			).Scan(&id); err != nil {
+				return fmt.Errorf("inserting meeting failed: %w", err)
+			}
+		}
+		m.ID = id
+		existing[meeting.StartTime.Unix()] = m
+		if _, err := tx.ExecContext(ctx, deleteAttendeesSQL, id); err != nil {
+			return fmt.Errorf("deleting attendees failed: %w", err)
+		}
+		for _, attendee := range meeting.Attendees {
+			nickname, err := canonical(attendee.Nickname)
+			if err != nil {
+				return err
+			}
+			if _, err := tx.ExecContext(ctx, insertAttendeeSQL,
+				id, nickname, attendee.VotingAllowed, attendee.NonVoting,
+			); err != nil {
+				return fmt.Errorf("inserting attendee failed: %w", err)
+			}
+		}
+		if _, err := tx.ExecContext(ctx, deleteExpectedSQL, id); err != nil {
+			return fmt.Errorf("deleting expected users failed: %w", err)
+		}
+		for _, expected := range meeting.Expected {
+			nickname, err := canonical(expected)
+			if err != nil {
+				return err
+			}
+			if _, err := tx.ExecContext(ctx, insertExpectedSQL, id, nickname); err != nil {
+				return fmt.Errorf("inserting expected user failed: %w", err)
+			}
+		}
+		snapshot := meeting.Snapshot
+		if snapshot == nil {
+			if _, err := tx.ExecContext(ctx, deleteSnapshotSQL, id); err != nil {
+				return fmt.Errorf("deleting meeting snapshot failed: %w", err)
+			}
+			continue
+		}
+		voters := make([]string, 0, len(snapshot.Voters))
+		for _, voter := range snapshot.Voters {
+			nickname, err := canonical(voter)
+			if err != nil {
+				return err
+			}
+			voters = append(voters, nickname)
+		}
+		quorum := Quorum{
+			Voting:          snapshot.Voting,
+			AttendingVoting: snapshot.AttendingVoting,
+		}
+		if err := writeMeetingSnapshotTx(ctx, tx, id, &quorum, voters, snapshot.Taken); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// importAbsencesTx replaces the excused absences of the committee.
+func importAbsencesTx(
+	ctx context.Context,
+	tx *sql.Tx,
+	committeeID int64,
+	absences []*AbsenceExport,
+	canonical func(string) (string, error),
+) error {
+	const (
+		deleteSQL = `DELETE FROM member_absent WHERE committee_id = ?`
+		insertSQL = `INSERT INTO member_absent ` +
+			`(nickname, start_time, stop_time, committee_id, status) ` +
+			`VALUES (?, ?, ?, ?, ?)`
+	)
+	if _, err := tx.ExecContext(ctx, deleteSQL, committeeID); err != nil {
+		return fmt.Errorf("deleting member absent failed: %w", err)
+	}
+	for _, absence := range absences {
+		nickname, err := canonical(absence.Nickname)
+		if err != nil {
+			return err
+		}
+		status, err := ParseAbsentStatus(absence.Status)
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrInvalidImport, err)
+		}
+		if _, err := tx.ExecContext(ctx, insertSQL,
+			nickname, absence.StartTime, absence.StopTime, committeeID, status,
+		); err != nil {
+			return fmt.Errorf("inserting member absent failed: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,139 @@
+// This file is Free Software under the Apache-2.0 License
+// without warranty, see README.md and LICENSE for details.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileCopyrightText: 2025 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2025 Intevation GmbH <https://intevation.de>
+
+package models
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/csaf-auxiliary/oasis-quorum-calculator/pkg/database"
+)
+
+// exportFixture fills a database with a committee using all
+// the parts of a [CommitteeExport].
+func exportFixture(t *testing.T) *database.Database {
+	t.Helper()
+	db := newTestDatabase(t)
+	exec(t, db,
+		`INSERT INTO committees (id, name, slug, description, attendance_reminders) `+
+			`VALUES (1, 'Technical Committee', 'tc', 'The TC', true)`,
+		`INSERT INTO users (nickname, firstname, lastname, email, password) VALUES `+
+			`('alice', 'Alice', 'Doe', 'alice@example.com', 'x'), ('bob', NULL, NULL, NULL, 'x')`,
+		`INSERT INTO committee_roles (nickname, committees_id, committee_role_id) VALUES `+
+			`('alice', 1, 0), ('alice', 1, 1), ('bob', 1, 1)`,
+		`INSERT INTO member_history (nickname, committees_id, status, since) VALUES `+
+			`('alice', 1, 1, '2025-01-01 00:00:00+00:00'), `+
+			`('bob', 1, 0, '2025-01-01 00:00:00+00:00'), `+
+			`('bob', 1, 1, '2025-02-01 00:00:00+00:00')`,
+		`INSERT INTO meetings (id, committees_id, status, gathering, start_time, stop_time, description, agenda) VALUES `+
+			`(1, 1, 2, false, '2025-01-10 10:00:00+00:00', '2025-01-10 11:00:00+00:00', 'First', '1. Welcome'), `+
+			`(2, 1, 0, true, '2025-03-10 10:00:00+00:00', '2025-03-10 12:00:00+00:00', NULL, NULL)`,
+		`INSERT INTO attendees (meetings_id, nickname, voting_allowed, non_voting) VALUES `+
+			`(1, 'alice', true, false), (1, 'bob', false, true)`,
+		`INSERT INTO meeting_expected (meetings_id, nickname) VALUES (1, 'alice'), (1, 'bob')`,
+		`INSERT INTO meeting_snapshots (meetings_id, voting, attending_voting, taken) `+
+			`VALUES (1, 1, 1, '2025-01-10 11:00:00+00:00')`,
+		`INSERT INTO meeting_snapshot_voters (meetings_id, nickname) VALUES (1, 'alice')`,
+		`INSERT INTO member_absent (nickname, committee_id, start_time, stop_time, status) `+
+			`VALUES ('bob', 1, '2025-03-01 00:00:00+00:00', '2025-03-31 00:00:00+00:00', 1)`,
+	)
+	return db
+}
+
+// exportJSON exports the committee with the given id as JSON.
+func exportJSON(t *testing.T, db *database.Database, committeeID int64) []byte {
+	t.Helper()
+	export, err := ExportCommittee(context.Background(), db, committeeID)
+	if err != nil {
+		t.Fatalf("exporting committee failed: %v", err)
+	}
+	if export == nil {
+		t.Fatal("committee not found")
+	}
+	data, err := json.MarshalIndent(export, "", "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func TestCommitteeExportRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	exported := exportJSON(t, exportFixture(t), 1)
+
+	// A fresh database only knows the admin.
+	db := newTestDatabase(t)
+	for range 2 { // Importing again leaves the state unchanged.
+		committeeID, err := ImportCommitteeJSON(ctx, db, bytes.NewReader(exported))
+		if err != nil {
+			t.Fatalf("importing committee failed: %v", err)
+		}
+		if reexported := exportJSON(t, db, committeeID); !bytes.Equal(exported, reexported) {
+			t.Fatalf("re-export differs:\n%s\nwant:\n%s", reexported, exported)
+		}
+	}
+
+	// The created users must not be able to log in.
+	var active int
+	if err := db.DB.QueryRowContext(ctx,
+		`SELECT count(*) FROM users WHERE nickname IN ('alice', 'bob') AND deactivated IS NULL`,
+	).Scan(&active); err != nil {
+		t.Fatal(err)
+	}
+	if active != 0 {
+		t.Errorf("got %d active imported users, want 0", active)
+	}
+}
+
+func TestImportCommitteeJSONInvalidMeetings(t *testing.T) {
+	var export CommitteeExport
+	if err := json.Unmarshal(exportJSON(t, exportFixture(t), 1), &export); err != nil {
+		t.Fatal(err)
+	}
+	for _, tc := range []struct {
+		name   string
+		modify func(*MeetingExport)
+	}{
+		{"stop before start", func(m *MeetingExport) {
+			m.StopTime = m.StartTime.Add(-1)
+		}},
+		{"no duration", func(m *MeetingExport) {
+			m.StopTime = m.StartTime
+		}},
+		{"overlapping", func(m *MeetingExport) {
+			// The first meeting is at 2025-01-10 10:00 - 11:00.
+			m.StartTime = export.Meetings[0].StartTime.Add(30 * time.Minute)
+			m.StopTime = m.StartTime.Add(time.Hour)
+		}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			meeting := *export.Meetings[1]
+			tc.modify(&meeting)
+			modified := export
+			modified.Meetings = []*MeetingExport{export.Meetings[0], &meeting}
+			data, err := json.Marshal(&modified)
+			if err != nil {
+				t.Fatal(err)
+			}
+			db := newTestDatabase(t)
+			_, err = ImportCommitteeJSON(context.Background(), db, bytes.NewReader(data))
+			if !errors.Is(err, ErrInvalidImport) {
+				t.Fatalf("got error %v, want %v", err, ErrInvalidImport)
+			}
+			if !strings.Contains(err.Error(), meeting.StartTime.Format("2006-01-02")) {
+				t.Errorf("error %q does not name the meeting", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,55 @@
+// This file is Free Software under the Apache-2.0 License
+// without warranty, see README.md and LICENSE for details.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileCopyrightText: 2025 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2025 Intevation GmbH <https://intevation.de>
+
+package models
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestCommitteeStoreModified(t *testing.T) {
+	db := newTestDatabase(t)
+	exec(t, db, `INSERT INTO committees (id, name, slug) VALUES (1, 'TC', 'tc')`)
+	ctx := context.Background()
+	load := func() *Committee {
+		t.Helper()
+		c, err := LoadCommittee(ctx, db, 1)
+		if err != nil || c == nil {
+			t.Fatalf("loading committee failed: %v", err)
+		}
+		return c
+	}
+	first, second := load(), load()
+
+	first.Name = "First"
+	if err := first.Store(ctx, db); err != nil {
+		t.Fatalf("storing failed: %v", err)
+	}
+	if first.Version != second.Version+1 {
+		t.Errorf("got version %d, want %d", first.Version, second.Version+1)
+	}
+	// The stale update is rejected.
+	second.Name = "Second"
+	if err := second.Store(ctx, db); !errors.Is(err, ErrModified) {
+		t.Fatalf("got error %v, want %v", err, ErrModified)
+	}
+	if c := load(); c.Name != "First" || c.Version != first.Version {
+		t.Errorf("got committee %q in version %d", c.Name, c.Version)
+	}
+	// Updates based on the current version succeed.
+	third := load()
+	third.Name = "Third"
+	if err := third.Store(ctx, db); err != nil {
+		t.Fatalf("storing failed: %v", err)
+	}
+	if c := load(); c.Name != "Third" {
+		t.Errorf("got committee %q", c.Name)
+	}
+}
@@ -0,0 +1,84 @@
+// This file is Free Software under the Apache-2.0 License
+// without warranty, see README.md and LICENSE for details.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileCopyrightText: 2025 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2025 Intevation GmbH <https://intevation.de>
+
+package models
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/csaf-auxiliary/oasis-quorum-calculator/pkg/database"
+)
+
+// Concluder concludes meetings which were left running
+// past their scheduled stop time.
+type Concluder struct {
+	grace      time.Duration
+	interval   time.Duration
+	db         *database.Database
+	onConclude func(context.Context, *Meeting)
+}
+
+// NewConcluder creates a new concluder which concludes meetings
+// running longer than grace after their stop time. It checks
+// for such meetings every interval. onConclude is called with
+// every concluded meeting if it is not nil.
+func NewConcluder(
+	grace, interval time.Duration,
+	db *database.Database,
+	onConclude func(context.Context, *Meeting),
+) *Concluder {
+	return &Concluder{
+		grace:      grace,
+		interval:   interval,
+		db:         db,
+		onConclude: onConclude,
+	}
+}
+
+// Run concludes the overrunning meetings on a schedule.
+func (c *Concluder) Run(ctx context.Context) {
+	c.conclude(ctx)
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			c.conclude(ctx)
+		}
+	}
+}
+
+// conclude concludes the overrunning meetings as of their stop time.
+func (c *Concluder) conclude(ctx context.Context) {
+	meetings, err := LoadOverrunningMeetings(ctx, c.db, c.grace)
+	if err != nil {
+		slog.ErrorContext(ctx, "loading overrunning meetings failed", "error", err)
+		return
+	}
+	for _, m := range meetings {
+		if err := ChangeMeetingStatus(
+			ctx, c.db,
+			m.ID, m.CommitteeID, MeetingConcluded,
+			m.StopTime,
+		); err != nil {
+			slog.WarnContext(ctx, "concluding overrunning meeting failed",
+				"meeting", m.ID, "committee", m.CommitteeID, "error", err)
+			continue
+		}
+		slog.InfoContext(ctx, "overrunning meeting concluded",
+			"meeting", m.ID, "committee", m.CommitteeID)
+		if c.onConclude != nil {
+			m.Status = MeetingConcluded
+			c.onConclude(ctx, m)
+		}
+	}
+}
@@ -0,0 +1,57 @@
+// This file is Free Software under the Apache-2.0 License
+// without warranty, see README.md and LICENSE for details.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileCopyrightText: 2025 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2025 Intevation GmbH <https://intevation.de>
+
+package models
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/csaf-auxiliary/oasis-quorum-calculator/pkg/config"
+	"github.com/csaf-auxiliary/oasis-quorum-calculator/pkg/database"
+)
+
+// newTestDatabase creates an empty database which
+// is removed at the end of the test.
+func newTestDatabase(t *testing.T) *database.Database {
+	t.Helper()
+	db, err := database.NewDatabase(context.Background(), &config.Database{
+		DatabaseURL:        filepath.Join(t.TempDir(), "oqcd.sqlite"),
+		Driver:             "sqlite3",
+		Migrate:            true,
+		MaxOpenConnections: 1,
+		MaxIdleConnections: 1,
+	})
+	if err != nil {
+		t.Fatalf("creating database failed: %v", err)
+	}
+	t.Cleanup(func() { db.DB.Close() })
+	return db
+}
+
+// exec executes the given statements as a fixture.
+func exec(t *testing.T, db *database.Database, stmts ...string) {
+	t.Helper()
+	for _, stmt := range stmts {
+		if _, err := db.DB.Exec(stmt); err != nil {
+			t.Fatalf("fixture %q failed: %v", stmt, err)
+		}
+	}
+}
+
+// at parses a time in the format used by the fixtures.
+func at(t *testing.T, s string) time.Time {
+	t.Helper()
+	ts, err := time.Parse("2006-01-02 15:04", s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ts
+}
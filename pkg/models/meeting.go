@@ -17,6 +17,7 @@ import (
 	"log/slog"
 	"maps"
 	"slices"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -25,6 +26,18 @@ import (
 	"github.com/csaf-auxiliary/oasis-quorum-calculator/pkg/misc"
 )
 
+var (
+	// ErrInvalidMeeting is returned when storing a meeting
+	// which fails the validation.
+	ErrInvalidMeeting = errors.New("invalid meeting")
+	// ErrMeetingConcluded is returned if a concluded meeting
+	// should be changed.
+	ErrMeetingConcluded = errors.New("meeting concluded")
+	// ErrMeetingCollides is returned if a meeting overlaps
+	// another meeting of the same committee.
+	ErrMeetingCollides = errors.New("meeting collides")
+)
+
 // MeetingStatus represents the current status of a meeting.
 type MeetingStatus int
 
@@ -46,6 +59,14 @@ type Meeting struct {
 	StartTime   time.Time
 	StopTime    time.Time
 	Description *string
+	Agenda      *string
+	CreatedAt   *time.Time
+	UpdatedAt   *time.Time
+	// ConcludedAt is the time the meeting was concluded.
+	// It is nil if the meeting is not concluded or the time is unknown.
+	ConcludedAt *time.Time
+	// Version is increased with every update to detect concurrent edits.
+	Version int64
 }
 
 // Quorum is the quorum of this meeting.
@@ -56,16 +77,45 @@ type Quorum struct {
 	Attending       int
 	NonVoting       int
 	Member          int
+	Observer        int
 }
 
-// Attendees is a map from nicknames to (attended, voting rights).
-type Attendees map[string]bool
+// Presence is the presence of a user in a meeting.
+type Presence int
+
+const (
+	// Absent means the user did not attend.
+	Absent Presence = iota
+	// PresentNonVoting means the user attended without taking part in votes.
+	PresentNonVoting
+	// PresentVoting means the user attended and took part in votes.
+	PresentVoting
+)
+
+// Attendance is the attendance of a user in a meeting.
+type Attendance struct {
+	Presence Presence
+	// VotingAllowed records if the user had voting rights
+	// at the time of attending.
+	VotingAllowed bool
+}
+
+// Attendees is a map from nicknames to their attendance.
+// Users who did not attend are not in the map.
+type Attendees map[string]Attendance
 
 // MeetingData captures the main data of a meeting.
 type MeetingData struct {
 	Meeting   *Meeting
 	Attendees Attendees
-	Quorum    *Quorum
+	Expected  Expected // Nil if all members are expected.
+	Quorum    *Quorum  // Nil for gatherings.
+}
+
+// IsGathering returns true if the meeting is a gathering
+// and therefore has no quorum.
+func (md *MeetingData) IsGathering() bool {
+	return md.Meeting.Gathering
 }
 
 // MeetingsOverview the an overview over a list of meetings.
@@ -73,13 +123,27 @@ type MeetingsOverview struct {
 	Data           []*MeetingData
 	UsersHistories UsersHistories
 	Users          []*User // Only basic user data, no memberships.
+	Total          int64   // Number of all meetings of the committee.
 }
 
+// AbsentStatus is the approval state of an excused absent.
+type AbsentStatus int
+
+const (
+	// AbsentRequested is an absent requested by a member.
+	AbsentRequested AbsentStatus = iota
+	// AbsentApproved is an absent approved by a chair.
+	AbsentApproved
+	// AbsentRejected is an absent rejected by a chair.
+	AbsentRejected
+)
+
 // MemberAbsent represents a time range where a member is absent.
 type MemberAbsent struct {
 	Name      string
 	StartTime time.Time
 	StopTime  time.Time
+	Status    AbsentStatus
 }
 
 // MemberAbsents is a slice of excused member absents.
@@ -108,13 +172,45 @@ type Meetings []*Meeting
 
 // Attended checks if a given user attended.
 func (a Attendees) Attended(nickname string) bool {
-	_, ok := a[nickname]
-	return ok
+	return a[nickname].Presence != Absent
 }
 
-// Voting checks if a given has voting rights.
+// Voting checks if a given user had voting rights when attending.
 func (a Attendees) Voting(nickname string) bool {
-	return a[nickname]
+	return a[nickname].VotingAllowed
+}
+
+// Votes checks if a given user attended and took part in votes.
+func (a Attendees) Votes(nickname string) bool {
+	return a[nickname].Presence == PresentVoting
+}
+
+// NonVoting checks if a given user attended without taking part in votes.
+func (a Attendees) NonVoting(nickname string) bool {
+	return a[nickname].Presence == PresentNonVoting
+}
+
+// attendance builds an attendance from the columns of the attendees table.
+func attendance(votingAllowed, nonVoting bool) Attendance {
+	presence := PresentVoting
+	if nonVoting {
+		presence = PresentNonVoting
+	}
+	return Attendance{Presence: presence, VotingAllowed: votingAllowed}
+}
+
+// String implements [fmt.Stringer].
+func (p Presence) String() string {
+	switch p {
+	case Absent:
+		return "absent"
+	case PresentNonVoting:
+		return "present-nonvoting"
+	case PresentVoting:
+		return "present-voting"
+	default:
+		return fmt.Sprintf("unknown presence (%d)", p)
+	}
 }
 
 // String implements [fmt.Stringer].
@@ -145,6 +241,34 @@ func ParseMeetingStatus(s string) (MeetingStatus, error) {
 	}
 }
 
+// String implements [fmt.Stringer].
+func (as AbsentStatus) String() string {
+	switch as {
+	case AbsentRequested:
+		return "requested"
+	case AbsentApproved:
+		return "approved"
+	case AbsentRejected:
+		return "rejected"
+	default:
+		return fmt.Sprintf("unknown absent status (%d)", as)
+	}
+}
+
+// ParseAbsentStatus parses a given string to an absent status.
+func ParseAbsentStatus(s string) (AbsentStatus, error) {
+	switch strings.ToLower(s) {
+	case "requested":
+		return AbsentRequested, nil
+	case "approved":
+		return AbsentApproved, nil
+	case "rejected":
+		return AbsentRejected, nil
+	default:
+		return 0, fmt.Errorf("unknown absent status %q", s)
+	}
+}
+
 // MeetingFilter defines a filter mechanism for meetings.
 type MeetingFilter func(*Meeting) bool
 
@@ -183,6 +307,12 @@ func OverlapFilter(start, stop time.Time, exceptions ...int64) func(m *Meeting)
 	}
 }
 
+// Overrunning returns true if the meeting is still running
+// longer than the given grace period after its scheduled stop time.
+func (m *Meeting) Overrunning(now time.Time, grace time.Duration) bool {
+	return m.Status == MeetingRunning && now.After(m.StopTime.Add(grace))
+}
+
 // Duration returns duration of the meeting.
 func (m *Meeting) Duration() time.Duration {
 	return m.StopTime.Sub(m.StartTime)
@@ -198,6 +328,28 @@ func (ms Meetings) Contains(cond func(m *Meeting) bool) bool {
 	return slices.ContainsFunc(ms, cond)
 }
 
+// NextAfter returns the earliest not concluded meeting
+// starting after t. The meetings have to be sorted by start time.
+func (ms Meetings) NextAfter(t time.Time) *Meeting {
+	idx := sort.Search(len(ms), func(i int) bool {
+		return ms[i].StartTime.After(t)
+	})
+	for _, m := range ms[idx:] {
+		if m.Status != MeetingConcluded {
+			return m
+		}
+	}
+	return nil
+}
+
+// CurrentlyRunning returns the earliest running meeting.
+func (ms Meetings) CurrentlyRunning() *Meeting {
+	if idx := slices.IndexFunc(ms, RunningFilter); idx != -1 {
+		return ms[idx]
+	}
+	return nil
+}
+
 // LoadMeeting loads a meeting by its id.
 func LoadMeeting(
 	ctx context.Context, db *database.Database,
@@ -220,7 +372,8 @@ func LoadMeetingTx(
 		ID:          meetingID,
 		CommitteeID: committeeID,
 	}
-	const loadSQL = `SELECT status, gathering, start_time, stop_time, description ` +
+	const loadSQL = `SELECT status, gathering, start_time, stop_time, description, agenda, ` +
+		`created_at, updated_at, concluded_at, version ` +
 		`FROM meetings ` +
 		`WHERE id = ? AND committees_id = ?`
 	switch err := tx.QueryRowContext(ctx, loadSQL, meetingID, committeeID).Scan(
@@ -229,6 +382,11 @@ func LoadMeetingTx(
 		&meeting.StartTime,
 		&meeting.StopTime,
 		&meeting.Description,
+		&meeting.Agenda,
+		&meeting.CreatedAt,
+		&meeting.UpdatedAt,
+		&meeting.ConcludedAt,
+		&meeting.Version,
 	); {
 	case errors.Is(err, sql.ErrNoRows):
 		return nil, nil
@@ -238,74 +396,293 @@ func LoadMeetingTx(
 	return &meeting, nil
 }
 
-// LoadMeetings loads meetings for a sequence of committees.
+// sqlPlaceholders returns n comma separated placeholders
+// to be used in SQL IN clauses.
+func sqlPlaceholders(n int) string {
+	return strings.Repeat(",?", n)[1:]
+}
+
+// MeetingsOptions restricts the meetings loaded by [LoadMeetingsFiltered].
+// Nil fields do not restrict the result.
+type MeetingsOptions struct {
+	// Since only includes meetings starting at or after this time.
+	Since *time.Time
+	// Until only includes meetings starting before this time.
+	Until *time.Time
+	// Status only includes meetings with this status.
+	Status *MeetingStatus
+	// Description only includes meetings whose description
+	// contains this text case-insensitively. Empty does not restrict.
+	Description string
+}
+
+// where returns the conditions and their arguments
+// to restrict the meetings to the options.
+func (mo *MeetingsOptions) where() (string, []any) {
+	if mo == nil {
+		return "", nil
+	}
+	var (
+		cond string
+		args []any
+	)
+	if mo.Since != nil {
+		cond += `AND unixepoch(start_time) >= unixepoch(?) `
+		args = append(args, mo.Since.UTC())
+	}
+	if mo.Until != nil {
+		cond += `AND unixepoch(start_time) < unixepoch(?) `
+		args = append(args, mo.Until.UTC())
+	}
+	if mo.Status != nil {
+		cond += `AND status = ? `
+		args = append(args, *mo.Status)
+	}
+	if mo.Description != "" {
+		cond += `AND description LIKE ? ESCAPE '\' `
+		args = append(args, misc.LikeContains(mo.Description))
+	}
+	return cond, args
+}
+
+// LoadMeetings loads meetings for a sequence of committees
+// ordered by their start time.
 func LoadMeetings(
 	ctx context.Context,
 	db *database.Database,
 	committees iter.Seq[int64],
+) (Meetings, error) {
+	return LoadMeetingsFiltered(ctx, db, committees, nil)
+}
+
+// LoadMeetingsFiltered loads the meetings for a sequence of committees
+// which match the given options ordered by their start time.
+func LoadMeetingsFiltered(
+	ctx context.Context,
+	db *database.Database,
+	committees iter.Seq[int64],
+	opts *MeetingsOptions,
 ) (Meetings, error) {
 	tx, err := db.DB.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
 	if err != nil {
 		return nil, err
 	}
 	defer tx.Rollback()
-	const loadSQL = `SELECT id, status, gathering, start_time, stop_time, description ` +
+	return LoadMeetingsFilteredTx(ctx, tx, committees, opts)
+}
+
+// LoadMeetingsFilteredTx loads the meetings for a sequence of committees
+// which match the given options ordered by their start time.
+func LoadMeetingsFilteredTx(
+	ctx context.Context,
+	tx *sql.Tx,
+	committees iter.Seq[int64],
+	opts *MeetingsOptions,
+) (Meetings, error) {
+	var args []any
+	for committee := range committees {
+		args = append(args, committee)
+	}
+	if len(args) == 0 {
+		return nil, nil
+	}
+	loadSQL := `SELECT id, committees_id, status, gathering, start_time, stop_time, description, agenda, ` +
+		`created_at, updated_at, concluded_at, version ` +
 		`FROM meetings ` +
-		`WHERE committees_id = ? ` +
-		`ORDER BY unixepoch(start_time)`
-	stmt, err := tx.PrepareContext(ctx, loadSQL)
+		`WHERE committees_id IN (` + sqlPlaceholders(len(args)) + `) `
+	cond, condArgs := opts.where()
+	loadSQL += cond + `ORDER BY unixepoch(start_time), id`
+	rows, err := tx.QueryContext(ctx, loadSQL, append(args, condArgs...)...)
 	if err != nil {
-		return nil, fmt.Errorf("preparing loadind meetings failed: %w", err)
+		return nil, fmt.Errorf("querying meetings failed: %w", err)
 	}
-	defer stmt.Close()
-	var meetings Meetings
+	return scanMeetings(rows)
+}
+
+// SearchMeetings loads a page of the meetings for a sequence of committees
+// which match the given options. The meetings are ordered by their start
+// time with the latest first. It returns the total number of matching
+// meetings, too.
+func SearchMeetings(
+	ctx context.Context,
+	db *database.Database,
+	committees iter.Seq[int64],
+	opts *MeetingsOptions,
+	offset, limit int64,
+) (Meetings, int64, error) {
+	var args []any
 	for committee := range committees {
-		rows, err := stmt.QueryContext(ctx, committee)
-		if err != nil {
-			return nil, fmt.Errorf("querying meetings failed: %w", err)
-		}
-		if err := func() error {
-			defer rows.Close()
-			for rows.Next() {
-				meeting := Meeting{CommitteeID: committee}
-				if err := rows.Scan(
-					&meeting.ID,
-					&meeting.Status,
-					&meeting.Gathering,
-					&meeting.StartTime,
-					&meeting.StopTime,
-					&meeting.Description,
-				); err != nil {
-					return nil
-				}
-				meetings = append(meetings, &meeting)
-			}
-			return rows.Err()
-		}(); err != nil {
+		args = append(args, committee)
+	}
+	if len(args) == 0 {
+		return nil, 0, nil
+	}
+	cond, condArgs := opts.where()
+	args = append(args, condArgs...)
+	where := `WHERE committees_id IN (` + sqlPlaceholders(len(args)-len(condArgs)) + `) ` + cond
+
+	tx, err := db.DB.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return nil, 0, err
+	}
+	defer tx.Rollback()
+
+	var total int64
+	if err := tx.QueryRowContext(ctx,
+		`SELECT count(*) FROM meetings `+where, args...,
+	).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("counting meetings failed: %w", err)
+	}
+	searchSQL := `SELECT id, committees_id, status, gathering, start_time, stop_time, description, agenda, ` +
+		`created_at, updated_at, concluded_at, version ` +
+		`FROM meetings ` + where +
+		`ORDER BY unixepoch(start_time) DESC, id DESC ` +
+		`LIMIT ? OFFSET ?`
+	rows, err := tx.QueryContext(ctx, searchSQL, append(args, limit, offset)...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("searching meetings failed: %w", err)
+	}
+	meetings, err := scanMeetings(rows)
+	if err != nil {
+		return nil, 0, err
+	}
+	return meetings, total, nil
+}
+
+// LoadHighlightMeetings loads the running meetings and the next
+// upcoming not concluded meeting after now for a sequence of committees
+// ordered by their start time. These are the meetings needed to find
+// the running and the next meeting of each committee.
+func LoadHighlightMeetings(
+	ctx context.Context,
+	db *database.Database,
+	committees iter.Seq[int64],
+	now time.Time,
+) (Meetings, error) {
+	var args []any
+	for committee := range committees {
+		args = append(args, committee)
+	}
+	if len(args) == 0 {
+		return nil, nil
+	}
+	now = now.UTC()
+	loadSQL := `SELECT id, committees_id, status, gathering, start_time, stop_time, description, agenda, ` +
+		`created_at, updated_at, concluded_at, version ` +
+		`FROM meetings m ` +
+		`WHERE committees_id IN (` + sqlPlaceholders(len(args)) + `) ` +
+		`AND (status = 1 ` + // MeetingRunning
+		`OR (status <> 2 ` + // MeetingConcluded
+		`AND unixepoch(start_time) > unixepoch(?) ` +
+		`AND NOT EXISTS (SELECT 1 FROM meetings e ` +
+		`WHERE e.committees_id = m.committees_id ` +
+		`AND e.status <> 2 ` +
+		`AND unixepoch(e.start_time) > unixepoch(?) ` +
+		`AND unixepoch(e.start_time) < unixepoch(m.start_time)))) ` +
+		`ORDER BY unixepoch(start_time), id`
+	rows, err := db.DB.QueryContext(ctx, loadSQL, append(args, now, now)...)
+	if err != nil {
+		return nil, fmt.Errorf("loading highlight meetings failed: %w", err)
+	}
+	return scanMeetings(rows)
+}
+
+// scanMeetings scans the meetings from the given rows.
+func scanMeetings(rows *sql.Rows) (Meetings, error) {
+	defer rows.Close()
+	var meetings Meetings
+	for rows.Next() {
+		var meeting Meeting
+		if err := rows.Scan(
+			&meeting.ID,
+			&meeting.CommitteeID,
+			&meeting.Status,
+			&meeting.Gathering,
+			&meeting.StartTime,
+			&meeting.StopTime,
+			&meeting.Description,
+			&meeting.Agenda,
+			&meeting.CreatedAt,
+			&meeting.UpdatedAt,
+			&meeting.ConcludedAt,
+			&meeting.Version,
+		); err != nil {
 			return nil, fmt.Errorf("scanning meetings failed: %w", err)
 		}
+		meetings = append(meetings, &meeting)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("querying meetings failed: %w", err)
 	}
 	return meetings, nil
 }
 
-// LoadLastNMeetingsTx loads the last n meetings.
-// If n < 0 all meetings are loaded.
-// The returned meetings are sorted lastest first.
+// LoadOverrunningMeetings loads the meetings which are still
+// running longer than the given grace period after their
+// scheduled stop time.
+func LoadOverrunningMeetings(
+	ctx context.Context,
+	db *database.Database,
+	grace time.Duration,
+) (Meetings, error) {
+	const loadSQL = `SELECT id, committees_id, status, gathering, start_time, stop_time, description, agenda, ` +
+		`created_at, updated_at, concluded_at, version ` +
+		`FROM meetings ` +
+		`WHERE status = ? AND unixepoch(stop_time) < unixepoch(?) ` +
+		`ORDER BY unixepoch(stop_time), id`
+	cutoff := time.Now().Add(-grace).UTC()
+	rows, err := db.DB.QueryContext(ctx, loadSQL, MeetingRunning, cutoff)
+	if err != nil {
+		return nil, fmt.Errorf("querying overrunning meetings failed: %w", err)
+	}
+	return scanMeetings(rows)
+}
+
+// LoadMeetingsLastModified returns the time of the last modification
+// of the meetings of a committee or their attendees.
+// Returns nil if the time is unknown.
+func LoadMeetingsLastModified(
+	ctx context.Context,
+	db *database.Database,
+	committeeID int64,
+) (*time.Time, error) {
+	const lastModifiedSQL = `SELECT max(t) FROM (` +
+		`SELECT unixepoch(updated_at) AS t FROM meetings WHERE committees_id = ? ` +
+		`UNION ALL ` +
+		`SELECT unixepoch(ac.time) FROM attendees_changes ac ` +
+		`JOIN meetings m ON ac.meetings_id = m.id ` +
+		`WHERE m.committees_id = ?) AS changes`
+	var last *int64
+	if err := db.DB.QueryRowContext(ctx, lastModifiedSQL, committeeID, committeeID).Scan(&last); err != nil {
+		return nil, fmt.Errorf("loading last modification of meetings failed: %w", err)
+	}
+	if last == nil {
+		return nil, nil
+	}
+	t := time.Unix(*last, 0).UTC()
+	return &t, nil
+}
+
+// LoadLastNMeetingsTx loads the last n meetings skipping
+// the offset latest ones.
+// If n < 0 all meetings are loaded and the offset is ignored.
+// The returned meetings are sorted lastest first. Meetings
+// starting at the same time are ordered by their id to
+// keep the selection of the last n meetings stable.
 func LoadLastNMeetingsTx(
 	ctx context.Context,
 	tx *sql.Tx,
 	committeeID int64,
-	limit int64,
+	offset, limit int64,
 ) (Meetings, error) {
-	const loadSQL = `SELECT id, status, gathering, start_time, stop_time, description ` +
+	const loadSQL = `SELECT id, status, gathering, start_time, stop_time, description, agenda, created_at, updated_at, concluded_at ` +
 		`FROM meetings ` +
 		`WHERE committees_id = ? ` +
-		`ORDER BY unixepoch(start_time) DESC `
-	var query string
+		`ORDER BY unixepoch(start_time) DESC, id DESC`
+	query := loadSQL
 	if limit >= 0 {
-		query = query + " LIMIT " + strconv.FormatInt(limit, 10)
-	} else {
-		query = loadSQL
+		query += " LIMIT " + strconv.FormatInt(limit, 10) +
+			" OFFSET " + strconv.FormatInt(max(offset, 0), 10)
 	}
 	rows, err := tx.QueryContext(ctx, query, committeeID)
 	if err != nil {
@@ -322,6 +699,10 @@ func LoadLastNMeetingsTx(
 			&meeting.StartTime,
 			&meeting.StopTime,
 			&meeting.Description,
+			&meeting.Agenda,
+			&meeting.CreatedAt,
+			&meeting.UpdatedAt,
+			&meeting.ConcludedAt,
 		); err != nil {
 			return nil, fmt.Errorf("scanning n last meetings failed: %w", err)
 		}
@@ -334,72 +715,220 @@ func LoadLastNMeetingsTx(
 }
 
 // DeleteMeetingsByID removes meetings the database identified by their id.
+// Concluded meetings are not removed. Returns the number of removed meetings.
 func DeleteMeetingsByID(
 	ctx context.Context,
 	db *database.Database,
 	committeeID int64,
 	meetingsIDs iter.Seq[int64],
-) error {
+) (int64, error) {
 	tx, err := db.DB.BeginTx(ctx, nil)
 	if err != nil {
-		return err
+		return 0, err
 	}
 	defer tx.Rollback()
 	const deleteSQL = `DELETE FROM meetings ` +
 		`WHERE id = ? AND committees_id = ? AND status <> 2` // MeetingConcluded
 	stmt, err := tx.PrepareContext(ctx, deleteSQL)
 	if err != nil {
-		return fmt.Errorf("preparing delete meetings failed: %w", err)
+		return 0, fmt.Errorf("preparing delete meetings failed: %w", err)
 	}
 	defer stmt.Close()
+	var deleted int64
 	for meetingID := range meetingsIDs {
-		if _, err := stmt.ExecContext(ctx, meetingID, committeeID); err != nil {
-			return fmt.Errorf("deleting meeting failed: %w", err)
+		result, err := stmt.ExecContext(ctx, meetingID, committeeID)
+		if err != nil {
+			return 0, fmt.Errorf("deleting meeting failed: %w", err)
+		}
+		n, err := result.RowsAffected()
+		if err != nil {
+			return 0, fmt.Errorf("counting deleted meetings failed: %w", err)
 		}
+		deleted += n
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return deleted, nil
+}
+
+// MoveMeeting moves a meeting to another committee.
+// The attendees follow the meeting. The member histories
+// of the committees are not changed.
+// Returns [ErrMeetingConcluded] if the meeting is concluded,
+// [ErrCommitteeArchived] if the target committee is archived,
+// [ErrMeetingCollides] if the meeting overlaps a meeting of the
+// target committee and [ErrAlreadyRunning] if the meeting is running
+// and the target committee already has a running meeting.
+// Moving a not existing meeting does nothing.
+func MoveMeeting(
+	ctx context.Context,
+	db *database.Database,
+	meetingID, fromCommitteeID, toCommitteeID int64,
+) error {
+	tx, err := db.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	meeting, err := LoadMeetingTx(ctx, tx, meetingID, fromCommitteeID)
+	switch {
+	case err != nil:
+		return err
+	case meeting == nil || fromCommitteeID == toCommitteeID:
+		return nil
+	case meeting.Status == MeetingConcluded:
+		return ErrMeetingConcluded
+	}
+	const archivedSQL = `SELECT archived FROM committees WHERE id = ?`
+	var archived bool
+	if err := tx.QueryRowContext(ctx, archivedSQL, toCommitteeID).Scan(&archived); err != nil {
+		return fmt.Errorf("loading committee failed: %w", err)
+	}
+	if archived {
+		return ErrCommitteeArchived
+	}
+	meetings, err := LoadMeetingsFilteredTx(ctx, tx, misc.Values(toCommitteeID), nil)
+	if err != nil {
+		return err
+	}
+	if meetings.Contains(OverlapFilter(meeting.StartTime, meeting.StopTime)) {
+		return ErrMeetingCollides
+	}
+	if meeting.Status == MeetingRunning && meetings.Contains(RunningFilter) {
+		return ErrAlreadyRunning
+	}
+	const moveSQL = `UPDATE meetings SET ` +
+		`committees_id = ?, ` +
+		`updated_at = CURRENT_TIMESTAMP, ` +
+		`version = version + 1 ` +
+		`WHERE id = ? AND committees_id = ?`
+	if _, err := tx.ExecContext(ctx, moveSQL, toCommitteeID, meetingID, fromCommitteeID); err != nil {
+		return fmt.Errorf("moving meeting failed: %w", err)
 	}
 	return tx.Commit()
 }
 
+// AttendanceChangeable returns true if members are allowed to change
+// their attendance at the given time. This is the case while the
+// meeting is running and for the grace period after its conclusion.
+func (m *Meeting) AttendanceChangeable(now time.Time, grace time.Duration) bool {
+	switch m.Status {
+	case MeetingRunning:
+		return true
+	case MeetingConcluded:
+		return grace > 0 && m.ConcludedAt != nil && now.Before(m.ConcludedAt.Add(grace))
+	default:
+		return false
+	}
+}
+
+// Validate checks if the meeting can be stored.
+// The returned errors wrap [ErrInvalidMeeting].
+func (m *Meeting) Validate() error {
+	switch {
+	case m.CommitteeID == 0:
+		return fmt.Errorf("%w: no committee", ErrInvalidMeeting)
+	case m.StartTime.IsZero():
+		return fmt.Errorf("%w: no start time", ErrInvalidMeeting)
+	case m.StopTime.IsZero():
+		return fmt.Errorf("%w: no stop time", ErrInvalidMeeting)
+	case !m.StopTime.After(m.StartTime):
+		return fmt.Errorf("%w: stop time not after start time", ErrInvalidMeeting)
+	}
+	return nil
+}
+
 // StoreNew stores a new meeting into the database.
+// Returns [ErrCommitteeArchived] if the committee is archived and
+// an error wrapping [ErrInvalidMeeting] if the validation fails.
 func (m *Meeting) StoreNew(ctx context.Context, db *database.Database) error {
-	const insertSQL = `INSERT INTO meetings ` +
-		`(gathering, committees_id, start_time, stop_time, description) ` +
-		`VALUES (?, ?, ?, ?, ?) ` +
-		`RETURNING id`
-	if err := db.DB.QueryRowContext(ctx, insertSQL,
+	if err := m.Validate(); err != nil {
+		return err
+	}
+	const (
+		archivedSQL = `SELECT archived FROM committees WHERE id = ?`
+		insertSQL   = `INSERT INTO meetings ` +
+			`(gathering, committees_id, start_time, stop_time, description, agenda, ` +
+			`created_at, updated_at) ` +
+			`VALUES (?, ?, ?, ?, ?, ?, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP) ` +
+			`RETURNING id, created_at, updated_at`
+	)
+	tx, err := db.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("starting transaction failed: %w", err)
+	}
+	defer tx.Rollback()
+	var archived bool
+	if err := tx.QueryRowContext(ctx, archivedSQL, m.CommitteeID).Scan(&archived); err != nil {
+		return fmt.Errorf("loading committee failed: %w", err)
+	}
+	if archived {
+		return ErrCommitteeArchived
+	}
+	if err := tx.QueryRowContext(ctx, insertSQL,
 		m.Gathering,
 		m.CommitteeID,
 		m.StartTime,
 		m.StopTime,
 		m.Description,
-	).Scan(&m.ID); err != nil {
+		m.Agenda,
+	).Scan(&m.ID, &m.CreatedAt, &m.UpdatedAt); err != nil {
 		return fmt.Errorf("inserting meeting into database failed: %w", err)
 	}
-	return nil
+	return tx.Commit()
 }
 
 // Store updates a meeting in the database.
+// Returns an error wrapping [ErrInvalidMeeting] if the validation fails
+// and [ErrModified] if the meeting was updated since it was loaded.
 func (m *Meeting) Store(ctx context.Context, db *database.Database) error {
+	if err := m.Validate(); err != nil {
+		return err
+	}
+	tx, err := db.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
 	const updateSQL = `UPDATE meetings SET ` +
 		`gathering = ?, ` +
 		`start_time = ?,` +
 		`stop_time = ?,` +
-		`description = ? ` +
-		`WHERE id = ? AND committees_id = ?`
-	if _, err := db.DB.ExecContext(ctx, updateSQL,
+		`description = ?, ` +
+		`agenda = ?, ` +
+		`updated_at = CURRENT_TIMESTAMP, ` +
+		`version = version + 1 ` +
+		`WHERE id = ? AND committees_id = ? AND version = ? ` +
+		`RETURNING updated_at, version`
+	switch err := tx.QueryRowContext(ctx, updateSQL,
 		m.Gathering,
 		m.StartTime,
 		m.StopTime,
 		m.Description,
-		m.ID, m.CommitteeID); err != nil {
+		m.Agenda,
+		m.ID, m.CommitteeID, m.Version,
+	).Scan(&m.UpdatedAt, &m.Version); {
+	case errors.Is(err, sql.ErrNoRows):
+		// Meeting does not exist any more or was modified.
+		const modifiedSQL = `SELECT EXISTS(SELECT 1 FROM meetings WHERE id = ? AND committees_id = ?)`
+		var modified bool
+		if err := tx.QueryRowContext(ctx, modifiedSQL, m.ID, m.CommitteeID).Scan(&modified); err != nil {
+			return fmt.Errorf("checking meeting existence failed: %w", err)
+		}
+		if modified {
+			return ErrModified
+		}
+		return nil
+	case err != nil:
 		return fmt.Errorf("updating meeting failed: %w", err)
 	}
-	return nil
+	return tx.Commit()
 }
 
 // Attendees loads the nicknames from the database which attend this meeting.
 func (m *Meeting) Attendees(ctx context.Context, db *database.Database) (Attendees, error) {
-	const loadAttendeesSQL = `SELECT nickname FROM attendees ` +
+	const loadAttendeesSQL = `SELECT nickname, voting_allowed, non_voting FROM attendees ` +
 		`WHERE meetings_id = ?`
 	attendees := make(Attendees)
 	rows, err := db.DB.QueryContext(ctx, loadAttendeesSQL, m.ID)
@@ -408,11 +937,14 @@ func (m *Meeting) Attendees(ctx context.Context, db *database.Database) (Attende
 	}
 	defer rows.Close()
 	for rows.Next() {
-		var attendee string
-		if err := rows.Scan(&attendee); err != nil {
+		var (
+			attendee                 string
+			votingAllowed, nonVoting bool
+		)
+		if err := rows.Scan(&attendee, &votingAllowed, &nonVoting); err != nil {
 			return nil, fmt.Errorf("scanning attendees failed: %w", err)
 		}
-		attendees[attendee] = true
+		attendees[attendee] = attendance(votingAllowed, nonVoting)
 	}
 	return attendees, nil
 }
@@ -467,12 +999,31 @@ func Unattend(
 }
 
 // Attend sets the attendees of a meeting to a given list.
+// The values of the sequence are the voting rights of the attendees.
+// The attendees are recorded as taking part in votes.
 func Attend(
 	ctx context.Context, db *database.Database,
 	meetingID int64,
 	seq iter.Seq2[string, bool],
 	accept time.Time,
 ) error {
+	return AttendPresence(ctx, db, meetingID, seq, PresentVoting, accept)
+}
+
+// AttendPresence sets the presence of a given list of attendees of a meeting.
+// The values of the sequence are the voting rights of the attendees.
+// Absent attendees are removed from the meeting.
+func AttendPresence(
+	ctx context.Context, db *database.Database,
+	meetingID int64,
+	seq iter.Seq2[string, bool],
+	presence Presence,
+	accept time.Time,
+) error {
+	if presence == Absent {
+		return Unattend(ctx, db, meetingID, seq, accept)
+	}
+	nonVoting := presence == PresentNonVoting
 	tx, err := db.DB.BeginTx(ctx, nil)
 	if err != nil {
 		return err
@@ -482,9 +1033,9 @@ func Attend(
 		checkSQL = `SELECT time FROM attendees_changes ` +
 			`WHERE meetings_id = ? AND nickname = ?`
 		insertSQL = `INSERT INTO attendees ` +
-			`(meetings_id, nickname, voting_allowed) ` +
-			`VALUES (?, ?, ?) ` +
-			`ON CONFLICT DO UPDATE SET voting_allowed = ?`
+			`(meetings_id, nickname, voting_allowed, non_voting) ` +
+			`VALUES (?, ?, ?, ?) ` +
+			`ON CONFLICT (meetings_id, nickname) DO UPDATE SET voting_allowed = ?, non_voting = ?`
 	)
 	insertStmt, err := tx.PrepareContext(ctx, insertSQL)
 	if err != nil {
@@ -510,7 +1061,8 @@ func Attend(
 				continue
 			}
 		}
-		if _, err := insertStmt.ExecContext(ctx, meetingID, nickname, voting, voting); err != nil {
+		if _, err := insertStmt.ExecContext(
+			ctx, meetingID, nickname, voting, nonVoting, voting, nonVoting); err != nil {
 			return fmt.Errorf("attend failed: %w", err)
 		}
 	}
@@ -518,32 +1070,118 @@ func Attend(
 }
 
 // UpdateAttendee updates a given attendee for given meeting.
+// Attendees are recorded as taking part in votes.
 func UpdateAttendee(
 	ctx context.Context, db *database.Database,
 	meetingID int64,
 	nickname string,
 	attend, voting bool,
+) error {
+	presence := Absent
+	if attend {
+		presence = PresentVoting
+	}
+	return UpdateAttendeePresence(ctx, db, meetingID, nickname, presence, voting)
+}
+
+// UpdateAttendeePresence updates the presence of a given attendee
+// for given meeting. voting are the voting rights of the attendee.
+func UpdateAttendeePresence(
+	ctx context.Context, db *database.Database,
+	meetingID int64,
+	nickname string,
+	presence Presence,
+	voting bool,
 ) error {
 	tx, err := db.DB.BeginTx(ctx, nil)
 	if err != nil {
 		return err
 	}
 	defer tx.Rollback()
+	if err := updateAttendeePresenceTx(ctx, tx, meetingID, nickname, presence, voting); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// CorrectAttendee updates a given attendee for a given meeting
+// which is already concluded. The voting rights of the attendee are
+// the ones at the start of the meeting. The status change of the
+// attendee caused by the conclusion is re-evaluated and the frozen
+// quorum of the meeting is refreshed keeping the time it was taken.
+func CorrectAttendee(
+	ctx context.Context, db *database.Database,
+	meetingID, committeeID int64,
+	nickname string,
+	attend bool,
+) error {
+	tx, err := db.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	meeting, err := LoadMeetingTx(ctx, tx, meetingID, committeeID)
+	if err != nil || meeting == nil {
+		return err
+	}
+	histories, err := LoadUsersHistoriesTx(ctx, tx, committeeID)
+	if err != nil {
+		return err
+	}
+	presence := Absent
+	if attend {
+		presence = PresentVoting
+	}
+	voting := histories[nickname].Status(meeting.StartTime) == Voting
+	if err := updateAttendeePresenceTx(ctx, tx, meetingID, nickname, presence, voting); err != nil {
+		return err
+	}
+	// Gatherings have no influence on voting so they have no snapshot.
+	if meeting.Gathering {
+		return tx.Commit()
+	}
+	taken, err := loadMeetingSnapshotTakenTx(ctx, tx, meetingID)
+	if err != nil {
+		return err
+	}
+	// Meetings concluded before snapshots were introduced have none.
+	if taken == nil {
+		return tx.Commit()
+	}
+	if err := correctMemberStatusTx(ctx, tx, nickname, committeeID, meeting, *taken); err != nil {
+		return err
+	}
+	if err := storeMeetingSnapshotTx(ctx, tx, meetingID, committeeID, *taken); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func updateAttendeePresenceTx(
+	ctx context.Context, tx *sql.Tx,
+	meetingID int64,
+	nickname string,
+	presence Presence,
+	voting bool,
+) error {
 	const (
-		insertSQL = `INSERT INTO attendees (meetings_id, nickname, voting_allowed) ` +
-			`VALUES (?, ?, ?) ` +
-			`ON CONFLICT DO UPDATE SET voting_allowed = ?`
+		insertSQL = `INSERT INTO attendees (meetings_id, nickname, voting_allowed, non_voting) ` +
+			`VALUES (?, ?, ?, ?) ` +
+			`ON CONFLICT (meetings_id, nickname) DO UPDATE SET voting_allowed = ?, non_voting = ?`
 		deleteSQL = `DELETE FROM attendees WHERE meetings_id = ? AND nickname = ?`
 	)
-	if attend {
-		_, err = tx.ExecContext(ctx, insertSQL, meetingID, nickname, voting, voting)
+	var err error
+	if presence != Absent {
+		nonVoting := presence == PresentNonVoting
+		_, err = tx.ExecContext(ctx, insertSQL,
+			meetingID, nickname, voting, nonVoting, voting, nonVoting)
 	} else {
 		_, err = tx.ExecContext(ctx, deleteSQL, meetingID, nickname)
 	}
 	if err != nil {
 		return fmt.Errorf("updating attendee failed: %w", err)
 	}
-	return tx.Commit()
+	return nil
 }
 
 // AttendedMeetings returns a set of ids of meetings the given user attended.
@@ -579,7 +1217,7 @@ func MeetingAttendeesTx(
 	tx *sql.Tx,
 	meetingID int64,
 ) (Attendees, error) {
-	const attendeesSQL = `SELECT nickname, voting_allowed FROM attendees ` +
+	const attendeesSQL = `SELECT nickname, voting_allowed, non_voting FROM attendees ` +
 		`WHERE meetings_id = ?`
 	rows, err := tx.QueryContext(ctx, attendeesSQL, meetingID)
 	if err != nil {
@@ -589,13 +1227,13 @@ func MeetingAttendeesTx(
 	attendees := Attendees{}
 	for rows.Next() {
 		var (
-			nickname string
-			voting   bool
+			nickname          string
+			voting, nonVoting bool
 		)
-		if err := rows.Scan(&nickname, &voting); err != nil {
+		if err := rows.Scan(&nickname, &voting, &nonVoting); err != nil {
 			return nil, fmt.Errorf("scanning meeting attendees failed: %w", err)
 		}
-		attendees[nickname] = voting
+		attendees[nickname] = attendance(voting, nonVoting)
 	}
 	if err := rows.Err(); err != nil {
 		return nil, fmt.Errorf("lading meeting attendees failed: %w", err)
@@ -603,6 +1241,47 @@ func MeetingAttendeesTx(
 	return attendees, nil
 }
 
+// MeetingAttendeesForMeetingsTx loads the attendees of several meetings
+// and their voting rights. The result is keyed by the meeting IDs.
+// Each of the given meetings has an entry.
+func MeetingAttendeesForMeetingsTx(
+	ctx context.Context,
+	tx *sql.Tx,
+	meetingIDs []int64,
+) (map[int64]Attendees, error) {
+	result := make(map[int64]Attendees, len(meetingIDs))
+	if len(meetingIDs) == 0 {
+		return result, nil
+	}
+	args := make([]any, len(meetingIDs))
+	for i, id := range meetingIDs {
+		args[i] = id
+		result[id] = Attendees{}
+	}
+	attendeesSQL := `SELECT meetings_id, nickname, voting_allowed, non_voting FROM attendees ` +
+		`WHERE meetings_id IN (` + sqlPlaceholders(len(meetingIDs)) + `)`
+	rows, err := tx.QueryContext(ctx, attendeesSQL, args...)
+	if err != nil {
+		return nil, fmt.Errorf("loading meetings attendees failed: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var (
+			meetingID         int64
+			nickname          string
+			voting, nonVoting bool
+		)
+		if err := rows.Scan(&meetingID, &nickname, &voting, &nonVoting); err != nil {
+			return nil, fmt.Errorf("scanning meetings attendees failed: %w", err)
+		}
+		result[meetingID][nickname] = attendance(voting, nonVoting)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("loading meetings attendees failed: %w", err)
+	}
+	return result, nil
+}
+
 // PreviousMeetingTx the id of the meeting before the given meeting.
 // Returns false as the second value if there isn't any.
 func PreviousMeetingTx(
@@ -627,6 +1306,52 @@ func PreviousMeetingTx(
 	return prevID, true, nil
 }
 
+// RunningMeeting is a running meeting together with the name of its committee.
+type RunningMeeting struct {
+	Meeting
+	CommitteeName string
+}
+
+// LoadRunningMeetings loads the running meetings of all committees
+// ordered by their start time.
+func LoadRunningMeetings(ctx context.Context, db *database.Database) ([]*RunningMeeting, error) {
+	const loadSQL = `SELECT m.id, m.committees_id, c.name, m.status, m.gathering, ` +
+		`m.start_time, m.stop_time, m.description, m.agenda, m.created_at, m.updated_at, m.concluded_at ` +
+		`FROM meetings m JOIN committees c ON m.committees_id = c.id ` +
+		`WHERE m.status = 1 ` + // MeetingRunning
+		`ORDER BY unixepoch(m.start_time), c.name`
+	rows, err := db.DB.QueryContext(ctx, loadSQL)
+	if err != nil {
+		return nil, fmt.Errorf("querying running meetings failed: %w", err)
+	}
+	defer rows.Close()
+	var meetings []*RunningMeeting
+	for rows.Next() {
+		var meeting RunningMeeting
+		if err := rows.Scan(
+			&meeting.ID,
+			&meeting.CommitteeID,
+			&meeting.CommitteeName,
+			&meeting.Status,
+			&meeting.Gathering,
+			&meeting.StartTime,
+			&meeting.StopTime,
+			&meeting.Description,
+			&meeting.Agenda,
+			&meeting.CreatedAt,
+			&meeting.UpdatedAt,
+			&meeting.ConcludedAt,
+		); err != nil {
+			return nil, fmt.Errorf("scanning running meetings failed: %w", err)
+		}
+		meetings = append(meetings, &meeting)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("querying running meetings failed: %w", err)
+	}
+	return meetings, nil
+}
+
 // HasCommitteeRunningMeeting checks if a committee has a running meeting.
 func HasCommitteeRunningMeeting(
 	ctx context.Context,
@@ -691,11 +1416,13 @@ func IsGatheringMeetingTx(
 }
 
 // LoadMeetingsOverview loads the last meetings and gathers infos about them.
+// The offset and limit select a page of the meetings sorted latest first.
+// If limit < 0 all meetings are loaded.
 func LoadMeetingsOverview(
 	ctx context.Context,
 	db *database.Database,
 	committeeID int64,
-	limit int64,
+	offset, limit int64,
 ) (*MeetingsOverview, error) {
 	tx, err := db.DB.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
 	if err != nil {
@@ -703,16 +1430,38 @@ func LoadMeetingsOverview(
 	}
 	defer tx.Rollback()
 
-	meetings, err := LoadLastNMeetingsTx(ctx, tx, committeeID, limit)
+	const countSQL = `SELECT count(*) FROM meetings WHERE committees_id = ?`
+	var total int64
+	if err := tx.QueryRowContext(ctx, countSQL, committeeID).Scan(&total); err != nil {
+		return nil, fmt.Errorf("counting meetings failed: %w", err)
+	}
+
+	meetings, err := LoadLastNMeetingsTx(ctx, tx, committeeID, offset, limit)
 	if err != nil {
 		return nil, err
 	}
 
+	// The histories are loaded completely as the status of
+	// the members does not depend on the selected meetings.
+
 	histories, err := LoadUsersHistoriesTx(ctx, tx, committeeID)
 	if err != nil {
 		return nil, err
 	}
 
+	meetingIDs := make([]int64, len(meetings))
+	for i, meeting := range meetings {
+		meetingIDs[i] = meeting.ID
+	}
+	meetingsAttendees, err := MeetingAttendeesForMeetingsTx(ctx, tx, meetingIDs)
+	if err != nil {
+		return nil, err
+	}
+	meetingsExpected, err := loadMeetingsExpectedTx(ctx, tx, meetingIDs)
+	if err != nil {
+		return nil, err
+	}
+
 	data := make([]*MeetingData, 0, len(meetings))
 
 	neededUsers := map[string]bool{}
@@ -722,10 +1471,7 @@ func LoadMeetingsOverview(
 				neededUsers[nickname] = true
 			}
 		}
-		attendees, err := MeetingAttendeesTx(ctx, tx, meeting.ID)
-		if err != nil {
-			return nil, err
-		}
+		attendees := meetingsAttendees[meeting.ID]
 		for nickname := range attendees {
 			neededUsers[nickname] = true
 		}
@@ -733,40 +1479,39 @@ func LoadMeetingsOverview(
 		data = append(data, &MeetingData{
 			Meeting:   meeting,
 			Attendees: attendees,
+			Expected:  meetingsExpected[meeting.ID],
 		})
 	}
 
-	users := make([]*User, 0, len(neededUsers))
-	for nickname := range neededUsers {
-		user, err := loadBasicUserTx(ctx, tx, nickname)
-		if err != nil {
-			return nil, err
-		}
-		if user != nil {
-			users = append(users, user)
+	users, err := loadBasicUsersTx(ctx, tx, slices.Collect(maps.Keys(neededUsers)))
+	if err != nil {
+		return nil, err
+	}
+
+	// Concluded meetings use the quora frozen at their conclusion.
+	var concluded []int64
+	for _, meeting := range meetings {
+		if meeting.Status == MeetingConcluded && !meeting.Gathering {
+			concluded = append(concluded, meeting.ID)
 		}
 	}
+	snapshots, err := loadMeetingSnapshotsTx(ctx, tx, concluded)
+	if err != nil {
+		return nil, err
+	}
 
 	// Calculate the quora
 	for _, d := range data {
 		meeting := d.Meeting
-		if meeting.Gathering {
+		if d.IsGathering() {
 			continue
 		}
-		var voting, attending int
-		for nickname := range neededUsers {
-			history := histories[nickname]
-			if history.Status(meeting.StartTime) == Voting {
-				voting++
-				if d.Attendees.Attended(nickname) {
-					attending++
-				}
-			}
-		}
-		d.Quorum = &Quorum{
-			Voting:          voting,
-			AttendingVoting: attending,
+		if quorum := snapshots[meeting.ID]; quorum != nil {
+			d.Quorum = quorum
+			continue
 		}
+		// Open meetings and meetings concluded without a snapshot.
+		d.Quorum, _ = historicQuorum(meeting, d.Attendees, histories, d.Expected)
 	}
 
 	// Sort user by firstname, lastname and nickname.
@@ -775,13 +1520,92 @@ func LoadMeetingsOverview(
 		Data:           data,
 		Users:          users,
 		UsersHistories: histories,
+		Total:          total,
 	}
 	return overview, nil
 }
 
+// MemberAttendance is the attendance of a member in a meeting.
+type MemberAttendance struct {
+	User       *User // Only basic user data, no memberships.
+	Status     MemberStatus
+	Attendance Attendance
+	Excused    bool
+	// Expected is false if the meeting is restricted
+	// to invited users and the member is not invited.
+	Expected bool
+}
+
+// LoadMeetingAttendance loads the attendance of the members
+// of a committee and of the attendees of one of its meetings.
+// The result is sorted by firstname, lastname and nickname.
+// It returns nil if the meeting does not exist.
+func LoadMeetingAttendance(
+	ctx context.Context,
+	db *database.Database,
+	meetingID, committeeID int64,
+) ([]*MemberAttendance, error) {
+	tx, err := db.DB.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	meeting, err := LoadMeetingTx(ctx, tx, meetingID, committeeID)
+	if err != nil || meeting == nil {
+		return nil, err
+	}
+	attendees, err := MeetingAttendeesTx(ctx, tx, meetingID)
+	if err != nil {
+		return nil, err
+	}
+	histories, err := LoadUsersHistoriesTx(ctx, tx, committeeID)
+	if err != nil {
+		return nil, err
+	}
+	expected, err := LoadMeetingExpectedTx(ctx, tx, meetingID)
+	if err != nil {
+		return nil, err
+	}
+
+	neededUsers := map[string]bool{}
+	for nickname, history := range histories {
+		if history.Status(meeting.StopTime) != NoMember {
+			neededUsers[nickname] = true
+		}
+	}
+	for nickname := range attendees {
+		neededUsers[nickname] = true
+	}
+	users, err := loadBasicUsersTx(ctx, tx, slices.Collect(maps.Keys(neededUsers)))
+	if err != nil {
+		return nil, err
+	}
+	slices.SortFunc(users, (*User).Compare)
+
+	result := make([]*MemberAttendance, 0, len(users))
+	for _, user := range users {
+		ma := &MemberAttendance{
+			User:       user,
+			Status:     histories[user.Nickname].Status(meeting.StopTime),
+			Attendance: attendees[user.Nickname],
+			Expected:   expected.Includes(user.Nickname),
+		}
+		if ma.Attendance.Presence == Absent {
+			if ma.Excused, err = IsUserExcusedFromMeetingTx(
+				ctx, tx, user.Nickname, committeeID, meeting.StopTime,
+			); err != nil {
+				return nil, err
+			}
+		}
+		result = append(result, ma)
+	}
+	return result, nil
+}
+
 // LoadAbsent loads all absent times of the members of a committee.
 func LoadAbsent(ctx context.Context, db *database.Database, committeeID int64) (MemberAbsents, error) {
-	const loadSQL = `SELECT nickname, start_time, stop_time FROM member_absent ` +
+	const loadSQL = `SELECT nickname, start_time, stop_time, status FROM member_absent ` +
 		`WHERE committee_id = ? ` +
 		`ORDER BY stop_time DESC`
 	rows, err := db.DB.QueryContext(ctx, loadSQL, committeeID)
@@ -792,7 +1616,7 @@ func LoadAbsent(ctx context.Context, db *database.Database, committeeID int64) (
 	var memberAbsents MemberAbsents
 	for rows.Next() {
 		var m MemberAbsent
-		if err := rows.Scan(&m.Name, &m.StartTime, &m.StopTime); err != nil {
+		if err := rows.Scan(&m.Name, &m.StartTime, &m.StopTime, &m.Status); err != nil {
 			return nil, fmt.Errorf("scanning member absent failed: %w", err)
 		}
 		memberAbsents = append(memberAbsents, &m)
@@ -806,13 +1630,14 @@ func LoadAbsent(ctx context.Context, db *database.Database, committeeID int64) (
 // StoreNew stores a new excused absent into the database.
 func (m *MemberAbsent) StoreNew(ctx context.Context, db *database.Database, committeeID int64) error {
 	const insertSQL = `INSERT INTO member_absent ` +
-		`(nickname, start_time, stop_time, committee_id) ` +
-		`VALUES (?, ?, ?, ?)`
+		`(nickname, start_time, stop_time, committee_id, status) ` +
+		`VALUES (?, ?, ?, ?, ?)`
 	if _, err := db.DB.ExecContext(ctx, insertSQL,
 		m.Name,
 		m.StartTime,
 		m.StopTime,
 		committeeID,
+		m.Status,
 	); err != nil {
 		return fmt.Errorf("inserting excused absent into database failed: %w", err)
 	}
@@ -846,11 +1671,52 @@ func DeleteAbsentEntries(
 	return tx.Commit()
 }
 
+// UpdateAbsentEntriesStatus sets the status of excused absent entries
+// identified by their nickname and start time.
+func UpdateAbsentEntriesStatus(
+	ctx context.Context,
+	db *database.Database,
+	committeeID int64,
+	entries iter.Seq2[string, time.Time],
+	status AbsentStatus,
+) error {
+	tx, err := db.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	const updateSQL = `UPDATE member_absent SET status = ? ` +
+		`WHERE nickname = ? AND unixepoch(start_time) = unixepoch(?) AND committee_id = ?`
+	stmt, err := tx.PrepareContext(ctx, updateSQL)
+	if err != nil {
+		return fmt.Errorf("preparing update excused absent entries failed: %w", err)
+	}
+	defer stmt.Close()
+	for nickname, startTime := range entries {
+		if _, err := stmt.ExecContext(ctx, status, nickname, startTime, committeeID); err != nil {
+			return fmt.Errorf("updating absent entry failed: %w", err)
+		}
+	}
+	return tx.Commit()
+}
+
 // MemberAbsentOverlapFilter creates a filter which checks if an excused
 // absent overlaps a given interval.
+// The intervals are treated as half-open [start, stop) so that
+// ranges which only touch at their bounds do not overlap.
+// Rejected absents are ignored.
 func MemberAbsentOverlapFilter(nickname string, start, stop time.Time) func(m *MemberAbsent) bool {
 	return func(m *MemberAbsent) bool {
-		return nickname == m.Name && !(m.StopTime.Before(start) || stop.Before(m.StartTime))
+		return nickname == m.Name && m.Status != AbsentRejected &&
+			m.StartTime.Before(stop) && start.Before(m.StopTime)
+	}
+}
+
+// MemberAbsentNicknameFilter creates a filter which matches the
+// excused absents of a given member.
+func MemberAbsentNicknameFilter(nickname string) func(m *MemberAbsent) bool {
+	return func(m *MemberAbsent) bool {
+		return nickname == m.Name
 	}
 }
 
@@ -876,7 +1742,7 @@ func startOfYear(year int) time.Time {
 func (ma MemberAbsents) CheckMaximumAbsentTime(maxTime time.Duration, nickname string) bool {
 	durations := map[int]time.Duration{}
 	for _, m := range ma {
-		if m.Name == nickname {
+		if m.Name == nickname && m.Status != AbsentRejected {
 			if m.StartTime.Year() != m.StopTime.Year() {
 				durations[m.StartTime.Year()] = endOfYear(m.StartTime.Year()).Sub(m.StartTime) + durations[m.StartTime.Year()]
 				durations[m.StopTime.Year()] = m.StopTime.Sub(startOfYear(m.StopTime.Year())) + durations[m.StopTime.Year()]
@@ -0,0 +1,127 @@
+// This file is Free Software under the Apache-2.0 License
+// without warranty, see README.md and LICENSE for details.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileCopyrightText: 2025 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2025 Intevation GmbH <https://intevation.de>
+
+package models
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/csaf-auxiliary/oasis-quorum-calculator/pkg/database"
+)
+
+// MeetingDocument is a document like the minutes attached to a meeting.
+// The content itself is stored outside the database.
+type MeetingDocument struct {
+	ID          int64
+	MeetingID   int64
+	Filename    string
+	ContentType string
+	UploadedBy  *string
+	Uploaded    time.Time
+}
+
+// AddMeetingDocument stores the meta data of a new document into the database.
+// The ID of the document is passed to the store function which
+// is expected to persist the content. If store fails nothing is
+// added to the database.
+func AddMeetingDocument(
+	ctx context.Context,
+	db *database.Database,
+	doc *MeetingDocument,
+	store func(id int64) error,
+) error {
+	tx, err := db.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	const insertSQL = `INSERT INTO meeting_documents ` +
+		`(meetings_id, filename, content_type, uploaded_by) ` +
+		`VALUES (?, ?, ?, ?) ` +
+		`RETURNING id, uploaded`
+	if err := tx.QueryRowContext(ctx, insertSQL,
+		doc.MeetingID,
+		doc.Filename,
+		doc.ContentType,
+		doc.UploadedBy,
+	).Scan(&doc.ID, &doc.Uploaded); err != nil {
+		return fmt.Errorf("inserting meeting document failed: %w", err)
+	}
+	if err := store(doc.ID); err != nil {
+		return fmt.Errorf("storing meeting document failed: %w", err)
+	}
+	return tx.Commit()
+}
+
+// LoadMeetingDocuments loads the meta data of the documents
+// of a meeting ordered by their upload time.
+func LoadMeetingDocuments(
+	ctx context.Context,
+	db *database.Database,
+	meetingID int64,
+) ([]*MeetingDocument, error) {
+	const loadSQL = `SELECT id, filename, content_type, uploaded_by, uploaded ` +
+		`FROM meeting_documents ` +
+		`WHERE meetings_id = ? ` +
+		`ORDER BY unixepoch(uploaded), id`
+	rows, err := db.DB.QueryContext(ctx, loadSQL, meetingID)
+	if err != nil {
+		return nil, fmt.Errorf("querying meeting documents failed: %w", err)
+	}
+	defer rows.Close()
+	var docs []*MeetingDocument
+	for rows.Next() {
+		doc := MeetingDocument{MeetingID: meetingID}
+		if err := rows.Scan(
+			&doc.ID,
+			&doc.Filename,
+			&doc.ContentType,
+			&doc.UploadedBy,
+			&doc.Uploaded,
+		); err != nil {
+			return nil, fmt.Errorf("scanning meeting documents failed: %w", err)
+		}
+		docs = append(docs, &doc)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("querying meeting documents failed: %w", err)
+	}
+	return docs, nil
+}
+
+// LoadMeetingDocument loads the meta data of a document of a meeting
+// in a given committee. Returns nil if there is no such document.
+func LoadMeetingDocument(
+	ctx context.Context,
+	db *database.Database,
+	documentID, meetingID, committeeID int64,
+) (*MeetingDocument, error) {
+	doc := MeetingDocument{
+		ID:        documentID,
+		MeetingID: meetingID,
+	}
+	const loadSQL = `SELECT filename, content_type, uploaded_by, uploaded ` +
+		`FROM meeting_documents md JOIN meetings m ON md.meetings_id = m.id ` +
+		`WHERE md.id = ? AND md.meetings_id = ? AND m.committees_id = ?`
+	switch err := db.DB.QueryRowContext(ctx, loadSQL, documentID, meetingID, committeeID).Scan(
+		&doc.Filename,
+		&doc.ContentType,
+		&doc.UploadedBy,
+		&doc.Uploaded,
+	); {
+	case errors.Is(err, sql.ErrNoRows):
+		return nil, nil
+	case err != nil:
+		return nil, fmt.Errorf("loading meeting document failed: %w", err)
+	}
+	return &doc, nil
+}
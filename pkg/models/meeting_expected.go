@@ -0,0 +1,138 @@
+// This file is Free Software under the Apache-2.0 License
+// without warranty, see README.md and LICENSE for details.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileCopyrightText: 2025 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2025 Intevation GmbH <https://intevation.de>
+
+package models
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"iter"
+
+	"github.com/csaf-auxiliary/oasis-quorum-calculator/pkg/database"
+)
+
+// Expected is the set of nicknames of the users expected at a meeting.
+// A nil set means that all members of the committee are expected.
+type Expected map[string]bool
+
+// Includes returns true if the user with the given nickname is expected.
+func (e Expected) Includes(nickname string) bool {
+	return e == nil || e[nickname]
+}
+
+// SetMeetingExpected replaces the users expected at a meeting.
+// An empty list removes the explicit list so that all
+// members of the committee are expected again.
+// Returns [ErrMeetingConcluded] if the meeting is concluded.
+func SetMeetingExpected(
+	ctx context.Context,
+	db *database.Database,
+	meetingID, committeeID int64,
+	nicknames iter.Seq[string],
+) error {
+	tx, err := db.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	meeting, err := LoadMeetingTx(ctx, tx, meetingID, committeeID)
+	switch {
+	case err != nil:
+		return err
+	case meeting == nil:
+		return nil
+	case meeting.Status == MeetingConcluded:
+		// The quorum of concluded meetings must not change.
+		return ErrMeetingConcluded
+	}
+	const (
+		deleteSQL = `DELETE FROM meeting_expected WHERE meetings_id = ?`
+		insertSQL = `INSERT INTO meeting_expected (meetings_id, nickname) VALUES (?, ?) ` +
+			`ON CONFLICT DO NOTHING`
+	)
+	if _, err := tx.ExecContext(ctx, deleteSQL, meetingID); err != nil {
+		return fmt.Errorf("deleting expected users failed: %w", err)
+	}
+	stmt, err := tx.PrepareContext(ctx, insertSQL)
+	if err != nil {
+		return fmt.Errorf("preparing expected users failed: %w", err)
+	}
+	defer stmt.Close()
+	for nickname := range nicknames {
+		if _, err := stmt.ExecContext(ctx, meetingID, nickname); err != nil {
+			return fmt.Errorf("inserting expected user failed: %w", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("storing expected users failed: %w", err)
+	}
+	return nil
+}
+
+// LoadMeetingExpected loads the users expected at a meeting.
+// Returns nil if all members of the committee are expected.
+func LoadMeetingExpected(ctx context.Context, db *database.Database, meetingID int64) (Expected, error) {
+	tx, err := db.DB.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+	return LoadMeetingExpectedTx(ctx, tx, meetingID)
+}
+
+// LoadMeetingExpectedTx is like [LoadMeetingExpected]
+// but runs inside a transaction.
+func LoadMeetingExpectedTx(ctx context.Context, tx *sql.Tx, meetingID int64) (Expected, error) {
+	expected, err := loadMeetingsExpectedTx(ctx, tx, []int64{meetingID})
+	if err != nil {
+		return nil, err
+	}
+	return expected[meetingID], nil
+}
+
+// loadMeetingsExpectedTx loads the users expected at the given meetings.
+// Meetings without an explicit list are not contained in the result.
+func loadMeetingsExpectedTx(
+	ctx context.Context,
+	tx *sql.Tx,
+	meetingIDs []int64,
+) (map[int64]Expected, error) {
+	if len(meetingIDs) == 0 {
+		return nil, nil
+	}
+	ids := make([]any, len(meetingIDs))
+	for i, id := range meetingIDs {
+		ids[i] = id
+	}
+	loadSQL := `SELECT meetings_id, nickname FROM meeting_expected ` +
+		`WHERE meetings_id IN (` + sqlPlaceholders(len(ids)) + `)`
+	rows, err := tx.QueryContext(ctx, loadSQL, ids...)
+	if err != nil {
+		return nil, fmt.Errorf("loading expected users failed: %w", err)
+	}
+	defer rows.Close()
+	expected := map[int64]Expected{}
+	for rows.Next() {
+		var (
+			meetingID int64
+			nickname  string
+		)
+		if err := rows.Scan(&meetingID, &nickname); err != nil {
+			return nil, fmt.Errorf("scanning expected users failed: %w", err)
+		}
+		if expected[meetingID] == nil {
+			expected[meetingID] = Expected{}
+		}
+		expected[meetingID][nickname] = true
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("loading expected users failed: %w", err)
+	}
+	return expected, nil
+}
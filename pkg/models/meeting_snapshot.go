@@ -0,0 +1,179 @@
+// This file is Free Software under the Apache-2.0 License
+// without warranty, see README.md and LICENSE for details.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileCopyrightText: 2025 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2025 Intevation GmbH <https://intevation.de>
+
+package models
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// historicQuorum calculates the quorum of a meeting based on the
+// member histories at the start of the meeting. Only the expected
+// members are taken into account.
+// It returns the quorum and the sorted nicknames of the voting members.
+func historicQuorum(
+	meeting *Meeting,
+	attendees Attendees,
+	histories UsersHistories,
+	expected Expected,
+) (*Quorum, []string) {
+	var (
+		voters    []string
+		attending int
+	)
+	for nickname, history := range histories {
+		if expected.Includes(nickname) && history.Status(meeting.StartTime) == Voting {
+			voters = append(voters, nickname)
+			if attendees.Votes(nickname) {
+				attending++
+			}
+		}
+	}
+	slices.Sort(voters)
+	return &Quorum{
+		Voting:          len(voters),
+		AttendingVoting: attending,
+	}, voters
+}
+
+// storeMeetingSnapshotTx stores the quorum and the voting members
+// of a meeting as they are at the time of its conclusion.
+func storeMeetingSnapshotTx(
+	ctx context.Context,
+	tx *sql.Tx,
+	meetingID, committeeID int64,
+	timer time.Time,
+) error {
+	meeting, err := LoadMeetingTx(ctx, tx, meetingID, committeeID)
+	if err != nil {
+		return fmt.Errorf("loading meeting failed: %w", err)
+	}
+	if meeting == nil {
+		return nil
+	}
+	attendees, err := MeetingAttendeesTx(ctx, tx, meetingID)
+	if err != nil {
+		return err
+	}
+	histories, err := LoadUsersHistoriesTx(ctx, tx, committeeID)
+	if err != nil {
+		return err
+	}
+	expected, err := LoadMeetingExpectedTx(ctx, tx, meetingID)
+	if err != nil {
+		return err
+	}
+	quorum, voters := historicQuorum(meeting, attendees, histories, expected)
+	return writeMeetingSnapshotTx(ctx, tx, meetingID, quorum, voters, timer)
+}
+
+// writeMeetingSnapshotTx stores the given quorum and voting members
+// as the snapshot of a meeting replacing an existing one.
+func writeMeetingSnapshotTx(
+	ctx context.Context,
+	tx *sql.Tx,
+	meetingID int64,
+	quorum *Quorum,
+	voters []string,
+	taken time.Time,
+) error {
+	const (
+		snapshotSQL = `INSERT INTO meeting_snapshots ` +
+			`(meetings_id, voting, attending_voting, taken) ` +
+			`VALUES (?, ?, ?, ?) ` +
+			`ON CONFLICT (meetings_id) DO UPDATE SET ` +
+			`voting = excluded.voting, ` +
+			`attending_voting = excluded.attending_voting, ` +
+			`taken = excluded.taken`
+		deleteVotersSQL = `DELETE FROM meeting_snapshot_voters WHERE meetings_id = ?`
+		insertVoterSQL  = `INSERT INTO meeting_snapshot_voters ` +
+			`(meetings_id, nickname) VALUES (?, ?)`
+	)
+	if _, err := tx.ExecContext(ctx, snapshotSQL,
+		meetingID, quorum.Voting, quorum.AttendingVoting, taken,
+	); err != nil {
+		return fmt.Errorf("storing meeting snapshot failed: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, deleteVotersSQL, meetingID); err != nil {
+		return fmt.Errorf("deleting meeting snapshot voters failed: %w", err)
+	}
+	stmt, err := tx.PrepareContext(ctx, insertVoterSQL)
+	if err != nil {
+		return fmt.Errorf("preparing meeting snapshot voters failed: %w", err)
+	}
+	defer stmt.Close()
+	for _, nickname := range voters {
+		if _, err := stmt.ExecContext(ctx, meetingID, nickname); err != nil {
+			return fmt.Errorf("storing meeting snapshot voter failed: %w", err)
+		}
+	}
+	return nil
+}
+
+// loadMeetingSnapshotTakenTx returns the time the snapshot
+// of a meeting was taken. It returns nil if there is no snapshot.
+func loadMeetingSnapshotTakenTx(
+	ctx context.Context,
+	tx *sql.Tx,
+	meetingID int64,
+) (*time.Time, error) {
+	const takenSQL = `SELECT taken FROM meeting_snapshots WHERE meetings_id = ?`
+	var taken time.Time
+	switch err := tx.QueryRowContext(ctx, takenSQL, meetingID).Scan(&taken); {
+	case errors.Is(err, sql.ErrNoRows):
+		return nil, nil
+	case err != nil:
+		return nil, fmt.Errorf("loading meeting snapshot failed: %w", err)
+	}
+	return &taken, nil
+}
+
+// loadMeetingSnapshotsTx loads the quora stored at the conclusions
+// of the given meetings. Meetings without a snapshot are not
+// contained in the result.
+func loadMeetingSnapshotsTx(
+	ctx context.Context,
+	tx *sql.Tx,
+	meetingIDs []int64,
+) (map[int64]*Quorum, error) {
+	if len(meetingIDs) == 0 {
+		return nil, nil
+	}
+	ids := make([]any, len(meetingIDs))
+	for i, id := range meetingIDs {
+		ids[i] = id
+	}
+	loadSQL := `SELECT meetings_id, voting, attending_voting ` +
+		`FROM meeting_snapshots ` +
+		`WHERE meetings_id IN (` + sqlPlaceholders(len(ids)) + `)`
+	rows, err := tx.QueryContext(ctx, loadSQL, ids...)
+	if err != nil {
+		return nil, fmt.Errorf("querying meeting snapshots failed: %w", err)
+	}
+	defer rows.Close()
+	snapshots := map[int64]*Quorum{}
+	for rows.Next() {
+		var (
+			meetingID int64
+			quorum    Quorum
+		)
+		if err := rows.Scan(&meetingID, &quorum.Voting, &quorum.AttendingVoting); err != nil {
+			return nil, fmt.Errorf("scanning meeting snapshots failed: %w", err)
+		}
+		snapshots[meetingID] = &quorum
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("querying meeting snapshots failed: %w", err)
+	}
+	return snapshots, nil
+}
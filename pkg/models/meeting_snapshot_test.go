@@ -0,0 +1,128 @@
+// This file is Free Software under the Apache-2.0 License
+// without warranty, see README.md and LICENSE for details.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileCopyrightText: 2025 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2025 Intevation GmbH <https://intevation.de>
+
+package models
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/csaf-auxiliary/oasis-quorum-calculator/pkg/database"
+)
+
+// snapshot is a stored snapshot of a meeting.
+type snapshot struct {
+	voting, attendingVoting int
+	taken                   time.Time
+}
+
+func (s snapshot) equal(o snapshot) bool {
+	return s.voting == o.voting &&
+		s.attendingVoting == o.attendingVoting &&
+		s.taken.Equal(o.taken)
+}
+
+func loadSnapshot(t *testing.T, db *database.Database, meetingID int64) snapshot {
+	t.Helper()
+	const snapshotSQL = `SELECT voting, attending_voting, taken ` +
+		`FROM meeting_snapshots WHERE meetings_id = ?`
+	var s snapshot
+	if err := db.DB.QueryRow(snapshotSQL, meetingID).Scan(
+		&s.voting, &s.attendingVoting, &s.taken,
+	); err != nil {
+		t.Fatalf("loading snapshot failed: %v", err)
+	}
+	return s
+}
+
+// concludeStreak concludes the running meeting of a [streakFixture].
+func concludeStreak(t *testing.T, db *database.Database, meetingID int64, timer time.Time) {
+	t.Helper()
+	if err := ChangeMeetingStatus(
+		context.Background(), db,
+		meetingID, 1, MeetingConcluded, timer,
+	); err != nil {
+		t.Fatalf("concluding meeting failed: %v", err)
+	}
+}
+
+func TestCorrectAttendee(t *testing.T) {
+	for _, tc := range []struct {
+		name       string
+		status     MemberStatus
+		attendance string
+		attend     bool
+		concluded  MemberStatus
+		corrected  MemberStatus
+		attending  int
+	}{
+		{"revoke downgrade", Voting, "--", true, Member, Voting, 2},
+		{"apply downgrade", Voting, "-V", false, Voting, Member, 1},
+		{"revoke upgrade", Member, "NN", false, Voting, Member, 1},
+		{"apply upgrade", Member, "N-", true, Member, Voting, 1},
+		{"no change", Voting, "VV", false, Voting, Voting, 1},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			db, meetingID := streakFixture(t, tc.status, tc.attendance)
+			timer := at(t, meetingDay(len(tc.attendance)-1)+" 11:00")
+			concludeStreak(t, db, meetingID, timer)
+			if got := memberStatus(t, db, "alice"); got != tc.concluded {
+				t.Fatalf("got status %v after conclusion, want %v", got, tc.concluded)
+			}
+			if err := CorrectAttendee(
+				context.Background(), db, meetingID, 1, "alice", tc.attend,
+			); err != nil {
+				t.Fatalf("correcting attendee failed: %v", err)
+			}
+			if got := memberStatus(t, db, "alice"); got != tc.corrected {
+				t.Errorf("got status %v after correction, want %v", got, tc.corrected)
+			}
+			s := loadSnapshot(t, db, meetingID)
+			if !s.taken.Equal(timer) {
+				t.Errorf("snapshot taken at %v, want %v", s.taken, timer)
+			}
+			if s.attendingVoting != tc.attending {
+				t.Errorf("got %d attending voters, want %d", s.attendingVoting, tc.attending)
+			}
+		})
+	}
+}
+
+func TestCorrectAttendeeLaterStatusEdit(t *testing.T) {
+	db, meetingID := streakFixture(t, Voting, "--")
+	timer := at(t, meetingDay(1)+" 11:00")
+	concludeStreak(t, db, meetingID, timer)
+	before := loadSnapshot(t, db, meetingID)
+
+	// Status changes after the conclusion.
+	exec(t, db,
+		`INSERT INTO member_history (nickname, committees_id, status, since) VALUES `+
+			`('alice', 1, 4, '2025-01-02 12:00:00+00:00'), `+ // Observer
+			`('bob', 1, 0, '2025-01-02 12:00:00+00:00')`, // Member
+	)
+	if got := loadSnapshot(t, db, meetingID); !got.equal(before) {
+		t.Fatalf("snapshot changed from %+v to %+v", before, got)
+	}
+
+	if err := CorrectAttendee(
+		context.Background(), db, meetingID, 1, "alice", true,
+	); err != nil {
+		t.Fatalf("correcting attendee failed: %v", err)
+	}
+	// The status edited by hand is kept.
+	if got := memberStatus(t, db, "alice"); got != Observer {
+		t.Errorf("got status %v, want %v", got, Observer)
+	}
+	// The quorum is based on the status at the start of the meeting.
+	after := loadSnapshot(t, db, meetingID)
+	want := snapshot{voting: 2, attendingVoting: 2, taken: before.taken}
+	if !after.equal(want) {
+		t.Errorf("got snapshot %+v, want %+v", after, want)
+	}
+}
@@ -26,6 +26,9 @@ var (
 	// ErrNewerConcluded is returned if there is a newer meeting
 	// that is already concluded.
 	ErrNewerConcluded = errors.New("newer concluded")
+	// ErrNotRunning is returned if a meeting should be concluded
+	// which is not running.
+	ErrNotRunning = errors.New("not running")
 )
 
 // ChangeMeetingStatus changes the status of a given meeting in
@@ -52,6 +55,13 @@ func ChangeMeetingStatus(
 				return ErrAlreadyRunning
 			}
 		case MeetingConcluded:
+			// Only meetings which have been run can be concluded.
+			switch meeting, err := LoadMeetingTx(ctx, tx, meetingID, committeeID); {
+			case err != nil:
+				return err
+			case meeting != nil && meeting.Status != MeetingRunning:
+				return ErrNotRunning
+			}
 			// To ensure the correct time order of conclusions
 			// prevent that we conclude a meeting if a newer
 			// one already has been concluded.
@@ -78,14 +88,22 @@ func ChangeMeetingStatus(
 		if gathering {
 			return nil
 		}
-		prevMeetingID, hasPrev, err := PreviousMeetingTx(ctx, tx, meetingID)
-		if err != nil {
+		// Freeze the quorum so later changes of the
+		// member histories don't alter the past.
+		if err := storeMeetingSnapshotTx(ctx, tx, meetingID, committeeID, timer); err != nil {
 			return err
 		}
-		if !hasPrev { // We need two meetings.
+		// Lazy loading of the previous meetings as we don't need them in all cases.
+		// previous(0) is the concluded meeting directly before the current one.
+		previous := previousMeetingsTx(ctx, tx, meetingID, committeeID)
+
+		switch prev, err := previous(0); {
+		case err != nil:
+			return err
+		case prev == nil: // We need two meetings.
 			return nil
 		}
-		prevAttendees, err := MeetingAttendeesTx(ctx, tx, prevMeetingID)
+		currMeeting, err := LoadMeetingTx(ctx, tx, meetingID, committeeID)
 		if err != nil {
 			return err
 		}
@@ -98,90 +116,26 @@ func ChangeMeetingStatus(
 			return err
 		}
 
-		// Lazy previous loading as we don't need this in all cases.
-		var prevMeeting *Meeting
-		loadPrevMeeting := func() error {
-			if prevMeeting != nil {
-				return nil
-			}
-			var err error
-			prevMeeting, err = LoadMeetingTx(ctx, tx, meetingID, committeeID)
-			if err != nil {
-				err = fmt.Errorf("loading previous meeting failed: %w", err)
-			}
-			return err
-		}
-
 		// Lists of users to upgrade and downgrade.
 		var upgrades, downgrades []string
 
 		crit := MembershipByID(committeeID)
 		for _, user := range users {
 			ms := user.FindMembershipCriterion(crit)
-			if ms == nil || ms.Status == NoneVoting {
+			if ms == nil {
 				continue
 			}
-			votingCurr, wasInCurr := currAttendees[user.Nickname]
-			votingPrev, wasInPrev := prevAttendees[user.Nickname]
-
-			if !wasInCurr { // user was absent in current meeting.
-				if ms.Status == Voting { // currently a voting member
-					if !wasInPrev { // was absent in previous meeting.
-						// There could be three reasons:
-						// 1. User was not in the committee at end of the previous meeting.
-						// 2. User was not a voting member at this time.
-						// 3. User was a voting member but absent.
-						if err := loadPrevMeeting(); err != nil {
-							return err
-						}
-						memberStatus, wasMemberPrev, err := UserMemberStatusSinceTx(
-							ctx, tx,
-							user.Nickname, committeeID,
-							prevMeeting.StopTime)
-						if err != nil {
-							return err
-						}
-						isExcused, err := IsUserExcusedFromMeetingTx(ctx, tx, user.Nickname, committeeID, prevMeeting.StopTime)
-						if err != nil {
-							return err
-						}
-						switch {
-						case isExcused:
-							// user had approved absent
-						case !wasMemberPrev:
-							// user was not member so that is his/her first strike.
-						case memberStatus != Voting:
-							// user was a member but at not a voter -> first strike.
-						default:
-							// second strike
-							downgrades = append(downgrades, user.Nickname)
-						}
-					}
-				}
-				continue
-			}
-			// User was in current meeting
-			if !votingCurr && ms.Status == Member { // Currently a none voting member
-				if wasInPrev { // Was in previous too
-					if votingPrev { // We know user was a downgraded voter -> no upgrade.
-						continue
-					}
-					// To be upgrade the user needs to be a member at the
-					// time of the previous time.
-					if err := loadPrevMeeting(); err != nil {
-						return err
-					}
-					memberStatus, wasMemberPrev, err := UserMemberStatusSinceTx(
-						ctx, tx,
-						user.Nickname, committeeID,
-						prevMeeting.StopTime)
-					if err != nil {
-						return err
-					}
-					if wasMemberPrev && memberStatus == Member {
-						upgrades = append(upgrades, user.Nickname)
-					}
-				}
+			switch status, err := memberTransitionTx(
+				ctx, tx, user.Nickname, ms.Status, committeeID,
+				currMeeting, currAttendees, previous,
+			); {
+			case err != nil:
+				return err
+			case status == ms.Status:
+			case status == Voting:
+				upgrades = append(upgrades, user.Nickname)
+			default:
+				downgrades = append(downgrades, user.Nickname)
 			}
 		} // all committee users.
 
@@ -227,12 +181,14 @@ func UpdateMeetingStatus(
 		}
 	}
 
-	const updateSQL = `UPDATE meetings SET status = ? ` +
+	const updateSQL = `UPDATE meetings SET status = ?, updated_at = CURRENT_TIMESTAMP, ` +
+		`concluded_at = CASE WHEN ? THEN CURRENT_TIMESTAMP END ` +
 		`WHERE id = ? AND committees_id = ? ` +
 		`AND status <> 2` // Don't update concluded meetings.
 
 	result, err := tx.ExecContext(ctx, updateSQL,
 		meetingStatus,
+		meetingStatus == MeetingConcluded,
 		meetingID,
 		committeeID,
 	)
@@ -250,3 +206,440 @@ func UpdateMeetingStatus(
 	}
 	return tx.Commit()
 }
+
+// memberTransitionTx returns the status a member with the given
+// status has after the current meeting is concluded. Members are
+// downgraded after being absent twice in a row and upgraded after
+// attending twice in a row. previous are the meetings concluded before.
+func memberTransitionTx(
+	ctx context.Context,
+	tx *sql.Tx,
+	nickname string,
+	status MemberStatus,
+	committeeID int64,
+	currMeeting *Meeting,
+	currAttendees Attendees,
+	previous func(int) (*MeetingData, error),
+) (MemberStatus, error) {
+	// Persistent none voters and observers keep their status.
+	if status == NoneVoting || status == Observer {
+		return status, nil
+	}
+	curr, wasInCurr := currAttendees[nickname]
+	if !wasInCurr { // user was absent in current meeting.
+		if status != Voting { // currently not a voting member
+			return status, nil
+		}
+		// Being excused in the current meeting is no strike.
+		isExcused, err := IsUserExcusedFromMeetingTx(
+			ctx, tx, nickname, committeeID, currMeeting.StopTime)
+		if err != nil || isExcused {
+			return status, err
+		}
+		// Excused meetings before are neutral and skipped.
+		counting, err := countingMeetingTx(ctx, tx, nickname, committeeID, previous)
+		if err != nil || counting == nil {
+			return status, err
+		}
+		if _, wasIn := counting.Attendees[nickname]; wasIn {
+			return status, nil
+		}
+		// Was absent in previous meeting.
+		strike, err := strikeTx(ctx, tx, nickname, committeeID, counting.Meeting)
+		if err != nil || !strike {
+			return status, err
+		}
+		// second strike
+		return Member, nil
+	}
+	// User was in current meeting
+	if curr.VotingAllowed || status != Member { // Not a none voting member
+		return status, nil
+	}
+	counting, err := countingMeetingTx(ctx, tx, nickname, committeeID, previous)
+	if err != nil || counting == nil {
+		return status, err
+	}
+	step, err := upgradeStepTx(ctx, tx, nickname, committeeID, counting)
+	if err != nil || !step {
+		return status, err
+	}
+	return Voting, nil
+}
+
+// correctMemberStatusTx re-evaluates the status change of a member
+// caused by the conclusion of a meeting at the given time after the
+// attendance of the member was corrected. A status changed after
+// the conclusion is kept.
+func correctMemberStatusTx(
+	ctx context.Context,
+	tx *sql.Tx,
+	nickname string,
+	committeeID int64,
+	meeting *Meeting,
+	concluded time.Time,
+) error {
+	const (
+		laterSQL = `SELECT EXISTS (SELECT 1 FROM member_history ` +
+			`WHERE nickname = ? AND committees_id = ? AND unixepoch(since) > unixepoch(?))`
+		revertSQL = `DELETE FROM member_history ` +
+			`WHERE nickname = ? AND committees_id = ? AND unixepoch(since) = unixepoch(?)`
+		statusSQL = `SELECT status FROM member_history ` +
+			`WHERE nickname = ? AND committees_id = ? ` +
+			`ORDER BY unixepoch(since) DESC LIMIT 1`
+	)
+	var later bool
+	if err := tx.QueryRowContext(ctx, laterSQL, nickname, committeeID, concluded).Scan(&later); err != nil {
+		return fmt.Errorf("checking later member status failed: %w", err)
+	}
+	if later {
+		return nil
+	}
+	// Undo the status change of the conclusion.
+	if _, err := tx.ExecContext(ctx, revertSQL, nickname, committeeID, concluded); err != nil {
+		return fmt.Errorf("reverting member status failed: %w", err)
+	}
+	var status MemberStatus
+	switch err := tx.QueryRowContext(ctx, statusSQL, nickname, committeeID).Scan(&status); {
+	case errors.Is(err, sql.ErrNoRows):
+		return nil
+	case err != nil:
+		return fmt.Errorf("fetching member status failed: %w", err)
+	}
+	previous := previousMeetingsTx(ctx, tx, meeting.ID, committeeID)
+	switch prev, err := previous(0); {
+	case err != nil:
+		return err
+	case prev == nil: // We need two meetings.
+		return nil
+	}
+	attendees, err := MeetingAttendeesTx(ctx, tx, meeting.ID)
+	if err != nil {
+		return err
+	}
+	next, err := memberTransitionTx(
+		ctx, tx, nickname, status, committeeID, meeting, attendees, previous)
+	if err != nil || next == status {
+		return err
+	}
+	if err := UpdateUserCommitteeStatusTx(
+		ctx, tx,
+		misc.Attribute(misc.Values(nickname), next),
+		committeeID,
+		concluded,
+	); err != nil {
+		return fmt.Errorf("correcting member status failed: %w", err)
+	}
+	return nil
+}
+
+// previousMeetingsTx returns a function which lazily loads the
+// concluded meetings of a committee before the given meeting.
+// Index 0 is the meeting directly before it. Nil is returned
+// if there are no more meetings.
+func previousMeetingsTx(
+	ctx context.Context,
+	tx *sql.Tx,
+	meetingID, committeeID int64,
+) func(int) (*MeetingData, error) {
+	var (
+		prevMeetings     []*MeetingData
+		prevMeetingsDone bool
+	)
+	return func(i int) (*MeetingData, error) {
+		for len(prevMeetings) <= i && !prevMeetingsDone {
+			last := meetingID
+			if n := len(prevMeetings); n > 0 {
+				last = prevMeetings[n-1].Meeting.ID
+			}
+			prevID, hasPrev, err := PreviousMeetingTx(ctx, tx, last)
+			if err != nil {
+				return nil, err
+			}
+			if !hasPrev {
+				prevMeetingsDone = true
+				break
+			}
+			meeting, err := LoadMeetingTx(ctx, tx, prevID, committeeID)
+			if err != nil {
+				return nil, fmt.Errorf("loading previous meeting failed: %w", err)
+			}
+			attendees, err := MeetingAttendeesTx(ctx, tx, prevID)
+			if err != nil {
+				return nil, err
+			}
+			prevMeetings = append(prevMeetings, &MeetingData{
+				Meeting:   meeting,
+				Attendees: attendees,
+			})
+		}
+		if i < len(prevMeetings) {
+			return prevMeetings[i], nil
+		}
+		return nil, nil
+	}
+}
+
+// strikeTx returns true if the absence of a user in a meeting
+// counts as a strike. This is not the case if
+// 1. the user was excused,
+// 2. the user was not in the committee at the end of the meeting or
+// 3. the user was a member but not a voter at this time.
+func strikeTx(
+	ctx context.Context,
+	tx *sql.Tx,
+	nickname string,
+	committeeID int64,
+	meeting *Meeting,
+) (bool, error) {
+	isExcused, err := IsUserExcusedFromMeetingTx(
+		ctx, tx, nickname, committeeID, meeting.StopTime)
+	if err != nil || isExcused {
+		return false, err
+	}
+	memberStatus, wasMember, err := UserMemberStatusSinceTx(
+		ctx, tx,
+		nickname, committeeID,
+		meeting.StopTime)
+	if err != nil {
+		return false, err
+	}
+	return wasMember && memberStatus == Voting, nil
+}
+
+// countingMeetingTx returns the latest of the given meetings
+// in which the user was not excused. Excused meetings are neutral
+// and do not interrupt a streak, so they are skipped.
+// Returns nil if there is no such meeting.
+func countingMeetingTx(
+	ctx context.Context,
+	tx *sql.Tx,
+	nickname string,
+	committeeID int64,
+	meetings func(int) (*MeetingData, error),
+) (*MeetingData, error) {
+	for i := 0; ; i++ {
+		m, err := meetings(i)
+		if err != nil || m == nil {
+			return nil, err
+		}
+		isExcused, err := IsUserExcusedFromMeetingTx(
+			ctx, tx, nickname, committeeID, m.Meeting.StopTime)
+		if err != nil {
+			return nil, err
+		}
+		if !isExcused {
+			return m, nil
+		}
+	}
+}
+
+// upgradeStepTx returns true if the attendance of a user in a meeting
+// counts toward an upgrade. The user has to have attended without
+// voting rights as a member of the committee.
+func upgradeStepTx(
+	ctx context.Context,
+	tx *sql.Tx,
+	nickname string,
+	committeeID int64,
+	md *MeetingData,
+) (bool, error) {
+	attendance, wasIn := md.Attendees[nickname]
+	if !wasIn || attendance.VotingAllowed {
+		// Was absent or we know user was a downgraded voter -> no upgrade.
+		return false, nil
+	}
+	memberStatus, wasMember, err := UserMemberStatusSinceTx(
+		ctx, tx,
+		nickname, committeeID,
+		md.Meeting.StopTime)
+	if err != nil {
+		return false, err
+	}
+	return wasMember && memberStatus == Member, nil
+}
+
+// lastConcludedMeetingTx returns the id of the most recent concluded
+// meeting of a committee which is not a gathering.
+// Returns false as the second value if there isn't any.
+func lastConcludedMeetingTx(
+	ctx context.Context,
+	tx *sql.Tx,
+	committeeID int64,
+) (int64, bool, error) {
+	const lastSQL = `SELECT id FROM meetings ` +
+		`WHERE committees_id = ? ` +
+		`AND NOT gathering ` +
+		`AND status = 2 ` + // MeetingConcluded
+		`ORDER by unixepoch(start_time) DESC LIMIT 1`
+	var lastID int64
+	switch err := tx.QueryRowContext(ctx, lastSQL, committeeID).Scan(&lastID); {
+	case errors.Is(err, sql.ErrNoRows):
+		return 0, false, nil
+	case err != nil:
+		return 0, false, fmt.Errorf("find last concluded meeting failed: %w", err)
+	}
+	return lastID, true, nil
+}
+
+// MemberStreak is a member of a committee on the way to a status change
+// together with the meeting which started the current streak.
+type MemberStreak struct {
+	User    *User
+	Meeting *Meeting
+}
+
+// MembersAtRisk returns the voting members of a committee which
+// would lose their voting rights if they miss the next meeting.
+// Following the rules of [ChangeMeetingStatus] these are the voting
+// members who were absent in the last concluded meeting in which they
+// were not excused although they were voting members at this time.
+func MembersAtRisk(
+	ctx context.Context,
+	db *database.Database,
+	committeeID int64,
+) ([]*User, error) {
+	tx, err := db.DB.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	meetings, users, err := concludedMeetingsTx(ctx, tx, committeeID)
+	if err != nil || meetings == nil {
+		return nil, err
+	}
+	streaks, err := atRiskTx(ctx, tx, committeeID, meetings, users)
+	if err != nil {
+		return nil, err
+	}
+	atRisk := make([]*User, 0, len(streaks))
+	for _, streak := range streaks {
+		atRisk = append(atRisk, streak.User)
+	}
+	slices.SortFunc(atRisk, (*User).Compare)
+	return atRisk, nil
+}
+
+// MemberStreaks returns the members of a committee which are on the
+// way to a status change following the rules of [ChangeMeetingStatus].
+// atRisk are the voting members which would lose their voting rights
+// if they miss the next meeting. upgrading are the members without
+// voting rights which would gain them if they attend the next meeting.
+// The meeting of a streak is the one which started it.
+func MemberStreaks(
+	ctx context.Context,
+	db *database.Database,
+	committeeID int64,
+) (atRisk, upgrading []*MemberStreak, err error) {
+	tx, err := db.DB.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return nil, nil, err
+	}
+	defer tx.Rollback()
+
+	meetings, users, err := concludedMeetingsTx(ctx, tx, committeeID)
+	if err != nil || meetings == nil {
+		return nil, nil, err
+	}
+	if atRisk, err = atRiskTx(ctx, tx, committeeID, meetings, users); err != nil {
+		return nil, nil, err
+	}
+	crit := MembershipByID(committeeID)
+	for _, user := range users {
+		if ms := user.FindMembershipCriterion(crit); ms == nil || ms.Status != Member {
+			continue
+		}
+		counting, err := countingMeetingTx(ctx, tx, user.Nickname, committeeID, meetings)
+		if err != nil {
+			return nil, nil, err
+		}
+		if counting == nil {
+			continue
+		}
+		step, err := upgradeStepTx(ctx, tx, user.Nickname, committeeID, counting)
+		if err != nil {
+			return nil, nil, err
+		}
+		if step {
+			upgrading = append(upgrading, &MemberStreak{User: user, Meeting: counting.Meeting})
+		}
+	}
+	byUser := func(a, b *MemberStreak) int { return a.User.Compare(b.User) }
+	slices.SortFunc(atRisk, byUser)
+	slices.SortFunc(upgrading, byUser)
+	return atRisk, upgrading, nil
+}
+
+// concludedMeetingsTx returns a function which lazily loads the
+// concluded meetings of a committee starting with the last one
+// together with the users of the committee.
+// Returns nil if there is no concluded meeting.
+func concludedMeetingsTx(
+	ctx context.Context,
+	tx *sql.Tx,
+	committeeID int64,
+) (func(int) (*MeetingData, error), []*User, error) {
+	lastID, hasLast, err := lastConcludedMeetingTx(ctx, tx, committeeID)
+	if err != nil || !hasLast {
+		return nil, nil, err
+	}
+	last, err := LoadMeetingTx(ctx, tx, lastID, committeeID)
+	if err != nil {
+		return nil, nil, err
+	}
+	attendees, err := MeetingAttendeesTx(ctx, tx, lastID)
+	if err != nil {
+		return nil, nil, err
+	}
+	users, err := LoadCommitteeUsersTx(ctx, tx, committeeID, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	md := &MeetingData{Meeting: last, Attendees: attendees}
+	previous := previousMeetingsTx(ctx, tx, lastID, committeeID)
+	meetings := func(i int) (*MeetingData, error) {
+		if i == 0 {
+			return md, nil
+		}
+		return previous(i - 1)
+	}
+	return meetings, users, nil
+}
+
+// atRiskTx returns the users which are currently voting members
+// and have a strike in the last concluded meeting in which they
+// were not excused. The meeting of a streak is this meeting.
+func atRiskTx(
+	ctx context.Context,
+	tx *sql.Tx,
+	committeeID int64,
+	meetings func(int) (*MeetingData, error),
+	users []*User,
+) ([]*MemberStreak, error) {
+	var atRisk []*MemberStreak
+	crit := MembershipByID(committeeID)
+	for _, user := range users {
+		if ms := user.FindMembershipCriterion(crit); ms == nil || ms.Status != Voting {
+			continue
+		}
+		counting, err := countingMeetingTx(ctx, tx, user.Nickname, committeeID, meetings)
+		if err != nil {
+			return nil, err
+		}
+		if counting == nil {
+			continue
+		}
+		if _, wasIn := counting.Attendees[user.Nickname]; wasIn {
+			continue
+		}
+		strike, err := strikeTx(ctx, tx, user.Nickname, committeeID, counting.Meeting)
+		if err != nil {
+			return nil, err
+		}
+		if strike {
+			atRisk = append(atRisk, &MemberStreak{User: user, Meeting: counting.Meeting})
+		}
+	}
+	return atRisk, nil
+}
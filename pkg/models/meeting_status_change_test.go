@@ -0,0 +1,244 @@
+// This file is Free Software under the Apache-2.0 License
+// without warranty, see README.md and LICENSE for details.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileCopyrightText: 2025 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2025 Intevation GmbH <https://intevation.de>
+
+package models
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/csaf-auxiliary/oasis-quorum-calculator/pkg/database"
+)
+
+// meetingDay is the day of the i-th meeting of a streak fixture.
+func meetingDay(i int) string {
+	return fmt.Sprintf("2025-01-%02d", i+1)
+}
+
+// streakFixture creates a committee with one meeting per character
+// of the attendance of user alice. All meetings but the last are
+// concluded, the last one is running. The characters are:
+// 'V' attended voting, 'N' attended not voting,
+// '-' absent and 'E' absent but excused.
+func streakFixture(t *testing.T, status MemberStatus, attendance string) (*database.Database, int64) {
+	t.Helper()
+	db := newTestDatabase(t)
+	exec(t, db,
+		`INSERT INTO committees (id, name, slug) VALUES (1, 'TC', 'tc')`,
+		`INSERT INTO users (nickname, password) VALUES ('alice', 'x'), ('bob', 'x')`,
+		`INSERT INTO committee_roles (nickname, committees_id, committee_role_id) VALUES `+
+			`('alice', 1, 1), ('bob', 1, 1)`,
+		fmt.Sprintf(`INSERT INTO member_history (nickname, committees_id, status, since) VALUES `+
+			`('alice', 1, %d, '2024-01-01 00:00:00+00:00'), `+
+			`('bob', 1, 1, '2024-01-01 00:00:00+00:00')`, status),
+	)
+	for i, c := range attendance {
+		day, id := meetingDay(i), i+1
+		meetingStatus := MeetingConcluded
+		if i == len(attendance)-1 {
+			meetingStatus = MeetingRunning
+		}
+		exec(t, db,
+			fmt.Sprintf(`INSERT INTO meetings (id, committees_id, status, start_time, stop_time) `+
+				`VALUES (%d, 1, %d, '%s 10:00:00+00:00', '%s 11:00:00+00:00')`,
+				id, meetingStatus, day, day),
+			// bob always attends to have a quorum.
+			fmt.Sprintf(`INSERT INTO attendees (meetings_id, nickname, voting_allowed) `+
+				`VALUES (%d, 'bob', true)`, id))
+		switch c {
+		case 'V', 'N':
+			exec(t, db, fmt.Sprintf(`INSERT INTO attendees (meetings_id, nickname, voting_allowed) `+
+				`VALUES (%d, 'alice', %t)`, id, c == 'V'))
+		case 'E':
+			exec(t, db, fmt.Sprintf(`INSERT INTO member_absent `+
+				`(nickname, committee_id, start_time, stop_time, status) `+
+				`VALUES ('alice', 1, '%s 09:00:00+00:00', '%s 12:00:00+00:00', 1)`, day, day))
+		}
+	}
+	return db, int64(len(attendance))
+}
+
+// memberStatus returns the current status of a member of committee 1.
+func memberStatus(t *testing.T, db *database.Database, nickname string) MemberStatus {
+	t.Helper()
+	const statusSQL = `SELECT status FROM member_history ` +
+		`WHERE nickname = ? AND committees_id = 1 ` +
+		`ORDER BY unixepoch(since) DESC LIMIT 1`
+	var status MemberStatus
+	if err := db.DB.QueryRow(statusSQL, nickname).Scan(&status); err != nil {
+		t.Fatal(err)
+	}
+	return status
+}
+
+func TestChangeMeetingStatusStreaks(t *testing.T) {
+	for _, tc := range []struct {
+		name       string
+		status     MemberStatus
+		attendance string
+		want       MemberStatus
+	}{
+		{"second strike", Voting, "--", Member},
+		{"single meeting", Voting, "-", Voting},
+		{"attended before", Voting, "V-", Voting},
+		{"excused in current", Voting, "-E", Voting},
+		{"only excused before", Voting, "E-", Voting},
+		{"excused between strikes", Voting, "-E-", Member},
+		{"excused twice between strikes", Voting, "-EE-", Member},
+		{"attended before excused", Voting, "VE-", Voting},
+		{"second attendance", Member, "NN", Voting},
+		{"single attendance", Member, "N", Member},
+		{"absent before", Member, "-N", Member},
+		{"absent between attendances", Member, "N-N", Member},
+		{"excused between attendances", Member, "NEN", Voting},
+		{"excused twice between attendances", Member, "NEEN", Voting},
+		{"only excused before attendance", Member, "EN", Member},
+		{"absent in current", Member, "N-", Member},
+		{"persistent none voter", NoneVoting, "NN", NoneVoting},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			db, meetingID := streakFixture(t, tc.status, tc.attendance)
+			timer := at(t, meetingDay(len(tc.attendance)-1)+" 11:00")
+			if err := ChangeMeetingStatus(
+				context.Background(), db,
+				meetingID, 1, MeetingConcluded, timer,
+			); err != nil {
+				t.Fatalf("concluding meeting failed: %v", err)
+			}
+			if got := memberStatus(t, db, "alice"); got != tc.want {
+				t.Errorf("got status %v, want %v", got, tc.want)
+			}
+			if got := memberStatus(t, db, "bob"); got != Voting {
+				t.Errorf("bystander got status %v, want %v", got, Voting)
+			}
+		})
+	}
+}
+
+func TestMembersAtRiskExcused(t *testing.T) {
+	for _, tc := range []struct {
+		name       string
+		attendance string
+		atRisk     bool
+	}{
+		{"absent", "-", true},
+		{"attended", "V", false},
+		{"excused", "E", false},
+		{"absent before excused", "-E", true},
+		{"attended before excused", "VE", false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			// The trailing meeting is the running one.
+			db, _ := streakFixture(t, Voting, tc.attendance+"V")
+			users, err := MembersAtRisk(context.Background(), db, 1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := len(users) == 1 && users[0].Nickname == "alice"; got != tc.atRisk {
+				t.Errorf("got at risk %v, want %v", users, tc.atRisk)
+			}
+		})
+	}
+}
+
+func TestMemberStreaks(t *testing.T) {
+	db := newTestDatabase(t)
+	exec(t, db,
+		`INSERT INTO committees (id, name, slug) VALUES (1, 'TC', 'tc')`,
+		`INSERT INTO users (nickname, password) VALUES `+
+			`('max', 'x'), ('nina', 'x'), ('sam', 'x'), ('vera', 'x')`,
+		`INSERT INTO committee_roles (nickname, committees_id, committee_role_id) VALUES `+
+			`('max', 1, 1), ('nina', 1, 1), ('sam', 1, 1), ('vera', 1, 1)`,
+		`INSERT INTO member_history (nickname, committees_id, status, since) VALUES `+
+			`('max', 1, 0, '2024-01-01 00:00:00+00:00'), `+
+			`('nina', 1, 0, '2024-01-01 00:00:00+00:00'), `+
+			`('sam', 1, 1, '2024-01-01 00:00:00+00:00'), `+
+			`('vera', 1, 1, '2024-01-01 00:00:00+00:00')`,
+		`INSERT INTO meetings (id, committees_id, status, start_time, stop_time) VALUES `+
+			`(1, 1, 2, '2025-01-01 10:00:00+00:00', '2025-01-01 11:00:00+00:00'), `+
+			`(2, 1, 2, '2025-01-02 10:00:00+00:00', '2025-01-02 11:00:00+00:00'), `+
+			`(3, 1, 0, '2025-01-03 10:00:00+00:00', '2025-01-03 11:00:00+00:00')`,
+		// sam attends all, vera missed the last meeting, max attended
+		// without voting rights before being excused and nina missed both.
+		`INSERT INTO attendees (meetings_id, nickname, voting_allowed) VALUES `+
+			`(1, 'sam', true), (1, 'vera', true), (1, 'max', false), `+
+			`(2, 'sam', true)`,
+		`INSERT INTO member_absent (nickname, committee_id, start_time, stop_time, status) `+
+			`VALUES ('max', 1, '2025-01-02 00:00:00+00:00', '2025-01-02 23:59:00+00:00', 1)`,
+	)
+	atRisk, upgrading, err := MemberStreaks(context.Background(), db, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	check := func(kind string, streaks []*MemberStreak, nickname string, meetingID int64) {
+		t.Helper()
+		switch {
+		case len(streaks) != 1:
+			var got []string
+			for _, s := range streaks {
+				got = append(got, s.User.Nickname)
+			}
+			t.Errorf("%s: got %v, want [%s]", kind, got, nickname)
+		case streaks[0].User.Nickname != nickname:
+			t.Errorf("%s: got %s, want %s", kind, streaks[0].User.Nickname, nickname)
+		case streaks[0].Meeting.ID != meetingID:
+			t.Errorf("%s: streak of %s started in meeting %d, want %d",
+				kind, nickname, streaks[0].Meeting.ID, meetingID)
+		}
+	}
+	check("at risk", atRisk, "vera", 2)
+	check("upgrading", upgrading, "max", 1)
+
+	// The at risk members agree with the streaks.
+	users, err := MembersAtRisk(context.Background(), db, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(users) != 1 || users[0].Nickname != "vera" {
+		t.Errorf("members at risk: got %v, want [vera]", users)
+	}
+}
+
+func TestConcludeNotRunning(t *testing.T) {
+	db := newTestDatabase(t)
+	exec(t, db,
+		`INSERT INTO committees (id, name, slug) VALUES (1, 'TC', 'tc')`,
+		`INSERT INTO meetings (id, committees_id, status, start_time, stop_time) VALUES `+
+			`(1, 1, 0, '2025-01-01 10:00:00+00:00', '2025-01-01 11:00:00+00:00')`,
+	)
+	ctx := context.Background()
+	timer := at(t, "2025-01-01 11:00")
+	status := func() MeetingStatus {
+		t.Helper()
+		meeting, err := LoadMeeting(ctx, db, 1, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return meeting.Status
+	}
+	for _, step := range []struct {
+		status MeetingStatus
+		err    error
+		want   MeetingStatus
+	}{
+		{MeetingConcluded, ErrNotRunning, MeetingOnHold},
+		{MeetingRunning, nil, MeetingRunning},
+		{MeetingConcluded, nil, MeetingConcluded},
+		{MeetingConcluded, ErrNotRunning, MeetingConcluded},
+	} {
+		err := ChangeMeetingStatus(ctx, db, 1, 1, step.status, timer)
+		if !errors.Is(err, step.err) {
+			t.Fatalf("changing to %v: got error %v, want %v", step.status, err, step.err)
+		}
+		if got := status(); got != step.want {
+			t.Fatalf("changing to %v: got status %v, want %v", step.status, got, step.want)
+		}
+	}
+}
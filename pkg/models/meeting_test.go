@@ -0,0 +1,550 @@
+// This file is Free Software under the Apache-2.0 License
+// without warranty, see README.md and LICENSE for details.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileCopyrightText: 2025 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2025 Intevation GmbH <https://intevation.de>
+
+package models
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/csaf-auxiliary/oasis-quorum-calculator/pkg/database"
+	"github.com/csaf-auxiliary/oasis-quorum-calculator/pkg/misc"
+)
+
+func TestLoadLastNMeetingsTx(t *testing.T) {
+	db := newTestDatabase(t)
+	exec(t, db,
+		`INSERT INTO committees (id, name, slug) VALUES (1, 'TC', 'tc'), (2, 'SC', 'sc')`,
+		// Inserted out of order to not depend on the ids.
+		`INSERT INTO meetings (id, committees_id, start_time, stop_time) VALUES `+
+			`(1, 1, '2025-01-03 10:00:00+00:00', '2025-01-03 11:00:00+00:00'), `+
+			`(2, 1, '2025-01-05 10:00:00+00:00', '2025-01-05 11:00:00+00:00'), `+
+			`(3, 1, '2025-01-01 10:00:00+00:00', '2025-01-01 11:00:00+00:00'), `+
+			`(4, 1, '2025-01-04 10:00:00+00:00', '2025-01-04 11:00:00+00:00'), `+
+			`(5, 1, '2025-01-02 10:00:00+00:00', '2025-01-02 11:00:00+00:00'), `+
+			`(6, 2, '2025-01-06 10:00:00+00:00', '2025-01-06 11:00:00+00:00')`,
+	)
+	for _, tc := range []struct {
+		name          string
+		offset, limit int64
+		want          []int64
+	}{
+		{"last 2 of 5", 0, 2, []int64{2, 4}},
+		{"next 2 of 5", 2, 2, []int64{1, 5}},
+		{"beyond the end", 4, 2, []int64{3}},
+		{"none", 0, 0, nil},
+		{"all", 3, -1, []int64{2, 4, 1, 5, 3}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tx, err := db.DB.BeginTx(context.Background(), nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer tx.Rollback()
+			meetings, err := LoadLastNMeetingsTx(context.Background(), tx, 1, tc.offset, tc.limit)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []int64
+			for _, m := range meetings {
+				got = append(got, m.ID)
+			}
+			if !slices.Equal(got, tc.want) {
+				t.Errorf("got meetings %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+// searchFixture creates meetings in two committees.
+func searchFixture(t *testing.T) *database.Database {
+	t.Helper()
+	db := newTestDatabase(t)
+	exec(t, db,
+		`INSERT INTO committees (id, name, slug) VALUES (1, 'TC', 'tc'), (2, 'SC', 'sc')`,
+		`INSERT INTO meetings (id, committees_id, status, start_time, stop_time, description) VALUES `+
+			`(1, 1, 2, '2025-01-01 10:00:00+00:00', '2025-01-01 11:00:00+00:00', 'Kick-off'), `+
+			`(2, 1, 2, '2025-02-01 10:00:00+00:00', '2025-02-01 11:00:00+00:00', '100% budget'), `+
+			`(3, 1, 1, '2025-03-01 10:00:00+00:00', '2025-03-01 11:00:00+00:00', 'Budget_review'), `+
+			`(4, 1, 0, '2025-04-01 10:00:00+00:00', '2025-04-01 11:00:00+00:00', 'Budget review'), `+
+			`(5, 1, 0, '2025-05-01 10:00:00+00:00', '2025-05-01 11:00:00+00:00', NULL), `+
+			`(6, 2, 0, '2025-03-15 10:00:00+00:00', '2025-03-15 11:00:00+00:00', 'Budget review')`,
+	)
+	return db
+}
+
+func TestSearchMeetings(t *testing.T) {
+	db := searchFixture(t)
+	var (
+		onHold    = MeetingOnHold
+		concluded = MeetingConcluded
+		feb       = at(t, "2025-02-01 10:00")
+		apr       = at(t, "2025-04-01 10:00")
+	)
+	for _, tc := range []struct {
+		name       string
+		committees []int64
+		opts       *MeetingsOptions
+		want       []int64
+	}{
+		{"no options", []int64{1}, nil, []int64{5, 4, 3, 2, 1}},
+		{"no committees", nil, nil, nil},
+		{"all committees", []int64{1, 2}, nil, []int64{5, 4, 6, 3, 2, 1}},
+		{"status", []int64{1}, &MeetingsOptions{Status: &onHold}, []int64{5, 4}},
+		{"since inclusive", []int64{1}, &MeetingsOptions{Since: &feb}, []int64{5, 4, 3, 2}},
+		{"until exclusive", []int64{1}, &MeetingsOptions{Until: &apr}, []int64{3, 2, 1}},
+		{"description case-insensitive", []int64{1},
+			&MeetingsOptions{Description: "BUDGET"}, []int64{4, 3, 2}},
+		{"percent is literal", []int64{1},
+			&MeetingsOptions{Description: "0%"}, []int64{2}},
+		{"underscore is literal", []int64{1},
+			&MeetingsOptions{Description: "t_r"}, []int64{3}},
+		{"backslash is literal", []int64{1},
+			&MeetingsOptions{Description: `\`}, nil},
+		{"combined", []int64{1, 2}, &MeetingsOptions{
+			Since:       &feb,
+			Until:       &apr,
+			Description: "budget",
+		}, []int64{6, 3, 2}},
+		{"combined with status", []int64{1, 2}, &MeetingsOptions{
+			Since:       &feb,
+			Status:      &concluded,
+			Description: "budget",
+		}, []int64{2}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			meetings, total, err := SearchMeetings(
+				context.Background(), db, slices.Values(tc.committees), tc.opts, 0, 100)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []int64
+			for _, m := range meetings {
+				got = append(got, m.ID)
+			}
+			if !slices.Equal(got, tc.want) {
+				t.Errorf("got meetings %v, want %v", got, tc.want)
+			}
+			if total != int64(len(tc.want)) {
+				t.Errorf("got total %d, want %d", total, len(tc.want))
+			}
+		})
+	}
+
+	// Paging keeps the total.
+	meetings, total, err := SearchMeetings(
+		context.Background(), db, slices.Values([]int64{1}), nil, 1, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != 5 || len(meetings) != 2 || meetings[0].ID != 4 || meetings[1].ID != 3 {
+		t.Errorf("got page %v of %d, want [4 3] of 5", meetings, total)
+	}
+}
+
+func TestLoadHighlightMeetings(t *testing.T) {
+	db := searchFixture(t)
+	for _, tc := range []struct {
+		now  string
+		want []int64
+	}{
+		// The running meeting 3 has started early, so it is the next
+		// meeting of committee 1, too. 6 is the next one of committee 2.
+		{"2025-02-15 00:00", []int64{3, 6}},
+		// Meeting 6 has started but is not running.
+		{"2025-03-20 00:00", []int64{3, 4}},
+		{"2025-04-15 00:00", []int64{3, 5}},
+		{"2025-06-01 00:00", []int64{3}},
+	} {
+		meetings, err := LoadHighlightMeetings(
+			context.Background(), db, slices.Values([]int64{1, 2}), at(t, tc.now))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got []int64
+		for _, m := range meetings {
+			got = append(got, m.ID)
+		}
+		if !slices.Equal(got, tc.want) {
+			t.Errorf("%s: got meetings %v, want %v", tc.now, got, tc.want)
+		}
+	}
+}
+
+func TestAttendanceChangeable(t *testing.T) {
+	concluded := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	// Later edits must not extend the grace period.
+	updated := concluded.Add(time.Hour)
+	const grace = 10 * time.Minute
+	for _, tc := range []struct {
+		name        string
+		status      MeetingStatus
+		concludedAt *time.Time
+		grace       time.Duration
+		now         time.Time
+		want        bool
+	}{
+		{"running", MeetingRunning, nil, grace, concluded, true},
+		{"on hold", MeetingOnHold, nil, grace, concluded, false},
+		{"at conclusion", MeetingConcluded, &concluded, grace, concluded, true},
+		{"inside grace", MeetingConcluded, &concluded, grace, concluded.Add(grace - time.Second), true},
+		{"end of grace", MeetingConcluded, &concluded, grace, concluded.Add(grace), false},
+		{"after grace", MeetingConcluded, &concluded, grace, updated, false},
+		{"no grace", MeetingConcluded, &concluded, 0, concluded, false},
+		{"unknown conclusion", MeetingConcluded, nil, grace, concluded, false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			m := &Meeting{Status: tc.status, ConcludedAt: tc.concludedAt, UpdatedAt: &updated}
+			if got := m.AttendanceChangeable(tc.now, tc.grace); got != tc.want {
+				t.Errorf("got %t, want %t", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestConcludedAt(t *testing.T) {
+	db := newTestDatabase(t)
+	exec(t, db,
+		`INSERT INTO committees (id, name, slug) VALUES (1, 'TC', 'tc')`,
+		`INSERT INTO meetings (id, committees_id, status, start_time, stop_time) `+
+			`VALUES (1, 1, 1, '2025-03-01 10:00:00', '2025-03-01 11:00:00')`,
+	)
+	ctx := context.Background()
+	load := func() *Meeting {
+		t.Helper()
+		m, err := LoadMeeting(ctx, db, 1, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return m
+	}
+	if m := load(); m.ConcludedAt != nil {
+		t.Fatalf("running meeting has conclusion time %v", m.ConcludedAt)
+	}
+	if err := ChangeMeetingStatus(
+		ctx, db, 1, 1, MeetingConcluded, at(t, "2025-03-01 11:00"),
+	); err != nil {
+		t.Fatalf("concluding meeting failed: %v", err)
+	}
+	m := load()
+	if m.ConcludedAt == nil {
+		t.Fatal("concluded meeting has no conclusion time")
+	}
+	concludedAt := *m.ConcludedAt
+	// Edits after the conclusion don't reopen the grace period.
+	exec(t, db, `UPDATE meetings SET updated_at = datetime(CURRENT_TIMESTAMP, '+1 day')`)
+	m = load()
+	if !m.ConcludedAt.Equal(concludedAt) {
+		t.Errorf("conclusion time changed from %v to %v", concludedAt, m.ConcludedAt)
+	}
+	if m.AttendanceChangeable(m.UpdatedAt.Add(-time.Minute), time.Hour) {
+		t.Error("attendance changeable after the grace period")
+	}
+	if !m.AttendanceChangeable(concludedAt.Add(time.Minute), time.Hour) {
+		t.Error("attendance not changeable inside the grace period")
+	}
+}
+
+func TestMemberAbsentOverlapFilter(t *testing.T) {
+	// The existing absent of alice is from 10:00 to 12:00.
+	existing := MemberAbsents{{
+		Name:      "alice",
+		StartTime: at(t, "2025-01-01 10:00"),
+		StopTime:  at(t, "2025-01-01 12:00"),
+		Status:    AbsentApproved,
+	}}
+	for _, tc := range []struct {
+		name        string
+		nickname    string
+		start, stop string
+		status      AbsentStatus
+		overlap     bool
+	}{
+		{"touching before", "alice", "08:00", "10:00", AbsentApproved, false},
+		{"touching after", "alice", "12:00", "14:00", AbsentApproved, false},
+		{"before", "alice", "07:00", "09:59", AbsentApproved, false},
+		{"after", "alice", "12:01", "14:00", AbsentApproved, false},
+		{"nested", "alice", "10:30", "11:30", AbsentApproved, true},
+		{"nesting", "alice", "09:00", "13:00", AbsentApproved, true},
+		{"same", "alice", "10:00", "12:00", AbsentApproved, true},
+		{"same start", "alice", "10:00", "11:00", AbsentApproved, true},
+		{"same stop", "alice", "11:00", "12:00", AbsentApproved, true},
+		{"partial start", "alice", "09:00", "10:01", AbsentApproved, true},
+		{"partial stop", "alice", "11:59", "13:00", AbsentApproved, true},
+		{"requested", "alice", "11:00", "13:00", AbsentRequested, true},
+		{"rejected", "alice", "11:00", "13:00", AbsentRejected, false},
+		{"other member", "bob", "10:00", "12:00", AbsentApproved, false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			existing[0].Status = tc.status
+			filter := MemberAbsentOverlapFilter(tc.nickname,
+				at(t, "2025-01-01 "+tc.start), at(t, "2025-01-01 "+tc.stop))
+			if got := existing.Contains(filter); got != tc.overlap {
+				t.Errorf("got overlap %t, want %t", got, tc.overlap)
+			}
+		})
+	}
+}
+
+func TestMeetingValidate(t *testing.T) {
+	start := at(t, "2025-01-01 10:00")
+	for _, tc := range []struct {
+		name        string
+		committeeID int64
+		start, stop time.Time
+		valid       bool
+	}{
+		{"valid", 1, start, start.Add(time.Hour), true},
+		{"no committee", 0, start, start.Add(time.Hour), false},
+		{"no start", 1, time.Time{}, start, false},
+		{"no stop", 1, start, time.Time{}, false},
+		{"stop before start", 1, start, start.Add(-time.Hour), false},
+		{"empty", 1, start, start, false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			db := newTestDatabase(t)
+			exec(t, db,
+				`INSERT INTO committees (id, name, slug) VALUES (1, 'TC', 'tc')`,
+				`INSERT INTO meetings (id, committees_id, start_time, stop_time) VALUES `+
+					`(1, 1, '2024-01-01 10:00:00+00:00', '2024-01-01 11:00:00+00:00')`,
+			)
+			ctx := context.Background()
+			m := &Meeting{CommitteeID: tc.committeeID, StartTime: tc.start, StopTime: tc.stop}
+			if err := m.Validate(); (err == nil) != tc.valid {
+				t.Fatalf("got validation error %v, want valid %t", err, tc.valid)
+			}
+			if tc.valid {
+				return
+			}
+			// Invalid meetings are neither inserted nor updated.
+			if err := m.StoreNew(ctx, db); !errors.Is(err, ErrInvalidMeeting) {
+				t.Errorf("storing new: got error %v, want %v", err, ErrInvalidMeeting)
+			}
+			m.ID = 1
+			if err := m.Store(ctx, db); !errors.Is(err, ErrInvalidMeeting) {
+				t.Errorf("storing: got error %v, want %v", err, ErrInvalidMeeting)
+			}
+			var n int
+			if err := db.DB.QueryRow(`SELECT count(*) FROM meetings ` +
+				`WHERE id = 1 AND version = 0 AND unixepoch(stop_time) - unixepoch(start_time) = 3600`,
+			).Scan(&n); err != nil {
+				t.Fatal(err)
+			}
+			var total int
+			if err := db.DB.QueryRow(`SELECT count(*) FROM meetings`).Scan(&total); err != nil {
+				t.Fatal(err)
+			}
+			if n != 1 || total != 1 {
+				t.Errorf("meetings changed")
+			}
+		})
+	}
+}
+
+func TestLoadMeetingsFiltered(t *testing.T) {
+	db := searchFixture(t)
+	var (
+		onHold = MeetingOnHold
+		feb    = at(t, "2025-02-01 10:00")
+		apr    = at(t, "2025-04-01 10:00")
+		// The start of meeting 2 in another time zone.
+		febCET       = feb.In(time.FixedZone("CET", 3600))
+		justAfterFeb = feb.Add(time.Second)
+	)
+	for _, tc := range []struct {
+		name       string
+		committees []int64
+		opts       *MeetingsOptions
+		want       []int64
+	}{
+		{"no options", []int64{1, 2}, nil, []int64{1, 2, 3, 6, 4, 5}},
+		{"empty options", []int64{1}, &MeetingsOptions{}, []int64{1, 2, 3, 4, 5}},
+		{"since inclusive", []int64{1}, &MeetingsOptions{Since: &feb}, []int64{2, 3, 4, 5}},
+		{"since other zone", []int64{1}, &MeetingsOptions{Since: &febCET}, []int64{2, 3, 4, 5}},
+		{"since after start", []int64{1}, &MeetingsOptions{Since: &justAfterFeb}, []int64{3, 4, 5}},
+		{"until exclusive", []int64{1}, &MeetingsOptions{Until: &apr}, []int64{1, 2, 3}},
+		{"until start", []int64{1}, &MeetingsOptions{Until: &justAfterFeb}, []int64{1, 2}},
+		{"range", []int64{1, 2}, &MeetingsOptions{Since: &feb, Until: &apr}, []int64{2, 3, 6}},
+		{"empty range", []int64{1}, &MeetingsOptions{Since: &feb, Until: &feb}, nil},
+		{"status", []int64{1, 2}, &MeetingsOptions{Status: &onHold}, []int64{6, 4, 5}},
+		{"status in range", []int64{1, 2},
+			&MeetingsOptions{Since: &feb, Until: &apr, Status: &onHold}, []int64{6}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			meetings, err := LoadMeetingsFiltered(
+				context.Background(), db, slices.Values(tc.committees), tc.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []int64
+			for _, m := range meetings {
+				got = append(got, m.ID)
+			}
+			if !slices.Equal(got, tc.want) {
+				t.Errorf("got meetings %v, want %v", got, tc.want)
+			}
+		})
+	}
+	// LoadMeetings does not filter.
+	meetings, err := LoadMeetings(context.Background(), db, slices.Values([]int64{2}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(meetings) != 1 || meetings[0].ID != 6 {
+		t.Errorf("got meetings %v, want [6]", meetings)
+	}
+}
+
+func TestMeetingStoreModified(t *testing.T) {
+	db := searchFixture(t)
+	ctx := context.Background()
+	load := func() *Meeting {
+		t.Helper()
+		m, err := LoadMeeting(ctx, db, 4, 1)
+		if err != nil || m == nil {
+			t.Fatalf("loading meeting failed: %v", err)
+		}
+		return m
+	}
+	first, second := load(), load()
+
+	first.StopTime = first.StopTime.Add(time.Hour)
+	if err := first.Store(ctx, db); err != nil {
+		t.Fatalf("storing failed: %v", err)
+	}
+	if first.Version != second.Version+1 {
+		t.Errorf("got version %d, want %d", first.Version, second.Version+1)
+	}
+	// The stale update is rejected.
+	desc := "stale"
+	second.Description = &desc
+	if err := second.Store(ctx, db); !errors.Is(err, ErrModified) {
+		t.Fatalf("got error %v, want %v", err, ErrModified)
+	}
+	m := load()
+	if !m.StopTime.Equal(first.StopTime) || *m.Description != "Budget review" || m.Version != first.Version {
+		t.Errorf("got meeting until %v with %q in version %d", m.StopTime, *m.Description, m.Version)
+	}
+	// Meetings of other committees are not found.
+	m.CommitteeID = 2
+	if err := m.Store(ctx, db); err != nil {
+		t.Errorf("storing in other committee: %v", err)
+	}
+	if got := load(); got.Version != first.Version {
+		t.Errorf("meeting changed by other committee")
+	}
+}
+
+func TestMeetingAgendaRoundTrip(t *testing.T) {
+	db := newTestDatabase(t)
+	exec(t, db, `INSERT INTO committees (id, name, slug) VALUES (1, 'TC', 'tc')`)
+	ctx := context.Background()
+	agenda := "1. Welcome\n2. Budget; \"review\"\n3. Üblicher Kram"
+	start := at(t, "2025-01-01 10:00")
+	m := &Meeting{
+		CommitteeID: 1,
+		StartTime:   start,
+		StopTime:    start.Add(time.Hour),
+		Agenda:      &agenda,
+	}
+	if err := m.StoreNew(ctx, db); err != nil {
+		t.Fatalf("storing new meeting failed: %v", err)
+	}
+	load := func() *Meeting {
+		t.Helper()
+		loaded, err := LoadMeeting(ctx, db, m.ID, 1)
+		if err != nil || loaded == nil {
+			t.Fatalf("loading meeting failed: %v", err)
+		}
+		return loaded
+	}
+	if loaded := load(); loaded.Agenda == nil || *loaded.Agenda != agenda || loaded.Description != nil {
+		t.Fatalf("got agenda %v and description %v", loaded.Agenda, loaded.Description)
+	}
+
+	// The agenda can be changed and removed.
+	for _, want := range []*string{misc.NilString("changed"), nil} {
+		loaded := load()
+		loaded.Agenda = want
+		if err := loaded.Store(ctx, db); err != nil {
+			t.Fatalf("storing meeting failed: %v", err)
+		}
+		got := load().Agenda
+		if (got == nil) != (want == nil) || (got != nil && *got != *want) {
+			t.Errorf("got agenda %v, want %v", got, want)
+		}
+	}
+}
+
+func TestLoadOverrunningMeetings(t *testing.T) {
+	db := searchFixture(t)
+	ctx := context.Background()
+	// Meeting 3 is the only running one and has been edited twice.
+	exec(t, db, `UPDATE meetings SET version = 2 WHERE id = 3`)
+
+	meetings, err := LoadOverrunningMeetings(ctx, db, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(meetings) != 1 || meetings[0].ID != 3 || meetings[0].CommitteeID != 1 {
+		t.Fatalf("got meetings %v, want [3]", meetings)
+	}
+	// The loaded version allows storing the meeting.
+	m := meetings[0]
+	if m.Version != 2 {
+		t.Errorf("got version %d, want 2", m.Version)
+	}
+	m.StopTime = m.StopTime.Add(time.Hour)
+	if err := m.Store(ctx, db); err != nil {
+		t.Errorf("storing overrunning meeting failed: %v", err)
+	}
+
+	// Meetings within the grace period are not overrunning.
+	if meetings, err := LoadOverrunningMeetings(ctx, db, time.Since(m.StopTime)+time.Hour); err != nil {
+		t.Fatal(err)
+	} else if len(meetings) != 0 {
+		t.Errorf("got meetings %v within grace period", meetings)
+	}
+}
+
+func TestScannedMeetingsVersion(t *testing.T) {
+	db := searchFixture(t)
+	ctx := context.Background()
+	exec(t, db, `UPDATE meetings SET version = id`)
+	committees := slices.Values([]int64{1, 2})
+
+	check := func(name string, meetings Meetings) {
+		t.Helper()
+		if len(meetings) == 0 {
+			t.Errorf("%s: no meetings loaded", name)
+		}
+		for _, m := range meetings {
+			if m.Version != m.ID {
+				t.Errorf("%s: meeting %d has version %d", name, m.ID, m.Version)
+			}
+		}
+	}
+	filtered, err := LoadMeetingsFiltered(ctx, db, committees, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	check("filtered", filtered)
+	found, _, err := SearchMeetings(ctx, db, committees, nil, 0, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	check("search", found)
+	highlights, err := LoadHighlightMeetings(ctx, db, committees, at(t, "2025-02-15 00:00"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	check("highlights", highlights)
+}
@@ -0,0 +1,174 @@
+// This file is Free Software under the Apache-2.0 License
+// without warranty, see README.md and LICENSE for details.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileCopyrightText: 2025 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2025 Intevation GmbH <https://intevation.de>
+
+package models
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/csaf-auxiliary/oasis-quorum-calculator/pkg/database"
+	"github.com/csaf-auxiliary/oasis-quorum-calculator/pkg/misc"
+)
+
+// recentMeetings is the number of concluded meetings
+// the recent attendance of a member is based on.
+const recentMeetings = 5
+
+// CommitteeDashboard summarizes a committee for one of its members.
+type CommitteeDashboard struct {
+	Committee *Committee
+	// Status is the current status of the member.
+	Status MemberStatus
+	// Meetings are the meetings of the committee ordered by start time.
+	Meetings Meetings
+	// Attended are the ids of the meetings the member attends or attended.
+	Attended map[int64]bool
+	// Live is the running meeting if any.
+	Live *Meeting
+	// Next is the next upcoming meeting if any.
+	Next *Meeting
+	// RecentAttended is the number of the recently concluded
+	// meetings the member attended.
+	RecentAttended int
+	// RecentConcluded is the number of the recently concluded meetings.
+	RecentConcluded int
+}
+
+// MemberDashboard are the committee dashboards of a member.
+type MemberDashboard []*CommitteeDashboard
+
+// Running returns the running meetings of all committees.
+func (md MemberDashboard) Running() []*CommitteeDashboard {
+	var running []*CommitteeDashboard
+	for _, cd := range md {
+		if cd.Live != nil {
+			running = append(running, cd)
+		}
+	}
+	return running
+}
+
+// LoadMemberDashboard loads the not archived committees in which the user
+// with the given nickname has the member role ordered by name together with
+// the current status of the member, the meetings and the attendance.
+func LoadMemberDashboard(
+	ctx context.Context,
+	db *database.Database,
+	nickname string,
+) (MemberDashboard, error) {
+	tx, err := db.DB.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	const committeesSQL = `SELECT c.id, c.name, c.slug, c.description, ` +
+		`c.created_at, c.updated_at, c.archived, c.attendance_reminders, ` +
+		`coalesce((SELECT mh.status FROM member_history mh ` +
+		`WHERE mh.nickname = cr.nickname AND mh.committees_id = c.id ` +
+		`ORDER BY unixepoch(mh.since) DESC LIMIT 1), 0) ` + // Member
+		`FROM committees c JOIN committee_roles cr ON cr.committees_id = c.id ` +
+		`WHERE cr.nickname = ? AND cr.committee_role_id = ? AND NOT c.archived ` +
+		`ORDER BY c.name`
+	rows, err := tx.QueryContext(ctx, committeesSQL, nickname, MemberRole)
+	if err != nil {
+		return nil, fmt.Errorf("querying member committees failed: %w", err)
+	}
+	defer rows.Close()
+	var dashboard MemberDashboard
+	for rows.Next() {
+		cd := CommitteeDashboard{
+			Committee: new(Committee),
+			Attended:  map[int64]bool{},
+		}
+		c := cd.Committee
+		if err := rows.Scan(
+			&c.ID, &c.Name, &c.Slug, &c.Description,
+			&c.CreatedAt, &c.UpdatedAt, &c.Archived, &c.AttendanceReminders,
+			&cd.Status,
+		); err != nil {
+			return nil, fmt.Errorf("scanning member committees failed: %w", err)
+		}
+		dashboard = append(dashboard, &cd)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("querying member committees failed: %w", err)
+	}
+	if len(dashboard) == 0 {
+		return nil, nil
+	}
+	byID := misc.Index(slices.Values(dashboard), func(cd *CommitteeDashboard) int64 {
+		return cd.Committee.ID
+	})
+
+	const meetingsSQL = `SELECT m.id, m.committees_id, m.status, m.gathering, ` +
+		`m.start_time, m.stop_time, m.description, m.agenda, m.created_at, m.updated_at, ` +
+		`EXISTS(SELECT 1 FROM attendees a WHERE a.meetings_id = m.id AND a.nickname = cr.nickname) ` +
+		`FROM meetings m JOIN committee_roles cr ON cr.committees_id = m.committees_id ` +
+		`WHERE cr.nickname = ? AND cr.committee_role_id = ? ` +
+		`ORDER BY unixepoch(m.start_time), m.id`
+	mrows, err := tx.QueryContext(ctx, meetingsSQL, nickname, MemberRole)
+	if err != nil {
+		return nil, fmt.Errorf("querying member meetings failed: %w", err)
+	}
+	defer mrows.Close()
+	for mrows.Next() {
+		var (
+			meeting  Meeting
+			attended bool
+		)
+		if err := mrows.Scan(
+			&meeting.ID,
+			&meeting.CommitteeID,
+			&meeting.Status,
+			&meeting.Gathering,
+			&meeting.StartTime,
+			&meeting.StopTime,
+			&meeting.Description,
+			&meeting.Agenda,
+			&meeting.CreatedAt,
+			&meeting.UpdatedAt,
+			&attended,
+		); err != nil {
+			return nil, fmt.Errorf("scanning member meetings failed: %w", err)
+		}
+		// Meetings of archived committees are not of interest.
+		cd := byID[meeting.CommitteeID]
+		if cd == nil {
+			continue
+		}
+		cd.Meetings = append(cd.Meetings, &meeting)
+		if attended {
+			cd.Attended[meeting.ID] = true
+		}
+	}
+	if err := mrows.Err(); err != nil {
+		return nil, fmt.Errorf("querying member meetings failed: %w", err)
+	}
+
+	now := time.Now()
+	for _, cd := range dashboard {
+		cd.Live = cd.Meetings.CurrentlyRunning()
+		if next := cd.Meetings.NextAfter(now); next != cd.Live {
+			cd.Next = next
+		}
+		for i := len(cd.Meetings) - 1; i >= 0 && cd.RecentConcluded < recentMeetings; i-- {
+			if m := cd.Meetings[i]; m.Status == MeetingConcluded {
+				cd.RecentConcluded++
+				if cd.Attended[m.ID] {
+					cd.RecentAttended++
+				}
+			}
+		}
+	}
+	return dashboard, nil
+}
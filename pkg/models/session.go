@@ -0,0 +1,76 @@
+// This file is Free Software under the Apache-2.0 License
+// without warranty, see README.md and LICENSE for details.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileCopyrightText: 2025 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2025 Intevation GmbH <https://intevation.de>
+
+package models
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/csaf-auxiliary/oasis-quorum-calculator/pkg/database"
+)
+
+// SessionPrefixLength is the number of characters of a session token
+// which are shown to identify a session.
+const SessionPrefixLength = 8
+
+// Session is a login session of a user.
+type Session struct {
+	// Prefix is the start of the session token.
+	// It identifies the session without revealing the token.
+	Prefix     string
+	LastAccess time.Time
+	// CreatedAt is nil for sessions created before
+	// the creation time was recorded.
+	CreatedAt *time.Time
+}
+
+// LoadSessions loads the sessions of a user ordered by their last access.
+// Sessions not removed by the cleaner yet are included,
+// so callers have to filter out expired ones.
+func LoadSessions(ctx context.Context, db *database.Database, nickname string) ([]*Session, error) {
+	const loadSQL = `SELECT substr(token, 1, ?), last_access, created_at FROM sessions ` +
+		`WHERE nickname = ? ` +
+		`ORDER BY unixepoch(last_access) DESC`
+	rows, err := db.DB.QueryContext(ctx, loadSQL, SessionPrefixLength, nickname)
+	if err != nil {
+		return nil, fmt.Errorf("loading sessions failed: %w", err)
+	}
+	defer rows.Close()
+	var sessions []*Session
+	for rows.Next() {
+		var session Session
+		if err := rows.Scan(&session.Prefix, &session.LastAccess, &session.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scanning sessions failed: %w", err)
+		}
+		sessions = append(sessions, &session)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("loading sessions failed: %w", err)
+	}
+	return sessions, nil
+}
+
+// DeleteSession removes the session of a user identified by the prefix of its token.
+func DeleteSession(ctx context.Context, db *database.Database, nickname, prefix string) error {
+	const deleteSQL = `DELETE FROM sessions WHERE nickname = ? AND substr(token, 1, ?) = ?`
+	if _, err := db.DB.ExecContext(ctx, deleteSQL, nickname, SessionPrefixLength, prefix); err != nil {
+		return fmt.Errorf("deleting session failed: %w", err)
+	}
+	return nil
+}
+
+// DeleteSessions removes all sessions of a user.
+func DeleteSessions(ctx context.Context, db *database.Database, nickname string) error {
+	const deleteSQL = `DELETE FROM sessions WHERE nickname = ?`
+	if _, err := db.DB.ExecContext(ctx, deleteSQL, nickname); err != nil {
+		return fmt.Errorf("deleting sessions failed: %w", err)
+	}
+	return nil
+}
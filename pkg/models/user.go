@@ -23,15 +23,42 @@ import (
 	"github.com/csaf-auxiliary/oasis-quorum-calculator/pkg/misc"
 )
 
+var (
+	// ErrLastAdmin is returned if an action would leave
+	// no active administrator.
+	ErrLastAdmin = errors.New("last admin")
+	// ErrNoStatusChange is returned if there is no change
+	// of the member status which could be reverted.
+	ErrNoStatusChange = errors.New("no status change")
+	// ErrNotChair is returned if the chair role should be
+	// handed over by a user who is not a chair.
+	ErrNotChair = errors.New("not a chair")
+	// ErrAlreadyChair is returned if the chair role should be
+	// handed over to a user who already is a chair.
+	ErrAlreadyChair = errors.New("already a chair")
+	// ErrNotInCommittee is returned if a user has no role in a committee.
+	ErrNotInCommittee = errors.New("not in committee")
+	// ErrLastChair is returned if an action would leave
+	// a committee without a chair.
+	ErrLastChair = errors.New("last chair")
+	// ErrNotMember is returned if the member status of a user
+	// should be changed who is not a member of a committee.
+	ErrNotMember = errors.New("not a member")
+	// ErrUnknownUser is returned if a user does not exist.
+	ErrUnknownUser = errors.New("unknown user")
+	// ErrMergeSelf is returned if a user should be merged with itself.
+	ErrMergeSelf = errors.New("merge with itself")
+)
+
 // Role is the role in the committee.
 type Role int
 
 const (
-	// ChairRole is the manager role.
+	// ChairRole is the chair role.
 	ChairRole Role = iota
 	// MemberRole is the member role.
 	MemberRole
-	// SecretaryRole is functionally the same as the manager role for this tool.
+	// SecretaryRole is functionally the same as the chair role for this tool.
 	SecretaryRole
 	// StaffRole manages members and member attending state.
 	StaffRole
@@ -49,6 +76,9 @@ const (
 	NoneVoting
 	// NoMember represents that the person is not a member any more.
 	NoMember
+	// Observer attends meetings but never votes
+	// and is not up- or downgraded.
+	Observer
 )
 
 // Membership is the membership of a user in a committee.
@@ -66,6 +96,22 @@ type User struct {
 	IsAdmin     bool
 	Memberships []*Membership
 	Password    *string
+	Timezone    *string
+	Email       *string
+	Deactivated *time.Time
+	// DefaultView is the page the user prefers to land on.
+	DefaultView *string
+	// MustChangePassword is set if the user has to change
+	// the generated password before doing anything else.
+	MustChangePassword bool
+	// LockedUntil is set if the account was locked
+	// after too many failed logins.
+	LockedUntil *time.Time
+}
+
+// IsLocked returns true if the account is locked at the given time.
+func (u *User) IsLocked(now time.Time) bool {
+	return u.LockedUntil != nil && now.Before(*u.LockedUntil)
 }
 
 // UserHistoryEntry is a point in time after this status applys.
@@ -84,7 +130,7 @@ type UsersHistories map[string]UserHistory
 // ParseRole parses a role from a string.
 func ParseRole(s string) (Role, error) {
 	switch strings.ToLower(s) {
-	case "chair":
+	case "chair", "manager": // "manager" is the former String() value.
 		return ChairRole, nil
 	case "member":
 		return MemberRole, nil
@@ -101,7 +147,7 @@ func ParseRole(s string) (Role, error) {
 func (r Role) String() string {
 	switch r {
 	case ChairRole:
-		return "manager"
+		return "chair"
 	case MemberRole:
 		return "member"
 	case SecretaryRole:
@@ -113,34 +159,38 @@ func (r Role) String() string {
 	}
 }
 
+// memberStatusNames are the String() values of the member statuses.
+// ParseMemberStatus accepts them, too, so every status round-trips.
+var memberStatusNames = [...]string{
+	Member:     "member",
+	Voting:     "voting",
+	NoneVoting: "nonevoting",
+	NoMember:   "nomember",
+	Observer:   "observer",
+}
+
 // ParseMemberStatus parses a member status from a string.
+// Besides the String() values some common aliases are accepted.
 func ParseMemberStatus(s string) (MemberStatus, error) {
-	switch strings.ToLower(s) {
-	case "member":
-		return Member, nil
-	case "voting":
+	name := strings.ToLower(s)
+	switch name {
+	case "voter":
 		return Voting, nil
-	case "nonevoting": // "nonvoting"
+	case "nonvoting", "non-voting", "non-voter":
 		return NoneVoting, nil
-	case "nomember":
-		return NoMember, nil
-	default:
-		return 0, fmt.Errorf("invalid member status %q", s)
 	}
+	if idx := slices.Index(memberStatusNames[:], name); idx >= 0 {
+		return MemberStatus(idx), nil
+	}
+	return 0, fmt.Errorf("invalid member status %q", s)
 }
 
 // String implements [fmt.Stringer].
 func (ms MemberStatus) String() string {
-	switch ms {
-	case Member:
-		return "member"
-	case Voting:
-		return "voting"
-	case NoneVoting:
-		return "nonevoting"
-	default:
-		return fmt.Sprintf("unknown member status (%d)", ms)
+	if ms >= 0 && int(ms) < len(memberStatusNames) {
+		return memberStatusNames[ms]
 	}
+	return fmt.Sprintf("unknown member status (%d)", ms)
 }
 
 // Compare compares this user with the other by its
@@ -229,12 +279,15 @@ func (u *User) CountMemberships(role ...Role) int {
 	return count
 }
 
-// CommitteesWithRole returns a sequence of Committees
-// in which the user has the given role.
+// CommitteesWithRole returns a sequence of the not archived
+// committees in which the user has the given role.
 func (u *User) CommitteesWithRole(role ...Role) iter.Seq[*Committee] {
 	return misc.Map(
 		misc.Filter(slices.Values(u.Memberships),
 			func(m *Membership) bool {
+				if m.Committee.Archived {
+					return false
+				}
 				for _, role := range role {
 					if m.HasRole(role) {
 						return true
@@ -245,9 +298,26 @@ func (u *User) CommitteesWithRole(role ...Role) iter.Seq[*Committee] {
 		(*Membership).GetCommittee)
 }
 
-// Committees returns an iterator over the committees of the user.
+// Committees returns an iterator over the not archived committees of the user.
 func (u *User) Committees() iter.Seq[*Committee] {
-	return misc.Map(slices.Values(u.Memberships), (*Membership).GetCommittee)
+	return misc.Filter(
+		misc.Map(slices.Values(u.Memberships), (*Membership).GetCommittee),
+		func(c *Committee) bool { return !c.Archived })
+}
+
+// PreferredTimezone returns the preferred timezone of the user.
+// Defaults to UTC if none is set.
+func (u *User) PreferredTimezone() string {
+	if u.Timezone == nil || *u.Timezone == "" {
+		return "UTC"
+	}
+	return *u.Timezone
+}
+
+// PreferredView returns the page the user prefers to land on.
+// An empty string means the page is picked by the roles.
+func (u *User) PreferredView() string {
+	return misc.EmptyString(u.DefaultView)
 }
 
 // Status member returns the status of the user at a given time.
@@ -272,6 +342,7 @@ func (uh UserHistory) Status(when time.Time) MemberStatus {
 }
 
 // LoadUser loads a user with a given nickname from the database.
+// The nickname is matched case-insensitively.
 func LoadUser(ctx context.Context, db *database.Database, nickname string, before *time.Time) (*User, error) {
 	tx, err := db.DB.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
 	if err != nil {
@@ -281,28 +352,90 @@ func LoadUser(ctx context.Context, db *database.Database, nickname string, befor
 	return loadUserTx(ctx, tx, nickname, before)
 }
 
+// nicknameMatchSQL matches nicknames case-insensitively.
+// An exact match is preferred over other matches.
+const nicknameMatchSQL = `lower(nickname) = lower(?) ORDER BY nickname = ? DESC LIMIT 1`
+
+// canonicalNicknameTx returns the nickname as it is stored in the
+// database for a case-insensitively matching nickname.
+// Returns an empty string if there is no such user.
+func canonicalNicknameTx(ctx context.Context, tx *sql.Tx, nickname string) (string, error) {
+	const nicknameSQL = `SELECT nickname FROM users WHERE ` + nicknameMatchSQL
+	var canonical string
+	switch err := tx.QueryRowContext(ctx, nicknameSQL, nickname, nickname).Scan(&canonical); {
+	case errors.Is(err, sql.ErrNoRows):
+		return "", nil
+	case err != nil:
+		return "", fmt.Errorf("loading nickname failed: %w", err)
+	}
+	return canonical, nil
+}
+
+// loadBasicUserTx loads the details of the user with the given
+// nickname without the memberships. The nickname is matched
+// case-insensitively.
 func loadBasicUserTx(
 	ctx context.Context,
 	tx *sql.Tx,
 	nickname string,
 ) (*User, error) {
-	// Collect user details
-	user := User{Nickname: nickname}
-	const userSQL = `SELECT firstname, lastname, is_admin ` +
-		`FROM users ` +
-		`WHERE nickname = ?`
+	canonical, err := canonicalNicknameTx(ctx, tx, nickname)
+	if err != nil || canonical == "" {
+		return nil, err
+	}
+	users, err := loadBasicUsersTx(ctx, tx, []string{canonical})
+	if err != nil || len(users) == 0 {
+		return nil, err
+	}
+	return users[0], nil
+}
 
-	switch err := tx.QueryRowContext(ctx, userSQL, nickname).Scan(
-		&user.Firstname,
-		&user.Lastname,
-		&user.IsAdmin,
-	); {
-	case errors.Is(err, sql.ErrNoRows):
+// loadBasicUsersTx loads the details of the users with the given
+// nicknames without their memberships. Unknown users are ignored.
+func loadBasicUsersTx(
+	ctx context.Context,
+	tx *sql.Tx,
+	nicknames []string,
+) ([]*User, error) {
+	if len(nicknames) == 0 {
 		return nil, nil
-	case err != nil:
-		return nil, fmt.Errorf("loading user failed: %w", err)
 	}
-	return &user, nil
+	args := make([]any, len(nicknames))
+	for i, nickname := range nicknames {
+		args[i] = nickname
+	}
+	usersSQL := `SELECT nickname, firstname, lastname, is_admin, timezone, email, ` +
+		`deactivated, must_change_password, default_view, locked_until ` +
+		`FROM users ` +
+		`WHERE nickname IN (` + sqlPlaceholders(len(nicknames)) + `)`
+	rows, err := tx.QueryContext(ctx, usersSQL, args...)
+	if err != nil {
+		return nil, fmt.Errorf("loading users failed: %w", err)
+	}
+	defer rows.Close()
+	users := make([]*User, 0, len(nicknames))
+	for rows.Next() {
+		var user User
+		if err := rows.Scan(
+			&user.Nickname,
+			&user.Firstname,
+			&user.Lastname,
+			&user.IsAdmin,
+			&user.Timezone,
+			&user.Email,
+			&user.Deactivated,
+			&user.MustChangePassword,
+			&user.DefaultView,
+			&user.LockedUntil,
+		); err != nil {
+			return nil, fmt.Errorf("scanning users failed: %w", err)
+		}
+		users = append(users, &user)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("loading users failed: %w", err)
+	}
+	return users, nil
 }
 
 func loadUserTx(
@@ -317,13 +450,13 @@ func loadUserTx(
 	}
 
 	// Collect memberships
-	const committeeRolesSQL = `SELECT committee_role_id, committees_id, name, description ` +
+	const committeeRolesSQL = `SELECT committee_role_id, committees_id, name, description, archived ` +
 		`FROM committee_roles JOIN committees ` +
 		`ON committee_roles.committees_id = committees.id ` +
 		`WHERE nickname = ? ` +
 		`ORDER BY committees_id, committee_role_id`
 
-	rows, err := tx.QueryContext(ctx, committeeRolesSQL, nickname)
+	rows, err := tx.QueryContext(ctx, committeeRolesSQL, user.Nickname)
 	if err != nil {
 		return nil, err
 	}
@@ -335,8 +468,9 @@ func loadUserTx(
 				rid         int
 				name        string
 				description *string
+				archived    bool
 			)
-			if err := rows.Scan(&rid, &cid, &name, &description); err != nil {
+			if err := rows.Scan(&rid, &cid, &name, &description, &archived); err != nil {
 				return err
 			}
 			if n := len(user.Memberships); n == 0 || user.Memberships[n-1].Committee.ID != cid {
@@ -345,6 +479,7 @@ func loadUserTx(
 						ID:          cid,
 						Name:        name,
 						Description: description,
+						Archived:    archived,
 					},
 				})
 			}
@@ -398,6 +533,10 @@ func (u *User) Store(ctx context.Context, db *database.Database) error {
 	}
 	add("firstname", u.Firstname)
 	add("lastname", u.Lastname)
+	add("timezone", u.Timezone)
+	add("email", u.Email)
+	add("default_view", u.DefaultView)
+	add("must_change_password", u.MustChangePassword)
 	if u.Password != nil {
 		encoded := misc.EncodePassword(*u.Password)
 		add("password", encoded)
@@ -412,23 +551,88 @@ func (u *User) Store(ctx context.Context, db *database.Database) error {
 	return nil
 }
 
-// LoadAllUsers loads all user ordered by their nickname.
+// LoadAllUsers loads all active user ordered by their nickname.
 func LoadAllUsers(ctx context.Context, db *database.Database) ([]*User, error) {
-	var users []*User
-	const loadSQL = `SELECT nickname, firstname, lastname, is_admin FROM users ` +
+	const loadSQL = `SELECT nickname, firstname, lastname, email, is_admin, deactivated FROM users ` +
+		`WHERE deactivated IS NULL ` +
 		`ORDER BY nickname`
-	rows, err := db.DB.QueryContext(ctx, loadSQL)
+	return queryUsers(ctx, db, loadSQL)
+}
+
+// LoadUsersPage loads a page of at most limit users ordered
+// by their nickname starting at offset. Like [LoadAllUsers]
+// deactivated users are left out if includeDeactivated is false.
+// The total number of these users is returned, too.
+func LoadUsersPage(
+	ctx context.Context,
+	db *database.Database,
+	includeDeactivated bool,
+	offset, limit int64,
+) ([]*User, int64, error) {
+	tx, err := db.DB.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return nil, 0, err
+	}
+	defer tx.Rollback()
+	var total int64
+	const countSQL = `SELECT count(*) FROM users WHERE (? OR deactivated IS NULL)`
+	if err := tx.QueryRowContext(ctx, countSQL, includeDeactivated).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("counting users failed: %w", err)
+	}
+	const loadSQL = `SELECT nickname, firstname, lastname, email, is_admin, deactivated FROM users ` +
+		`WHERE (? OR deactivated IS NULL) ` +
+		`ORDER BY nickname ` +
+		`LIMIT ? OFFSET ?`
+	rows, err := tx.QueryContext(ctx, loadSQL, includeDeactivated, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("loading users page failed: %w", err)
+	}
+	users, err := scanUsers(rows)
+	if err != nil {
+		return nil, 0, err
+	}
+	return users, total, nil
+}
+
+// SearchUsers loads all users ordered by their nickname whose nickname,
+// first name or last name contains the given query case-insensitively.
+func SearchUsers(ctx context.Context, db *database.Database, query string) ([]*User, error) {
+	const searchSQL = `SELECT nickname, firstname, lastname, email, is_admin, deactivated FROM users ` +
+		`WHERE nickname LIKE ? ESCAPE '\' ` +
+		`OR firstname LIKE ? ESCAPE '\' ` +
+		`OR lastname LIKE ? ESCAPE '\' ` +
+		`ORDER BY nickname`
+	pattern := misc.LikeContains(query)
+	return queryUsers(ctx, db, searchSQL, pattern, pattern, pattern)
+}
+
+// queryUsers loads the users selected by the given query.
+func queryUsers(
+	ctx context.Context,
+	db *database.Database,
+	query string,
+	args ...any,
+) ([]*User, error) {
+	rows, err := db.DB.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("loading users failed: %w", err)
 	}
+	return scanUsers(rows)
+}
+
+// scanUsers scans the basic user data from the given rows.
+func scanUsers(rows *sql.Rows) ([]*User, error) {
 	defer rows.Close()
+	var users []*User
 	for rows.Next() {
 		var user User
 		if err := rows.Scan(
 			&user.Nickname,
 			&user.Firstname,
 			&user.Lastname,
+			&user.Email,
 			&user.IsAdmin,
+			&user.Deactivated,
 		); err != nil {
 			return nil, fmt.Errorf("scanning users failed: %w", err)
 		}
@@ -440,7 +644,70 @@ func LoadAllUsers(ctx context.Context, db *database.Database) ([]*User, error) {
 	return users, nil
 }
 
-// DeleteUsersByNickname deletes users by their nicknames.
+// DeactivateUsersByNickname deactivates users by their nicknames.
+// Deactivated users keep their history but cannot log in any more.
+// Their sessions are removed.
+func DeactivateUsersByNickname(
+	ctx context.Context,
+	db *database.Database,
+	nicknames iter.Seq[string],
+) error {
+	tx, err := db.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	const (
+		deactivateSQL = `UPDATE users SET deactivated = CURRENT_TIMESTAMP ` +
+			`WHERE nickname = ? AND deactivated IS NULL`
+		deleteSessionsSQL = `DELETE FROM sessions WHERE nickname = ?`
+	)
+	for nickname := range nicknames {
+		if _, err := tx.ExecContext(ctx, deactivateSQL, nickname); err != nil {
+			return fmt.Errorf("deactivating users failed: %w", err)
+		}
+		if _, err := tx.ExecContext(ctx, deleteSessionsSQL, nickname); err != nil {
+			return fmt.Errorf("deleting sessions failed: %w", err)
+		}
+	}
+	if err := checkAdminsLeftTx(ctx, tx); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// ReactivateUsersByNickname reactivates deactivated users by their nicknames.
+func ReactivateUsersByNickname(
+	ctx context.Context,
+	db *database.Database,
+	nicknames iter.Seq[string],
+) error {
+	tx, err := db.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	const reactivateSQL = `UPDATE users SET deactivated = NULL WHERE nickname = ?`
+	for nickname := range nicknames {
+		if _, err := tx.ExecContext(ctx, reactivateSQL, nickname); err != nil {
+			return fmt.Errorf("reactivating users failed: %w", err)
+		}
+	}
+	return tx.Commit()
+}
+
+// UnlockUser removes the lock of an account locked
+// after too many failed logins.
+func UnlockUser(ctx context.Context, db *database.Database, nickname string) error {
+	const unlockSQL = `UPDATE users SET failed_logins = 0, locked_until = NULL WHERE nickname = ?`
+	if _, err := db.DB.ExecContext(ctx, unlockSQL, nickname); err != nil {
+		return fmt.Errorf("unlocking user failed: %w", err)
+	}
+	return nil
+}
+
+// DeleteUsersByNickname permanently deletes users by their nicknames.
+// This removes their history, too.
 func DeleteUsersByNickname(
 	ctx context.Context,
 	db *database.Database,
@@ -448,7 +715,7 @@ func DeleteUsersByNickname(
 ) error {
 	tx, err := db.DB.BeginTx(ctx, nil)
 	if err != nil {
-		return nil
+		return err
 	}
 	defer tx.Rollback()
 	const deleteSQL = `DELETE FROM users WHERE nickname = ?`
@@ -457,31 +724,158 @@ func DeleteUsersByNickname(
 			return fmt.Errorf("deleting users failed: %w", err)
 		}
 	}
+	if err := checkAdminsLeftTx(ctx, tx); err != nil {
+		return err
+	}
 	return tx.Commit()
 }
 
+// MergeUsers merges the user remove into the user keep.
+// The attendances, committee roles, status histories, excused absents,
+// attendance reminders and uploaded documents of remove are
+// reassigned to keep before remove is deleted. If both users have
+// an entry for the same thing the one of keep is preserved.
+// Returns ErrUnknownUser if one of the users does not exist,
+// ErrMergeSelf if they are the same and ErrLastAdmin if
+// this would leave no active admin.
+func MergeUsers(
+	ctx context.Context,
+	db *database.Database,
+	keep, remove string,
+) error {
+	if keep == remove {
+		return ErrMergeSelf
+	}
+	tx, err := db.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	const existsSQL = `SELECT count(*) FROM users WHERE nickname IN (?, ?)`
+	var n int
+	if err := tx.QueryRowContext(ctx, existsSQL, keep, remove).Scan(&n); err != nil {
+		return fmt.Errorf("checking users failed: %w", err)
+	}
+	if n != 2 {
+		return ErrUnknownUser
+	}
+	for _, merge := range []struct {
+		name  string
+		query string
+		args  []any
+	}{{
+		"attendees",
+		`INSERT INTO attendees (meetings_id, nickname, voting_allowed, non_voting) ` +
+			`SELECT meetings_id, ?, voting_allowed, non_voting FROM attendees WHERE nickname = ? ` +
+			`ON CONFLICT (meetings_id, nickname) DO NOTHING`,
+		[]any{keep, remove},
+	}, {
+		"committee roles",
+		`INSERT INTO committee_roles (nickname, committees_id, committee_role_id) ` +
+			`SELECT ?, committees_id, committee_role_id FROM committee_roles WHERE nickname = ? ` +
+			`ON CONFLICT (nickname, committee_role_id, committees_id) DO NOTHING`,
+		[]any{keep, remove},
+	}, {
+		"member history",
+		`INSERT INTO member_history (nickname, committees_id, status, since) ` +
+			`SELECT ?, committees_id, status, since FROM member_history mh WHERE nickname = ? ` +
+			`AND NOT EXISTS (SELECT 1 FROM member_history k ` +
+			`WHERE k.nickname = ? AND k.committees_id = mh.committees_id ` +
+			`AND unixepoch(k.since) = unixepoch(mh.since))`,
+		[]any{keep, remove, keep},
+	}, {
+		"member absent",
+		`INSERT INTO member_absent (nickname, committee_id, start_time, stop_time, status) ` +
+			`SELECT ?, committee_id, start_time, stop_time, status FROM member_absent WHERE nickname = ? ` +
+			`ON CONFLICT (nickname, committee_id, start_time) DO NOTHING`,
+		[]any{keep, remove},
+	}, {
+		"attendance reminders",
+		`INSERT INTO attendance_reminders (meetings_id, nickname, sent) ` +
+			`SELECT meetings_id, ?, sent FROM attendance_reminders WHERE nickname = ? ` +
+			`ON CONFLICT (meetings_id, nickname) DO NOTHING`,
+		[]any{keep, remove},
+	}, {
+		"expected users",
+		`INSERT INTO meeting_expected (meetings_id, nickname) ` +
+			`SELECT meetings_id, ? FROM meeting_expected WHERE nickname = ? ` +
+			`ON CONFLICT (meetings_id, nickname) DO NOTHING`,
+		[]any{keep, remove},
+	}, {
+		"meeting documents",
+		`UPDATE meeting_documents SET uploaded_by = ? WHERE uploaded_by = ?`,
+		[]any{keep, remove},
+	}, {
+		"snapshot voters",
+		`UPDATE meeting_snapshot_voters SET nickname = ? WHERE nickname = ? ` +
+			`AND NOT EXISTS (SELECT 1 FROM meeting_snapshot_voters k ` +
+			`WHERE k.nickname = ? AND k.meetings_id = meeting_snapshot_voters.meetings_id)`,
+		[]any{keep, remove, keep},
+	}} {
+		if _, err := tx.ExecContext(ctx, merge.query, merge.args...); err != nil {
+			return fmt.Errorf("merging %s failed: %w", merge.name, err)
+		}
+	}
+	// The history is not deleted together with the user.
+	// The attendees are deleted before the user as their
+	// delete trigger records changes referring to the user.
+	const (
+		deleteHistorySQL   = `DELETE FROM member_history WHERE nickname = ?`
+		deleteVotersSQL    = `DELETE FROM meeting_snapshot_voters WHERE nickname = ?`
+		deleteAttendeesSQL = `DELETE FROM attendees WHERE nickname = ?`
+		deleteUserSQL      = `DELETE FROM users WHERE nickname = ?`
+	)
+	for _, query := range []string{
+		deleteHistorySQL, deleteVotersSQL, deleteAttendeesSQL, deleteUserSQL,
+	} {
+		if _, err := tx.ExecContext(ctx, query, remove); err != nil {
+			return fmt.Errorf("deleting merged user failed: %w", err)
+		}
+	}
+	if err := checkAdminsLeftTx(ctx, tx); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// checkAdminsLeftTx returns ErrLastAdmin if there is no active admin left.
+func checkAdminsLeftTx(ctx context.Context, tx *sql.Tx) error {
+	const countSQL = `SELECT count(*) FROM users ` +
+		`WHERE is_admin AND deactivated IS NULL`
+	var admins int
+	if err := tx.QueryRowContext(ctx, countSQL).Scan(&admins); err != nil {
+		return fmt.Errorf("counting admins failed: %w", err)
+	}
+	if admins == 0 {
+		return ErrLastAdmin
+	}
+	return nil
+}
+
 // StoreNew stores the user with a given password into the database.
-// Returns false if the user already exists.
+// As the password is generated the user has to change it on first login.
+// Returns false if a user with the same nickname ignoring case already exists.
 func (u *User) StoreNew(ctx context.Context, db *database.Database, password string) (bool, error) {
 	tx, err := db.DB.BeginTx(ctx, nil)
 	if err != nil {
 		return false, err
 	}
 	defer tx.Rollback()
-	var exists bool
-	const userExistsSQL = `SELECT EXISTS(SELECT 1 FROM users WHERE nickname = ?)`
-	if err := tx.QueryRowContext(ctx, userExistsSQL, u.Nickname).Scan(&exists); err != nil {
+	// Nicknames only differing in case would be ambiguous.
+	existing, err := canonicalNicknameTx(ctx, tx, u.Nickname)
+	if err != nil {
 		return false, fmt.Errorf("checking user existance failed: %w", err)
 	}
-	if exists {
+	if existing != "" {
 		return false, nil
 	}
 	encoded := misc.EncodePassword(password)
-	const insertSQL = `INSERT INTO users (nickname, firstname, lastname, is_admin, password) ` +
-		`VALUES (?, ?, ?, ?, ?)`
+	const insertSQL = `INSERT INTO users ` +
+		`(nickname, firstname, lastname, email, is_admin, password, must_change_password) ` +
+		`VALUES (?, ?, ?, ?, ?, ?, true)`
 	if _, err := tx.ExecContext(
 		ctx, insertSQL,
-		u.Nickname, u.Firstname, u.Lastname, u.IsAdmin, encoded); err != nil {
+		u.Nickname, u.Firstname, u.Lastname, u.Email, u.IsAdmin, encoded); err != nil {
 		return false, fmt.Errorf("inserting user failed: %w", err)
 	}
 	if err := tx.Commit(); err != nil {
@@ -491,6 +885,7 @@ func (u *User) StoreNew(ctx context.Context, db *database.Database, password str
 }
 
 // UpdateMemberships updates the memberships of the user with a given nickname.
+// ErrLastChair is returned if this would leave a committee without a chair.
 func UpdateMemberships(
 	ctx context.Context,
 	db *database.Database,
@@ -502,6 +897,10 @@ func UpdateMemberships(
 		return err
 	}
 	defer tx.Rollback()
+	chaired, err := chairedCommitteesTx(ctx, tx, nickname)
+	if err != nil {
+		return err
+	}
 	const deleteSQL = `DELETE FROM committee_roles WHERE nickname = ?`
 	if _, err := tx.ExecContext(ctx, deleteSQL, nickname); err != nil {
 		return fmt.Errorf("deleting committee roles failed: %w", err)
@@ -563,9 +962,52 @@ func UpdateMemberships(
 			}
 		}
 	}
+	if err := checkChairsLeftTx(ctx, tx, chaired); err != nil {
+		return err
+	}
 	return tx.Commit()
 }
 
+// chairedCommitteesTx returns the ids of the committees chaired by a user.
+func chairedCommitteesTx(ctx context.Context, tx *sql.Tx, nickname string) ([]int64, error) {
+	const chairedSQL = `SELECT committees_id FROM committee_roles ` +
+		`WHERE nickname = ? AND committee_role_id = ?`
+	rows, err := tx.QueryContext(ctx, chairedSQL, nickname, ChairRole)
+	if err != nil {
+		return nil, fmt.Errorf("querying chaired committees failed: %w", err)
+	}
+	defer rows.Close()
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("scanning chaired committees failed: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("scanning chaired committees failed: %w", err)
+	}
+	return ids, nil
+}
+
+// checkChairsLeftTx returns ErrLastChair if any of the
+// given committees has no chair left.
+func checkChairsLeftTx(ctx context.Context, tx *sql.Tx, committeeIDs []int64) error {
+	const countSQL = `SELECT count(*) FROM committee_roles ` +
+		`WHERE committees_id = ? AND committee_role_id = ?`
+	for _, id := range committeeIDs {
+		var chairs int
+		if err := tx.QueryRowContext(ctx, countSQL, id, ChairRole).Scan(&chairs); err != nil {
+			return fmt.Errorf("counting chairs failed: %w", err)
+		}
+		if chairs == 0 {
+			return ErrLastChair
+		}
+	}
+	return nil
+}
+
 // LoadCommitteeUsers loads all users of a committee.
 func LoadCommitteeUsers(
 	ctx context.Context,
@@ -593,7 +1035,57 @@ func LoadCommitteeUsersTx(
 		`WHERE committees_id = ?` +
 		`AND committee_role_id != (SELECT id FROM committee_role WHERE name = 'staff')` +
 		`ORDER BY nickname`
-	rows, err := tx.QueryContext(ctx, committeeUsersSQL, committeeID)
+	return loadCommitteeUsersTx(ctx, tx, before, committeeUsersSQL, committeeID)
+}
+
+// LoadCommitteeUsersByStatus loads the users of a committee
+// whose member status before the given time is one of the given ones.
+// If no status is given all users of the committee are loaded.
+func LoadCommitteeUsersByStatus(
+	ctx context.Context,
+	db *database.Database,
+	committeeID int64,
+	before *time.Time,
+	statuses ...MemberStatus,
+) ([]*User, error) {
+	tx, err := db.DB.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+	if len(statuses) == 0 {
+		return LoadCommitteeUsersTx(ctx, tx, committeeID, before)
+	}
+	// Users without a history are members.
+	query := `SELECT distinct(cr.nickname) FROM committee_roles cr ` +
+		`WHERE cr.committees_id = ? ` +
+		`AND cr.committee_role_id != (SELECT id FROM committee_role WHERE name = 'staff') ` +
+		`AND coalesce((SELECT mh.status FROM member_history mh ` +
+		`WHERE mh.nickname = cr.nickname AND mh.committees_id = cr.committees_id `
+	args := []any{committeeID}
+	if before != nil {
+		query += `AND unixepoch(mh.since) < unixepoch(?) `
+		args = append(args, before)
+	}
+	query += `ORDER BY unixepoch(mh.since) DESC LIMIT 1), 0) ` + // Member
+		`IN (` + sqlPlaceholders(len(statuses)) + `) ` +
+		`ORDER BY cr.nickname`
+	for _, status := range statuses {
+		args = append(args, status)
+	}
+	return loadCommitteeUsersTx(ctx, tx, before, query, args...)
+}
+
+// loadCommitteeUsersTx loads the users whose nicknames are
+// selected by the given query.
+func loadCommitteeUsersTx(
+	ctx context.Context,
+	tx *sql.Tx,
+	before *time.Time,
+	query string,
+	args ...any,
+) ([]*User, error) {
+	rows, err := tx.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("querying committee users failed: %w", err)
 	}
@@ -623,6 +1115,38 @@ func LoadCommitteeUsersTx(
 	return users, nil
 }
 
+// LoadExcused loads the nicknames of the users which were
+// excused in a committee at a given point in time.
+// The bounds of the absent ranges are inclusive.
+// Only approved absents are taken into account.
+func LoadExcused(
+	ctx context.Context,
+	db *database.Database,
+	committeeID int64,
+	at time.Time,
+) (map[string]bool, error) {
+	const loadSQL = `SELECT DISTINCT nickname FROM member_absent ` +
+		`WHERE committee_id = ? AND unixepoch(?) BETWEEN unixepoch(start_time) AND unixepoch(stop_time) ` +
+		`AND status = 1` // AbsentApproved
+	rows, err := db.DB.QueryContext(ctx, loadSQL, committeeID, at)
+	if err != nil {
+		return nil, fmt.Errorf("loading excused users failed: %w", err)
+	}
+	defer rows.Close()
+	excused := map[string]bool{}
+	for rows.Next() {
+		var nickname string
+		if err := rows.Scan(&nickname); err != nil {
+			return nil, fmt.Errorf("scanning excused users failed: %w", err)
+		}
+		excused[nickname] = true
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("loading excused users failed: %w", err)
+	}
+	return excused, nil
+}
+
 // IsUserExcusedFromMeetingTx figures out if the user was excused
 // for a given user in a committee in a given point in time.
 // Returns false if the user was not excused at this time.
@@ -634,7 +1158,8 @@ func IsUserExcusedFromMeetingTx(
 ) (bool, error) {
 	var isExcused bool
 	const statusSQL = `SELECT 1 FROM member_absent ` +
-		`WHERE nickname = ? AND committee_id = ? AND unixepoch(?) BETWEEN unixepoch(start_time) AND unixepoch(stop_time)` +
+		`WHERE nickname = ? AND committee_id = ? AND unixepoch(?) BETWEEN unixepoch(start_time) AND unixepoch(stop_time) ` +
+		`AND status = 1 ` + // AbsentApproved
 		`LIMIT 1`
 	switch err := tx.QueryRowContext(ctx, statusSQL, nickname, committeeID, when).Scan(&isExcused); {
 	case errors.Is(err, sql.ErrNoRows):
@@ -714,6 +1239,250 @@ func UpdateUserCommitteeStatusTx(
 	return nil
 }
 
+// UpdateCommitteeStatus sets the member status of several members
+// of a committee at once. All changes share the same time.
+// Members which already have the given status are left untouched.
+// Returns [ErrNotMember] if one of the users is not a member
+// of the committee.
+func UpdateCommitteeStatus(
+	ctx context.Context,
+	db *database.Database,
+	committeeID int64,
+	nicknames []string,
+	status MemberStatus,
+) error {
+	tx, err := db.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	const isMemberSQL = `SELECT EXISTS(SELECT 1 FROM committee_roles ` +
+		`WHERE nickname = ? AND committees_id = ? AND committee_role_id = ?)`
+	for _, nickname := range nicknames {
+		var isMember bool
+		if err := tx.QueryRowContext(
+			ctx, isMemberSQL, nickname, committeeID, MemberRole,
+		).Scan(&isMember); err != nil {
+			return fmt.Errorf("checking membership failed: %w", err)
+		}
+		if !isMember {
+			return fmt.Errorf("%w: %q", ErrNotMember, nickname)
+		}
+	}
+	if err := UpdateUserCommitteeStatusTx(
+		ctx, tx,
+		misc.Attribute(slices.Values(nicknames), status),
+		committeeID,
+		time.Now().UTC(),
+	); err != nil {
+		return fmt.Errorf("updating member status failed: %w", err)
+	}
+	return tx.Commit()
+}
+
+// RevertLastStatusChange removes the latest change of the member status
+// of a user in a committee restoring the previous status.
+// The initial status cannot be removed. ErrNoStatusChange is
+// returned in this case.
+func RevertLastStatusChange(
+	ctx context.Context,
+	db *database.Database,
+	nickname string,
+	committeeID int64,
+) error {
+	tx, err := db.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	const (
+		countSQL = `SELECT count(*) FROM member_history ` +
+			`WHERE nickname = ? AND committees_id = ?`
+		deleteSQL = `DELETE FROM member_history ` +
+			`WHERE nickname = ? AND committees_id = ? AND since = (` +
+			`SELECT since FROM member_history ` +
+			`WHERE nickname = ? AND committees_id = ? ` +
+			`ORDER BY unixepoch(since) DESC LIMIT 1)`
+	)
+	var count int
+	if err := tx.QueryRowContext(ctx, countSQL, nickname, committeeID).Scan(&count); err != nil {
+		return fmt.Errorf("counting member status changes failed: %w", err)
+	}
+	if count < 2 {
+		return ErrNoStatusChange
+	}
+	if _, err := tx.ExecContext(ctx, deleteSQL,
+		nickname, committeeID,
+		nickname, committeeID,
+	); err != nil {
+		return fmt.Errorf("reverting member status failed: %w", err)
+	}
+	return tx.Commit()
+}
+
+// TransferChair hands the chair role in a committee over from one user
+// to another. The receiving user has to have a role in the committee
+// already and is made a member of it if needed.
+func TransferChair(
+	ctx context.Context,
+	db *database.Database,
+	committeeID int64,
+	from, to string,
+) error {
+	tx, err := db.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	const (
+		hasRoleSQL = `SELECT EXISTS(SELECT 1 FROM committee_roles ` +
+			`WHERE nickname = ? AND committees_id = ? AND committee_role_id = ?)`
+		inCommitteeSQL = `SELECT EXISTS(SELECT 1 FROM committee_roles cr ` +
+			`JOIN users u ON cr.nickname = u.nickname ` +
+			`WHERE cr.nickname = ? AND cr.committees_id = ? AND u.deactivated IS NULL)`
+		deleteRoleSQL = `DELETE FROM committee_roles ` +
+			`WHERE nickname = ? AND committees_id = ? AND committee_role_id = ?`
+		insertRoleSQL = `INSERT INTO committee_roles ` +
+			`(nickname, committees_id, committee_role_id) ` +
+			`VALUES (?, ?, ?) ON CONFLICT DO NOTHING`
+	)
+	var inCommittee, isChair bool
+	if err := tx.QueryRowContext(ctx, inCommitteeSQL, to, committeeID).Scan(&inCommittee); err != nil {
+		return fmt.Errorf("checking committee membership failed: %w", err)
+	}
+	if !inCommittee {
+		return ErrNotInCommittee
+	}
+	if err := tx.QueryRowContext(ctx, hasRoleSQL, to, committeeID, ChairRole).Scan(&isChair); err != nil {
+		return fmt.Errorf("checking chair role failed: %w", err)
+	}
+	if isChair {
+		return ErrAlreadyChair
+	}
+	res, err := tx.ExecContext(ctx, deleteRoleSQL, from, committeeID, ChairRole)
+	if err != nil {
+		return fmt.Errorf("removing chair role failed: %w", err)
+	}
+	if n, err := res.RowsAffected(); err != nil || n == 0 {
+		return ErrNotChair
+	}
+	if _, err := tx.ExecContext(ctx, insertRoleSQL, to, committeeID, ChairRole); err != nil {
+		return fmt.Errorf("adding chair role failed: %w", err)
+	}
+	res, err = tx.ExecContext(ctx, insertRoleSQL, to, committeeID, MemberRole)
+	if err != nil {
+		return fmt.Errorf("adding member role failed: %w", err)
+	}
+	// Record the status of a new member.
+	if n, err := res.RowsAffected(); err == nil && n > 0 {
+		if err := UpdateUserCommitteeStatusTx(
+			ctx, tx,
+			misc.Attribute(slices.Values([]string{to}), Member),
+			committeeID,
+			time.Now().UTC(),
+		); err != nil {
+			return fmt.Errorf("storing member status failed: %w", err)
+		}
+	}
+	return tx.Commit()
+}
+
+// EnsureInitialHistory makes sure that the member status history
+// of every member of a committee starts not later than the first
+// meeting of the committee. The earliest recorded status is moved
+// back to the start of the first meeting. Members without any
+// recorded status get the Member status from then on.
+// This is meant for members added without a date, e.g. imported ones,
+// as it would count later joiners as members of earlier meetings.
+func EnsureInitialHistory(
+	ctx context.Context,
+	db *database.Database,
+	committeeID int64,
+) error {
+	tx, err := db.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	const (
+		firstMeetingSQL = `SELECT start_time FROM meetings ` +
+			`WHERE committees_id = ? ` +
+			`ORDER BY unixepoch(start_time) LIMIT 1`
+		membersSQL = `SELECT nickname FROM committee_roles ` +
+			`WHERE committees_id = ? AND committee_role_id = ?`
+		earliestSQL = `SELECT since FROM member_history ` +
+			`WHERE nickname = ? AND committees_id = ? ` +
+			`ORDER BY unixepoch(since) LIMIT 1`
+		redateSQL = `UPDATE member_history SET since = ? ` +
+			`WHERE nickname = ? AND committees_id = ? AND since = (` +
+			earliestSQL + `)`
+		insertSQL = `INSERT INTO member_history ` +
+			`(nickname, committees_id, status, since) ` +
+			`VALUES (?, ?, ?, ?)`
+	)
+	var first time.Time
+	switch err := tx.QueryRowContext(ctx, firstMeetingSQL, committeeID).Scan(&first); {
+	case errors.Is(err, sql.ErrNoRows):
+		// Without meetings there is nothing to backfill.
+		return nil
+	case err != nil:
+		return fmt.Errorf("loading first meeting failed: %w", err)
+	}
+	first = first.UTC()
+	rows, err := tx.QueryContext(ctx, membersSQL, committeeID, MemberRole)
+	if err != nil {
+		return fmt.Errorf("querying members failed: %w", err)
+	}
+	var nicknames []string
+	if err := func() error {
+		defer rows.Close()
+		for rows.Next() {
+			var nickname string
+			if err := rows.Scan(&nickname); err != nil {
+				return err
+			}
+			nicknames = append(nicknames, nickname)
+		}
+		return rows.Err()
+	}(); err != nil {
+		return fmt.Errorf("scanning members failed: %w", err)
+	}
+	for _, nickname := range nicknames {
+		var since time.Time
+		switch err := tx.QueryRowContext(ctx, earliestSQL, nickname, committeeID).Scan(&since); {
+		case errors.Is(err, sql.ErrNoRows):
+			if _, err := tx.ExecContext(ctx, insertSQL, nickname, committeeID, Member, first); err != nil {
+				return fmt.Errorf("inserting initial member status failed: %w", err)
+			}
+		case err != nil:
+			return fmt.Errorf("loading earliest member status failed: %w", err)
+		case since.After(first):
+			if _, err := tx.ExecContext(ctx, redateSQL,
+				first,
+				nickname, committeeID,
+				nickname, committeeID,
+			); err != nil {
+				return fmt.Errorf("moving initial member status failed: %w", err)
+			}
+		}
+	}
+	return tx.Commit()
+}
+
+// LoadUsersHistories loads the histories of the users of a committee.
+func LoadUsersHistories(
+	ctx context.Context,
+	db *database.Database,
+	committeeID int64,
+) (UsersHistories, error) {
+	tx, err := db.DB.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+	return LoadUsersHistoriesTx(ctx, tx, committeeID)
+}
+
 // LoadUsersHistoriesTx loads the histories of the users of a committee.
 func LoadUsersHistoriesTx(
 	ctx context.Context,
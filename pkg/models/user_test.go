@@ -0,0 +1,452 @@
+// This file is Free Software under the Apache-2.0 License
+// without warranty, see README.md and LICENSE for details.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileCopyrightText: 2025 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2025 Intevation GmbH <https://intevation.de>
+
+package models
+
+import (
+	"context"
+	"errors"
+	"iter"
+	"maps"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/csaf-auxiliary/oasis-quorum-calculator/pkg/database"
+)
+
+func TestLoadExcused(t *testing.T) {
+	db := newTestDatabase(t)
+	exec(t, db,
+		`INSERT INTO committees (id, name, slug) VALUES (1, 'TC', 'tc'), (2, 'SC', 'sc')`,
+		`INSERT INTO users (nickname, password) VALUES `+
+			`('alice', 'x'), ('bob', 'x'), ('carol', 'x'), ('dave', 'x')`,
+		`INSERT INTO member_absent (nickname, committee_id, start_time, stop_time, status) VALUES `+
+			// Bounds are inclusive.
+			`('alice', 1, '2025-01-01 00:00:00+00:00', '2025-01-01 10:00:00+00:00', 1), `+
+			// Only requested.
+			`('bob', 1, '2025-01-01 00:00:00+00:00', '2025-01-02 00:00:00+00:00', 0), `+
+			// Other committee.
+			`('carol', 2, '2025-01-01 00:00:00+00:00', '2025-01-02 00:00:00+00:00', 1), `+
+			// Overlapping absents.
+			`('dave', 1, '2025-01-01 00:00:00+00:00', '2025-01-02 00:00:00+00:00', 1), `+
+			`('dave', 1, '2025-01-01 09:00:00+00:00', '2025-01-01 11:00:00+00:00', 1)`,
+	)
+	excused, err := LoadExcused(context.Background(), db, 1, at(t, "2025-01-01 10:00"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := slices.Sorted(maps.Keys(excused))
+	if want := []string{"alice", "dave"}; !slices.Equal(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestMemberStatusString(t *testing.T) {
+	for ms := Member; ms <= Observer; ms++ {
+		s := ms.String()
+		if strings.Contains(s, "unknown") {
+			t.Errorf("%d: got %q", int(ms), s)
+			continue
+		}
+		switch got, err := ParseMemberStatus(s); {
+		case err != nil:
+			t.Errorf("%q: %v", s, err)
+		case got != ms:
+			t.Errorf("%q: got %d, want %d", s, int(got), int(ms))
+		}
+	}
+	for _, ms := range []MemberStatus{-1, Observer + 1} {
+		if s := ms.String(); !strings.Contains(s, "unknown") {
+			t.Errorf("undefined status %d: got %q", int(ms), s)
+		}
+	}
+	for _, alias := range []struct {
+		s    string
+		want MemberStatus
+	}{
+		{"Voter", Voting},
+		{"non-voting", NoneVoting},
+		{"NOMEMBER", NoMember},
+	} {
+		if got, err := ParseMemberStatus(alias.s); err != nil || got != alias.want {
+			t.Errorf("%q: got %d (%v), want %d", alias.s, int(got), err, int(alias.want))
+		}
+	}
+	if _, err := ParseMemberStatus("unknown member status (5)"); err == nil {
+		t.Error("undefined status parsed")
+	}
+}
+
+func TestLoadUsersPage(t *testing.T) {
+	db := newTestDatabase(t)
+	// Together with the admin there are 6 users, 2 of them deactivated.
+	exec(t, db,
+		`INSERT INTO users (nickname, password, deactivated) VALUES `+
+			`('eve', 'x', NULL), ('bob', 'x', NULL), ('dave', 'x', CURRENT_TIMESTAMP), `+
+			`('alice', 'x', NULL), ('carol', 'x', CURRENT_TIMESTAMP)`,
+	)
+	for _, tc := range []struct {
+		includeDeactivated bool
+		want               []string
+	}{
+		{false, []string{"admin", "alice", "bob", "eve"}},
+		{true, []string{"admin", "alice", "bob", "carol", "dave", "eve"}},
+	} {
+		var got []string
+		for offset := int64(0); ; offset += 4 {
+			users, total, err := LoadUsersPage(
+				context.Background(), db, tc.includeDeactivated, offset, 4)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if total != int64(len(tc.want)) {
+				t.Errorf("deactivated %t: got total %d, want %d",
+					tc.includeDeactivated, total, len(tc.want))
+			}
+			if len(users) == 0 {
+				break
+			}
+			for _, user := range users {
+				got = append(got, user.Nickname)
+			}
+		}
+		if !slices.Equal(got, tc.want) {
+			t.Errorf("deactivated %t: got %v, want %v", tc.includeDeactivated, got, tc.want)
+		}
+	}
+}
+
+func TestParseRole(t *testing.T) {
+	for role := ChairRole; role <= StaffRole; role++ {
+		switch got, err := ParseRole(role.String()); {
+		case err != nil:
+			t.Errorf("%q: %v", role.String(), err)
+		case got != role:
+			t.Errorf("%q: got %d, want %d", role.String(), int(got), int(role))
+		}
+	}
+	for _, tc := range []struct {
+		input string
+		want  Role
+	}{
+		{"manager", ChairRole}, // Former name of the chair role.
+		{"Manager", ChairRole},
+		{"CHAIR", ChairRole},
+		{"Staff", StaffRole},
+	} {
+		if got, err := ParseRole(tc.input); err != nil || got != tc.want {
+			t.Errorf("%q: got %d (%v), want %d", tc.input, int(got), err, int(tc.want))
+		}
+	}
+	for _, input := range []string{"", "admin", "chairs"} {
+		if _, err := ParseRole(input); err == nil {
+			t.Errorf("%q: expected an error", input)
+		}
+	}
+	if s := ChairRole.String(); s != "chair" {
+		t.Errorf("chair role is called %q", s)
+	}
+}
+
+// committeeRoles returns the sorted roles of the users in committee 1.
+func committeeRoles(t *testing.T, db *database.Database) []string {
+	t.Helper()
+	rows, err := db.DB.Query(`SELECT nickname, committee_role_id FROM committee_roles ` +
+		`WHERE committees_id = 1 ORDER BY nickname, committee_role_id`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+	var roles []string
+	for rows.Next() {
+		var (
+			nickname string
+			role     Role
+		)
+		if err := rows.Scan(&nickname, &role); err != nil {
+			t.Fatal(err)
+		}
+		roles = append(roles, nickname+":"+role.String())
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatal(err)
+	}
+	return roles
+}
+
+func TestTransferChair(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		from, to string
+		err      error
+		roles    []string
+	}{
+		{"to member", "alice", "bob", nil,
+			[]string{"alice:member", "bob:chair", "bob:member", "carol:secretary", "dave:member"}},
+		{"to secretary", "alice", "carol", nil,
+			[]string{"alice:member", "bob:member", "carol:chair", "carol:member", "carol:secretary", "dave:member"}},
+		{"not a chair", "bob", "carol", ErrNotChair, nil},
+		{"already chair", "alice", "alice", ErrAlreadyChair, nil},
+		{"outsider", "alice", "erin", ErrNotInCommittee, nil},
+		{"deactivated", "alice", "dave", ErrNotInCommittee, nil},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			db := newTestDatabase(t)
+			exec(t, db,
+				`INSERT INTO committees (id, name, slug) VALUES (1, 'TC', 'tc')`,
+				`INSERT INTO users (nickname, password) VALUES `+
+					`('alice', 'x'), ('bob', 'x'), ('carol', 'x'), ('erin', 'x')`,
+				`INSERT INTO users (nickname, password, deactivated) VALUES `+
+					`('dave', 'x', CURRENT_TIMESTAMP)`,
+				`INSERT INTO committee_roles (nickname, committees_id, committee_role_id) VALUES `+
+					`('alice', 1, 0), ('alice', 1, 1), ('bob', 1, 1), ('carol', 1, 2), ('dave', 1, 1)`,
+			)
+			before := committeeRoles(t, db)
+			err := TransferChair(context.Background(), db, 1, tc.from, tc.to)
+			if !errors.Is(err, tc.err) {
+				t.Fatalf("got error %v, want %v", err, tc.err)
+			}
+			want := tc.roles
+			if tc.err != nil {
+				// Failed transfers change nothing.
+				want = before
+			}
+			if got := committeeRoles(t, db); !slices.Equal(got, want) {
+				t.Errorf("got roles %q, want %q", got, want)
+			}
+			// The committee keeps exactly one chair.
+			var chairs int
+			if err := db.DB.QueryRow(`SELECT count(*) FROM committee_roles ` +
+				`WHERE committees_id = 1 AND committee_role_id = 0`).Scan(&chairs); err != nil {
+				t.Fatal(err)
+			}
+			if chairs != 1 {
+				t.Errorf("got %d chairs, want 1", chairs)
+			}
+		})
+	}
+}
+
+func TestStoreNewCaseInsensitive(t *testing.T) {
+	db := newTestDatabase(t)
+	ctx := context.Background()
+	for _, tc := range []struct {
+		nickname string
+		stored   bool
+	}{
+		{"Alice", true},
+		{"alice", false},
+		{"ALICE", false},
+		{"alicia", true},
+	} {
+		u := &User{Nickname: tc.nickname}
+		stored, err := u.StoreNew(ctx, db, "secret")
+		if err != nil {
+			t.Fatalf("%s: storing failed: %v", tc.nickname, err)
+		}
+		if stored != tc.stored {
+			t.Errorf("%s: got stored %t, want %t", tc.nickname, stored, tc.stored)
+		}
+	}
+	// Loading finds the user in any case and returns the stored nickname.
+	for _, nickname := range []string{"Alice", "alice", "ALICE"} {
+		u, err := LoadUser(ctx, db, nickname, nil)
+		if err != nil {
+			t.Fatalf("%s: loading failed: %v", nickname, err)
+		}
+		if u == nil || u.Nickname != "Alice" {
+			t.Errorf("%s: got user %+v", nickname, u)
+		}
+	}
+}
+
+// activeAdmins returns the sorted nicknames of the active admins.
+func activeAdmins(t *testing.T, db *database.Database) []string {
+	t.Helper()
+	rows, err := db.DB.Query(`SELECT nickname FROM users ` +
+		`WHERE is_admin AND deactivated IS NULL ORDER BY nickname`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+	var admins []string
+	for rows.Next() {
+		var nickname string
+		if err := rows.Scan(&nickname); err != nil {
+			t.Fatal(err)
+		}
+		admins = append(admins, nickname)
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatal(err)
+	}
+	return admins
+}
+
+func TestLastAdmin(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		action func(context.Context, *database.Database, iter.Seq[string]) error
+	}{
+		{"delete", DeleteUsersByNickname},
+		{"deactivate", DeactivateUsersByNickname},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			db := newTestDatabase(t)
+			exec(t, db, `INSERT INTO users (nickname, password, is_admin) VALUES `+
+				`('root', 'x', true), ('alice', 'x', false)`)
+			ctx := context.Background()
+
+			// Removing one of two admins is fine.
+			if err := tc.action(ctx, db, slices.Values([]string{"admin"})); err != nil {
+				t.Fatalf("removing admin failed: %v", err)
+			}
+			if got, want := activeAdmins(t, db), []string{"root"}; !slices.Equal(got, want) {
+				t.Fatalf("got admins %q, want %q", got, want)
+			}
+			// Removing the last one is not, even together with others.
+			if err := tc.action(ctx, db, slices.Values([]string{"alice", "root"})); !errors.Is(err, ErrLastAdmin) {
+				t.Fatalf("got error %v, want %v", err, ErrLastAdmin)
+			}
+			if got, want := activeAdmins(t, db), []string{"root"}; !slices.Equal(got, want) {
+				t.Errorf("got admins %q, want %q", got, want)
+			}
+			// Nothing else was changed.
+			var n int
+			if err := db.DB.QueryRow(`SELECT count(*) FROM users ` +
+				`WHERE nickname = 'alice' AND deactivated IS NULL`).Scan(&n); err != nil {
+				t.Fatal(err)
+			}
+			if n != 1 {
+				t.Error("alice removed")
+			}
+		})
+	}
+}
+
+func TestMergeLastAdmin(t *testing.T) {
+	db := newTestDatabase(t)
+	exec(t, db, `INSERT INTO users (nickname, password, deactivated) VALUES `+
+		`('alice', 'x', CURRENT_TIMESTAMP)`)
+	// The deactivated alice would remain as the only admin.
+	if err := MergeUsers(context.Background(), db, "alice", "admin"); !errors.Is(err, ErrLastAdmin) {
+		t.Fatalf("got error %v, want %v", err, ErrLastAdmin)
+	}
+	if got, want := activeAdmins(t, db), []string{"admin"}; !slices.Equal(got, want) {
+		t.Errorf("got admins %q, want %q", got, want)
+	}
+}
+
+func TestUpdateMembershipsLastChair(t *testing.T) {
+	membership := func(id int64, roles ...Role) *Membership {
+		return &Membership{Committee: &Committee{ID: id}, Status: Voting, Roles: roles}
+	}
+	for _, tc := range []struct {
+		name        string
+		nickname    string
+		memberships []*Membership
+		err         error
+		roles       []string
+	}{
+		{"other chair left", "alice", []*Membership{membership(1, MemberRole)}, nil,
+			[]string{"alice:member", "bob:chair", "bob:member"}},
+		{"last chair", "bob", []*Membership{membership(1, MemberRole)}, ErrLastChair, nil},
+		{"last chair leaves", "bob", nil, ErrLastChair, nil},
+		{"last chair of other committee", "bob",
+			[]*Membership{membership(1, ChairRole, MemberRole)}, ErrLastChair, nil},
+		{"keeps chairs", "bob", []*Membership{membership(1, ChairRole), membership(2, ChairRole)}, nil,
+			[]string{"alice:chair", "alice:member", "bob:chair"}},
+		{"no chair before", "carol", []*Membership{membership(1, MemberRole)}, nil,
+			[]string{"alice:chair", "alice:member", "bob:chair", "bob:member", "carol:member"}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			db := newTestDatabase(t)
+			exec(t, db,
+				`INSERT INTO committees (id, name, slug) VALUES (1, 'TC', 'tc'), (2, 'SC', 'sc')`,
+				`INSERT INTO users (nickname, password) VALUES ('alice', 'x'), ('bob', 'x'), ('carol', 'x')`,
+				`INSERT INTO committee_roles (nickname, committees_id, committee_role_id) VALUES `+
+					`('alice', 1, 0), ('alice', 1, 1), ('bob', 1, 0), ('bob', 1, 1), ('bob', 2, 0)`,
+			)
+			before := committeeRoles(t, db)
+			err := UpdateMemberships(context.Background(), db, tc.nickname, slices.Values(tc.memberships))
+			if !errors.Is(err, tc.err) {
+				t.Fatalf("got error %v, want %v", err, tc.err)
+			}
+			want := tc.roles
+			if tc.err != nil {
+				// Rejected edits change nothing.
+				want = before
+			}
+			if got := committeeRoles(t, db); !slices.Equal(got, want) {
+				t.Errorf("got roles %q, want %q", got, want)
+			}
+		})
+	}
+}
+
+func TestRevertLastStatusChange(t *testing.T) {
+	db := newTestDatabase(t)
+	exec(t, db,
+		`INSERT INTO committees (id, name, slug) VALUES (1, 'TC', 'tc'), (2, 'SC', 'sc')`,
+		`INSERT INTO users (nickname, password) VALUES ('alice', 'x'), ('bob', 'x')`,
+		`INSERT INTO member_history (nickname, committees_id, status, since) VALUES `+
+			`('alice', 1, 2, '2025-01-01 00:00:00+00:00'), `+ // NoneVoting
+			`('alice', 1, 0, '2024-01-01 00:00:00+00:00'), `+ // Member
+			`('alice', 1, 1, '2024-06-01 00:00:00+00:00'), `+ // Voting
+			`('alice', 2, 0, '2024-01-01 00:00:00+00:00'), `+ // Member
+			`('alice', 2, 1, '2025-06-01 00:00:00+00:00'), `+ // Voting
+			`('bob', 1, 1, '2024-01-01 00:00:00+00:00')`, // Voting
+	)
+	ctx := context.Background()
+	now := at(t, "2026-01-01 00:00")
+	status := func(committeeID int64, nickname string) MemberStatus {
+		t.Helper()
+		histories, err := LoadUsersHistories(ctx, db, committeeID)
+		if err != nil {
+			t.Fatalf("loading histories failed: %v", err)
+		}
+		return histories[nickname].Status(now)
+	}
+	for _, want := range []MemberStatus{Voting, Member} {
+		if err := RevertLastStatusChange(ctx, db, "alice", 1); err != nil {
+			t.Fatalf("reverting failed: %v", err)
+		}
+		if got := status(1, "alice"); got != want {
+			t.Errorf("got status %v after revert, want %v", got, want)
+		}
+	}
+	// The initial status is kept.
+	if err := RevertLastStatusChange(ctx, db, "alice", 1); !errors.Is(err, ErrNoStatusChange) {
+		t.Errorf("got error %v, want %v", err, ErrNoStatusChange)
+	}
+	if got := status(1, "alice"); got != Member {
+		t.Errorf("got status %v, want %v", got, Member)
+	}
+	if err := RevertLastStatusChange(ctx, db, "bob", 1); !errors.Is(err, ErrNoStatusChange) {
+		t.Errorf("got error %v, want %v", err, ErrNoStatusChange)
+	}
+	// Other committees are not affected.
+	if got := status(2, "alice"); got != Voting {
+		t.Errorf("got status %v in other committee, want %v", got, Voting)
+	}
+}
+
+func TestRevertAutomaticDowngrade(t *testing.T) {
+	db, meetingID := streakFixture(t, Voting, "--")
+	concludeStreak(t, db, meetingID, at(t, meetingDay(1)+" 11:00"))
+	if got := memberStatus(t, db, "alice"); got != Member {
+		t.Fatalf("got status %v after conclusion, want %v", got, Member)
+	}
+	if err := RevertLastStatusChange(context.Background(), db, "alice", 1); err != nil {
+		t.Fatalf("reverting failed: %v", err)
+	}
+	if got := memberStatus(t, db, "alice"); got != Voting {
+		t.Errorf("got status %v after revert, want %v", got, Voting)
+	}
+}
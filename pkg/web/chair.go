@@ -9,11 +9,24 @@
 package web
 
 import (
+	"bytes"
+	"context"
+	"crypto/sha256"
 	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"maps"
+	"mime"
+	"mime/multipart"
 	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
 	"slices"
+	"strconv"
 	"strings"
 	"time"
 
@@ -22,24 +35,139 @@ import (
 	"github.com/csaf-auxiliary/oasis-quorum-calculator/pkg/models"
 )
 
+const (
+	defaultMeetingsPerPage = 20
+	maxMeetingsPerPage     = 200
+)
+
+// meetingsFilter are the filters of the meetings listed on the chair page.
+// The fields keep the values of the request to render them again.
+type meetingsFilter struct {
+	Status string
+	From   string
+	To     string
+	Query  string
+}
+
+// parseMeetingsFilter extracts the filters from the 'status', 'from',
+// 'to' and 'q' parameters of the request.
+func parseMeetingsFilter(r *http.Request) *meetingsFilter {
+	return &meetingsFilter{
+		Status: r.FormValue("status"),
+		From:   r.FormValue("from"),
+		To:     r.FormValue("to"),
+		Query:  strings.TrimSpace(r.FormValue("q")),
+	}
+}
+
+// options converts the filter into options to search meetings.
+// The dates are interpreted in the given timezone. To is inclusive.
+func (mf *meetingsFilter) options(timezone string) (*models.MeetingsOptions, error) {
+	location, err := time.LoadLocation(timezone)
+	if err != nil {
+		location = time.UTC
+	}
+	opts := &models.MeetingsOptions{Description: mf.Query}
+	if mf.Status != "" {
+		status, err := models.ParseMeetingStatus(mf.Status)
+		if err != nil {
+			return nil, err
+		}
+		opts.Status = &status
+	}
+	if mf.From != "" {
+		from, err := time.ParseInLocation("2006-01-02", mf.From, location)
+		if err != nil {
+			return nil, err
+		}
+		opts.Since = &from
+	}
+	if mf.To != "" {
+		to, err := time.ParseInLocation("2006-01-02", mf.To, location)
+		if err != nil {
+			return nil, err
+		}
+		to = to.AddDate(0, 0, 1)
+		opts.Until = &to
+	}
+	return opts, nil
+}
+
 func (c *Controller) chair(w http.ResponseWriter, r *http.Request) {
+	c.chairError(w, r, "")
+}
+
+func (c *Controller) chairError(
+	w http.ResponseWriter,
+	r *http.Request,
+	errKey string,
+	errArgs ...any,
+) {
 	ctx := r.Context()
 	user := auth.UserFromContext(ctx)
-	meetings, err := models.LoadMeetings(
-		ctx, c.db,
-		misc.Map(user.Committees(), (*models.Committee).GetID))
+	filter := parseMeetingsFilter(r)
+	opts, err := filter.options(user.PreferredTimezone())
+	if !checkParam(w, err) {
+		return
+	}
+	committeeIDs := misc.Map(user.Committees(), (*models.Committee).GetID)
+	page := parsePagination(r, defaultMeetingsPerPage, maxMeetingsPerPage)
+	meetings, total, err := models.SearchMeetings(
+		ctx, c.db, committeeIDs, opts, page.Offset(), page.PerPage)
 	if !check(w, r, err) {
 		return
 	}
-	data := templateData{
-		"Session":  auth.SessionFromContext(ctx),
-		"User":     user,
-		"Meetings": meetings,
+	page.Total = total
+	// The running and next meetings are highlighted regardless of the filter.
+	now := time.Now()
+	highlights, err := models.LoadHighlightMeetings(ctx, c.db, committeeIDs, now)
+	if !check(w, r, err) {
+		return
+	}
+	atRisk := map[int64][]*models.User{}
+	for committee := range user.CommitteesWithRole(
+		models.ChairRole, models.SecretaryRole, models.StaffRole,
+	) {
+		users, err := models.MembersAtRisk(ctx, c.db, committee.ID)
+		if !check(w, r, err) {
+			return
+		}
+		atRisk[committee.ID] = users
+	}
+	live, next := highlightMeetings(highlights, now)
+	overrun := map[int64]*models.Meeting{}
+	for id, m := range live {
+		if m.Overrunning(now, c.cfg.Meetings.OverrunGrace) {
+			overrun[id] = m
+		}
 	}
-	check(w, r, c.tmpls.ExecuteTemplate(w, "chair.tmpl", data))
+	data := templateData{
+		"Session":    auth.SessionFromContext(ctx),
+		"User":       user,
+		"Meetings":   meetings,
+		"Filter":     filter,
+		"Pagination": page,
+		"AtRisk":     atRisk,
+		"Live":       live,
+		"Next":       next,
+		"Overrun":    overrun,
+	}
+	if errKey != "" {
+		data.error(errKey, errArgs...)
+	}
+	check(w, r, c.templates(r).ExecuteTemplate(w, "chair.tmpl", data))
 }
 
 func (c *Controller) absentOverview(w http.ResponseWriter, r *http.Request) {
+	c.absentOverviewError(w, r, "")
+}
+
+func (c *Controller) absentOverviewError(
+	w http.ResponseWriter,
+	r *http.Request,
+	errKey string,
+	errArgs ...any,
+) {
 	var (
 		committeeID, err = misc.Atoi64(r.FormValue("committee"))
 		ctx              = r.Context()
@@ -68,7 +196,10 @@ func (c *Controller) absentOverview(w http.ResponseWriter, r *http.Request) {
 		"Members":      members,
 		"MemberAbsent": memberAbsent,
 	}
-	check(w, r, c.tmpls.ExecuteTemplate(w, "absent_overview.tmpl", data))
+	if errKey != "" {
+		data.error(errKey, errArgs...)
+	}
+	check(w, r, c.templates(r).ExecuteTemplate(w, "absent_overview.tmpl", data))
 }
 
 func (c *Controller) absentStore(w http.ResponseWriter, r *http.Request) {
@@ -77,26 +208,90 @@ func (c *Controller) absentStore(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	ctx := r.Context()
-	if r.FormValue("delete") != "" {
-		parseAbsentEntries := func(s string) (string, time.Time, error) {
-			split := strings.Split(s, ";")
-			if len(split) != 2 {
-				return "", time.Time{}, errors.New("invalid entry length")
-			}
-			t, err := time.Parse("2006-01-02T15:04:05Z07:00", split[1])
-			if err != nil {
-				return "", time.Time{}, err
-			}
-			return split[0], t, nil
+	parseAbsentEntries := func(s string) (string, time.Time, error) {
+		split := strings.Split(s, ";")
+		if len(split) != 2 {
+			return "", time.Time{}, errors.New("invalid entry length")
+		}
+		t, err := time.Parse("2006-01-02T15:04:05Z07:00", split[1])
+		if err != nil {
+			return "", time.Time{}, err
 		}
-		ids := misc.ParseSeq2(slices.Values(r.Form["entries"]), parseAbsentEntries)
+		return split[0], t, nil
+	}
+	ids, errs := misc.ParseSeq2Errors(slices.Values(r.Form["entries"]), parseAbsentEntries)
+	// Don't act on a subset of the selected entries.
+	if len(errs) > 0 {
+		c.absentOverviewError(w, r, "error.invalid_entries", len(errs))
+		return
+	}
+	switch {
+	case r.FormValue("delete") != "":
 		if !check(w, r, models.DeleteAbsentEntries(ctx, c.db, committeeID, ids)) {
 			return
 		}
+	case r.FormValue("approve") != "":
+		if !check(w, r, models.UpdateAbsentEntriesStatus(
+			ctx, c.db, committeeID, ids, models.AbsentApproved)) {
+			return
+		}
+	case r.FormValue("reject") != "":
+		if !check(w, r, models.UpdateAbsentEntriesStatus(
+			ctx, c.db, committeeID, ids, models.AbsentRejected)) {
+			return
+		}
 	}
 	c.absentOverview(w, r)
 }
 
+// parseAbsentRange parses the start and stop time of an excused absent
+// in the given timezone. Problems are reported as errors in data.
+func parseAbsentRange(data templateData, startTime, stopTime, timezone string) (time.Time, time.Time) {
+	location, errL := time.LoadLocation(timezone)
+	if errL != nil {
+		data.error("error.invalid_timezone")
+		location = time.UTC
+	}
+	start, errStart := time.ParseInLocation("2006-01-02T15:04", startTime, location)
+	if errStart == nil {
+		start = start.UTC()
+	}
+
+	stop, errStop := time.ParseInLocation("2006-01-02T15:04", stopTime, location)
+	if errStop == nil {
+		stop = stop.UTC()
+	}
+
+	switch {
+	case errStart != nil && errStop != nil:
+		data.error("error.start_stop_invalid")
+	case errStart != nil:
+		data.error("error.start_invalid")
+	case errStop != nil:
+		data.error("error.stop_invalid")
+	}
+	return start, stop
+}
+
+// checkNewAbsent checks if a new excused absent collides with
+// the existing ones or exceeds the maximum absent time.
+// Problems are reported as errors in data.
+func (c *Controller) checkNewAbsent(
+	data templateData,
+	memberAbsent models.MemberAbsents,
+	m *models.MemberAbsent,
+) {
+	if memberAbsent.Contains(models.MemberAbsentOverlapFilter(m.Name, m.StartTime, m.StopTime)) {
+		data.error("error.absent_collides")
+		return
+	}
+	maxAbsent := c.cfg.Absent.MaxDuration
+	if !slices.Concat(memberAbsent, models.MemberAbsents{m}).
+		CheckMaximumAbsentTime(maxAbsent, m.Name) {
+		data.error("error.absent_too_long", daysHoursMinutes(maxAbsent))
+	}
+}
+
 func (c *Controller) absentCreateStore(w http.ResponseWriter, r *http.Request) {
 	committeeID, err := misc.Atoi64(r.FormValue("committee"))
 	if !checkParam(w, err) {
@@ -120,36 +315,15 @@ func (c *Controller) absentCreateStore(w http.ResponseWriter, r *http.Request) {
 		"Committee": committee,
 	}
 
-	location, errL := time.LoadLocation(timezone)
-	if errL != nil {
-		data.error("Invalid timezone.")
-		location = time.UTC
-	}
-	start, errStart := time.ParseInLocation("2006-01-02T15:04", startTime, location)
-	if errStart == nil {
-		start = start.UTC()
-	}
-
-	stop, errStop := time.ParseInLocation("2006-01-02T15:04", stopTime, location)
-	if errStop == nil {
-		stop = stop.UTC()
-	}
-
-	switch {
-	case errStart != nil && errStop != nil:
-		data.error("Start time and stop time are invalid.")
-	case errStart != nil:
-		data.error("Start time is invalid.")
-	case errStop != nil:
-		data.error("Stop time is invalid.")
-	}
+	start, stop := parseAbsentRange(data, startTime, stopTime, timezone)
 
 	var m models.MemberAbsent
 	m.Name = nickname
 	m.StartTime = start
 	m.StopTime = stop
+	m.Status = models.AbsentApproved
 	if data.hasError() {
-		check(w, r, c.tmpls.ExecuteTemplate(w, "absent_overview.tmpl", data))
+		check(w, r, c.templates(r).ExecuteTemplate(w, "absent_overview.tmpl", data))
 		return
 	}
 	memberAbsent, err := models.LoadAbsent(ctx, c.db, committeeID)
@@ -157,14 +331,8 @@ func (c *Controller) absentCreateStore(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	data["MemberAbsent"] = memberAbsent
-	if memberAbsent.Contains(models.MemberAbsentOverlapFilter(m.Name, m.StartTime, m.StopTime)) {
-		data.error("Time range collides with another excused absent in this committee.")
-		check(w, r, c.tmpls.ExecuteTemplate(w, "absent_overview.tmpl", data))
-		return
-	}
-	if !memberAbsent.CheckMaximumAbsentTime(time.Hour*24*40, m.Name) {
-		data.error("Maximum absent time is too large.")
-		check(w, r, c.tmpls.ExecuteTemplate(w, "absent_overview.tmpl", data))
+	if c.checkNewAbsent(data, memberAbsent, &m); data.hasError() {
+		check(w, r, c.templates(r).ExecuteTemplate(w, "absent_overview.tmpl", data))
 		return
 	}
 	if !check(w, r, m.StoreNew(ctx, c.db, committeeID)) {
@@ -180,23 +348,65 @@ func (c *Controller) meetingsStore(w http.ResponseWriter, r *http.Request) {
 	}
 	ctx := r.Context()
 	if r.FormValue("delete") != "" {
-		ids := misc.ParseSeq(slices.Values(r.Form["meetings"]), misc.Atoi64)
-		if !check(w, r, models.DeleteMeetingsByID(ctx, c.db, committeeID, ids)) {
+		ids, errs := misc.ParseSeqErrors(slices.Values(r.Form["meetings"]), misc.Atoi64)
+		// Don't act on a subset of the selected meetings.
+		if len(errs) > 0 {
+			c.chairError(w, r, "error.invalid_entries", len(errs))
 			return
 		}
+		selectedIDs := slices.Collect(ids)
+		meetings, err := models.LoadMeetings(ctx, c.db, misc.Values(committeeID))
+		if !check(w, r, err) {
+			return
+		}
+		tz := auth.UserFromContext(ctx).PreferredTimezone()
+		d := &deletion{
+			Action: "/meetings_store",
+			Back:   "/chair?SESSIONID=" + url.QueryEscape(auth.SessionFromContext(ctx).ID()),
+			Submit: "delete",
+			Verb:   "deleted",
+			Field:  "meetings",
+			Hidden: map[string]string{"committee": strconv.FormatInt(committeeID, 10)},
+		}
+		// Concluded meetings are never deleted.
+		for m := range meetings.Filter(func(m *models.Meeting) bool {
+			return m.Status != models.MeetingConcluded && slices.Contains(selectedIDs, m.ID)
+		}) {
+			label := inTZ(m.StartTime, tz).Format("2006-01-02 15:04 MST")
+			if m.Description != nil {
+				label += ": " + misc.Shorten(*m.Description)
+			}
+			d.Entries = append(d.Entries, &deletionEntry{
+				Value: strconv.FormatInt(m.ID, 10),
+				Label: label,
+			})
+		}
+		switch selected := len(selectedIDs); {
+		case len(d.Entries) == 0:
+			if selected > 0 {
+				c.chairError(w, r, "error.meetings_not_deleted", 0, selected)
+				return
+			}
+		case len(d.Entries) < selected:
+			c.confirmDeletionError(w, r, d,
+				"error.meetings_concluded", selected-len(d.Entries), selected)
+			return
+		case !c.confirmDeletion(w, r, d):
+			return
+		default:
+			ids = misc.ParseSeq(slices.Values(d.Values()), misc.Atoi64)
+			deleted, err := models.DeleteMeetingsByID(ctx, c.db, committeeID, ids)
+			if !check(w, r, err) {
+				return
+			}
+			// Meetings may have been concluded in the meantime.
+			if deleted < int64(len(d.Entries)) {
+				c.chairError(w, r, "error.meetings_not_deleted", deleted, len(d.Entries))
+				return
+			}
+		}
 	}
-	user := auth.UserFromContext(ctx)
-	remaining, err := models.LoadMeetings(ctx, c.db,
-		misc.Map(user.Committees(), (*models.Committee).GetID))
-	if !check(w, r, err) {
-		return
-	}
-	data := templateData{
-		"Session":  auth.SessionFromContext(ctx),
-		"User":     user,
-		"Meetings": remaining,
-	}
-	check(w, r, c.tmpls.ExecuteTemplate(w, "chair.tmpl", data))
+	c.chair(w, r)
 }
 
 func (c *Controller) meetingCreate(w http.ResponseWriter, r *http.Request) {
@@ -215,7 +425,50 @@ func (c *Controller) meetingCreate(w http.ResponseWriter, r *http.Request) {
 		},
 		"Committee": committee,
 	}
-	check(w, r, c.tmpls.ExecuteTemplate(w, "meeting_create.tmpl", data))
+	check(w, r, c.templates(r).ExecuteTemplate(w, "meeting_create.tmpl", data))
+}
+
+func (c *Controller) meetingClone(w http.ResponseWriter, r *http.Request) {
+	var (
+		meetingID, err1   = misc.Atoi64(r.FormValue("meeting"))
+		committeeID, err2 = misc.Atoi64(r.FormValue("committee"))
+	)
+	if !checkParam(w, err1, err2) {
+		return
+	}
+	ctx := r.Context()
+	meeting, err := models.LoadMeeting(ctx, c.db, meetingID, committeeID)
+	if !check(w, r, err) {
+		return
+	}
+	if meeting == nil {
+		c.chair(w, r)
+		return
+	}
+	// Only the time slot has to be picked for the clone.
+	// Attendance and status are not cloned.
+	data := templateData{
+		"Session": auth.SessionFromContext(ctx),
+		"User":    auth.UserFromContext(ctx),
+		"Meeting": &models.Meeting{
+			StopTime:    time.Time{}.Add(meeting.Duration()),
+			Gathering:   meeting.Gathering,
+			Description: meeting.Description,
+			Agenda:      meeting.Agenda,
+		},
+		"Committee": committeeID,
+	}
+	check(w, r, c.templates(r).ExecuteTemplate(w, "meeting_create.tmpl", data))
+}
+
+// parseStartTime parses the start time of a meeting entered in
+// the given location and returns it in UTC.
+func parseStartTime(startTime string, location *time.Location) (time.Time, error) {
+	s, err := time.ParseInLocation("2006-01-02T15:04", startTime, location)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return s.UTC(), nil
 }
 
 func (c *Controller) meetingCreateStore(w http.ResponseWriter, r *http.Request) {
@@ -224,7 +477,8 @@ func (c *Controller) meetingCreateStore(w http.ResponseWriter, r *http.Request)
 		return
 	}
 	var (
-		description = misc.NilString(strings.TrimSpace(r.FormValue("description")))
+		description = misc.NilString(misc.SanitizeText(r.FormValue("description")))
+		agenda      = misc.NilString(misc.SanitizeText(r.FormValue("agenda")))
 		startTime   = r.FormValue("start_time")
 		duration    = r.FormValue("duration")
 		timezone    = r.FormValue("timezone")
@@ -236,6 +490,7 @@ func (c *Controller) meetingCreateStore(w http.ResponseWriter, r *http.Request)
 		CommitteeID: committee,
 		Gathering:   gathering,
 		Description: description,
+		Agenda:      agenda,
 	}
 	data := templateData{
 		"Session":   auth.SessionFromContext(ctx),
@@ -246,30 +501,35 @@ func (c *Controller) meetingCreateStore(w http.ResponseWriter, r *http.Request)
 
 	location, errL := time.LoadLocation(timezone)
 	if errL != nil {
-		data.error("Invalid timezone.")
+		data.error("error.invalid_timezone")
 		location = time.UTC
 	}
-	s, errS := time.ParseInLocation("2006-01-02T15:04", startTime, location)
-	if errS == nil {
-		s = s.UTC()
-	}
+	s, errS := parseStartTime(startTime, location)
 
 	switch {
 	case errS != nil && errD != nil:
-		data.error("Start time and duration are invalid.")
+		data.error("error.start_duration_invalid")
 		s, d = time.Now(), time.Hour
 	case errS != nil:
-		data.error("Start time is invalid.")
+		data.error("error.start_invalid")
 		s = time.Now()
 	case errD != nil:
-		data.error("Duration is invalid.")
+		data.error("error.duration_invalid")
+		d = time.Hour
+	case d <= 0:
+		data.error("error.duration_not_positive")
+		d = time.Hour
+	case d > c.cfg.Meetings.MaxDuration:
+		data.error("error.duration_too_long",
+			hoursMinutes(c.cfg.Meetings.MaxDuration))
 		d = time.Hour
 	}
+	checkMeetingTexts(data, description, agenda)
 
 	meeting.StartTime = s
 	meeting.StopTime = s.Add(d)
 	if data.hasError() {
-		check(w, r, c.tmpls.ExecuteTemplate(w, "meeting_create.tmpl", data))
+		check(w, r, c.templates(r).ExecuteTemplate(w, "meeting_create.tmpl", data))
 		return
 	}
 	meetings, err := models.LoadMeetings(ctx, c.db, misc.Values(committee))
@@ -277,17 +537,42 @@ func (c *Controller) meetingCreateStore(w http.ResponseWriter, r *http.Request)
 		return
 	}
 	if meetings.Contains(models.OverlapFilter(meeting.StartTime, meeting.StopTime)) {
-		data.error("Time range collides with another meeting in this committee.")
-		check(w, r, c.tmpls.ExecuteTemplate(w, "meeting_create.tmpl", data))
+		data.error("error.meeting_collides")
+		check(w, r, c.templates(r).ExecuteTemplate(w, "meeting_create.tmpl", data))
 		return
 	}
-	if !check(w, r, meeting.StoreNew(ctx, c.db)) {
+	switch err := meeting.StoreNew(ctx, c.db); {
+	case errors.Is(err, models.ErrCommitteeArchived):
+		data.error("error.committee_archived")
+		check(w, r, c.templates(r).ExecuteTemplate(w, "meeting_create.tmpl", data))
+		return
+	case !check(w, r, err):
 		return
 	}
 	c.chair(w, r)
 }
 
+// checkMeetingTexts checks the lengths of the description
+// and the agenda of a meeting.
+func checkMeetingTexts(data templateData, description, agenda *string) {
+	if textTooLong(description, maxDescriptionLength) {
+		data.error("error.description_too_long", maxDescriptionLength)
+	}
+	if textTooLong(agenda, maxAgendaLength) {
+		data.error("error.agenda_too_long", maxAgendaLength)
+	}
+}
+
 func (c *Controller) meetingEdit(w http.ResponseWriter, r *http.Request) {
+	c.meetingEditError(w, r, "")
+}
+
+func (c *Controller) meetingEditError(
+	w http.ResponseWriter,
+	r *http.Request,
+	errKey string,
+	errArgs ...any,
+) {
 	var (
 		meetingID, err1   = misc.Atoi64(r.FormValue("meeting"))
 		committeeID, err2 = misc.Atoi64(r.FormValue("committee"))
@@ -310,24 +595,160 @@ func (c *Controller) meetingEdit(w http.ResponseWriter, r *http.Request) {
 		"Meeting":   meeting,
 		"Committee": committeeID,
 	}
-	check(w, r, c.tmpls.ExecuteTemplate(w, "meeting_edit.tmpl", data))
+	if errKey != "" {
+		data.error(errKey, errArgs...)
+	}
+	c.meetingEditRender(w, r, meeting, data)
+}
+
+// meetingEditRender adds the move targets and the members
+// of the meeting to the data and renders the edit page.
+func (c *Controller) meetingEditRender(
+	w http.ResponseWriter,
+	r *http.Request,
+	meeting *models.Meeting,
+	data templateData,
+) {
+	ctx := r.Context()
+	targets, err := c.meetingMoveTargets(r, auth.UserFromContext(ctx), meeting.CommitteeID)
+	if !check(w, r, err) {
+		return
+	}
+	members, err := c.meetingMembers(r, meeting)
+	if !check(w, r, err) {
+		return
+	}
+	expected, err := models.LoadMeetingExpected(ctx, c.db, meeting.ID)
+	if !check(w, r, err) {
+		return
+	}
+	data["Targets"] = targets
+	data["Members"] = members
+	data["Expected"] = expected
+	check(w, r, c.templates(r).ExecuteTemplate(w, "meeting_edit.tmpl", data))
+}
+
+// meetingMoveTargets returns the committees a meeting of the
+// given committee can be moved to by the user.
+// Admins can move meetings between all not archived committees.
+// Chairs can move meetings between the committees they chair.
+func (c *Controller) meetingMoveTargets(
+	r *http.Request,
+	user *models.User,
+	committeeID int64,
+) ([]*models.Committee, error) {
+	var committees []*models.Committee
+	switch {
+	case user.IsAdmin:
+		var err error
+		if committees, err = models.LoadCommittees(r.Context(), c.db); err != nil {
+			return nil, err
+		}
+	case user.MembershipByID(committeeID).HasRole(models.ChairRole):
+		committees = slices.Collect(user.CommitteesWithRole(models.ChairRole))
+	}
+	return slices.DeleteFunc(committees, func(c *models.Committee) bool {
+		return c.ID == committeeID || c.Archived
+	}), nil
+}
+
+func (c *Controller) meetingMove(w http.ResponseWriter, r *http.Request) {
+	var (
+		meetingID, err1   = misc.Atoi64(r.FormValue("meeting"))
+		committeeID, err2 = misc.Atoi64(r.FormValue("committee"))
+		targetID, err3    = misc.Atoi64(r.FormValue("target"))
+		ctx               = r.Context()
+	)
+	if !checkParam(w, err1, err2, err3) {
+		return
+	}
+	targets, err := c.meetingMoveTargets(r, auth.UserFromContext(ctx), committeeID)
+	if !check(w, r, err) {
+		return
+	}
+	if !slices.ContainsFunc(targets, func(c *models.Committee) bool { return c.ID == targetID }) {
+		http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+		return
+	}
+	switch err := models.MoveMeeting(ctx, c.db, meetingID, committeeID, targetID); {
+	case errors.Is(err, models.ErrMeetingConcluded):
+		c.meetingEditError(w, r, "error.meeting_concluded")
+	case errors.Is(err, models.ErrMeetingCollides):
+		c.meetingEditError(w, r, "error.meeting_move_collides")
+	case errors.Is(err, models.ErrAlreadyRunning):
+		c.meetingEditError(w, r, "error.already_running")
+	case errors.Is(err, models.ErrCommitteeArchived):
+		c.meetingEditError(w, r, "error.committee_archived")
+	case check(w, r, err):
+		c.chair(w, r)
+	}
+}
+
+// meetingMembers returns the members of the committee
+// of a meeting at its start sorted by their names.
+func (c *Controller) meetingMembers(r *http.Request, meeting *models.Meeting) ([]*models.User, error) {
+	users, err := models.LoadCommitteeUsers(r.Context(), c.db, meeting.CommitteeID, &meeting.StartTime)
+	if err != nil {
+		return nil, err
+	}
+	crit := models.MembershipByID(meeting.CommitteeID)
+	members := slices.DeleteFunc(users, func(u *models.User) bool {
+		ms := u.FindMembershipCriterion(crit)
+		return ms == nil || !ms.HasRole(models.MemberRole)
+	})
+	slices.SortFunc(members, (*models.User).Compare)
+	return members, nil
+}
+
+func (c *Controller) meetingExpectedStore(w http.ResponseWriter, r *http.Request) {
+	var (
+		meetingID, err1   = misc.Atoi64(r.FormValue("meeting"))
+		committeeID, err2 = misc.Atoi64(r.FormValue("committee"))
+		ctx               = r.Context()
+	)
+	if !checkParam(w, err1, err2) {
+		return
+	}
+	meeting, err := models.LoadMeeting(ctx, c.db, meetingID, committeeID)
+	if !check(w, r, err) {
+		return
+	}
+	if meeting == nil {
+		c.chair(w, r)
+		return
+	}
+	members, err := c.meetingMembers(r, meeting)
+	if !check(w, r, err) {
+		return
+	}
+	// Only members of the committee can be expected.
+	selected := r.Form["expected"]
+	nicknames := misc.Filter(
+		misc.Map(slices.Values(members), func(u *models.User) string { return u.Nickname }),
+		func(nickname string) bool { return slices.Contains(selected, nickname) })
+	switch err := models.SetMeetingExpected(ctx, c.db, meetingID, committeeID, nicknames); {
+	case errors.Is(err, models.ErrMeetingConcluded):
+		c.meetingEditError(w, r, "error.meeting_concluded")
+	case check(w, r, err):
+		c.meetingEdit(w, r)
+	}
 }
 
 func (c *Controller) meetingEditStore(w http.ResponseWriter, r *http.Request) {
 	var (
 		meetingID, err1   = misc.Atoi64(r.FormValue("meeting"))
 		committeeID, err2 = misc.Atoi64(r.FormValue("committee"))
-		description       = misc.NilString(strings.TrimSpace(r.FormValue("description")))
+		description       = misc.NilString(misc.SanitizeText(r.FormValue("description")))
+		agenda            = misc.NilString(misc.SanitizeText(r.FormValue("agenda")))
 		startTime         = r.FormValue("start_time")
 		duration          = r.FormValue("duration")
 		timezone          = r.FormValue("timezone")
 		gathering         = r.FormValue("gathering") != ""
+		version, err3     = misc.Atoi64(r.FormValue("version"))
 		d, errD           = parseDuration(duration)
 		ctx               = r.Context()
-		s                 time.Time
-		errS              error
 	)
-	if !checkParam(w, err1, err2) {
+	if !checkParam(w, err1, err2, err3) {
 		return
 	}
 	meeting, err := models.LoadMeeting(ctx, c.db, meetingID, committeeID)
@@ -338,7 +759,10 @@ func (c *Controller) meetingEditStore(w http.ResponseWriter, r *http.Request) {
 		c.chair(w, r)
 		return
 	}
+	// Only accept changes based on the current state.
+	meeting.Version = version
 	meeting.Description = description
+	meeting.Agenda = agenda
 	data := templateData{
 		"Session":   auth.SessionFromContext(ctx),
 		"User":      auth.UserFromContext(ctx),
@@ -348,29 +772,35 @@ func (c *Controller) meetingEditStore(w http.ResponseWriter, r *http.Request) {
 
 	location, errL := time.LoadLocation(timezone)
 	if errL != nil {
-		data.error("Invalid timezone.")
+		data.error("error.invalid_timezone")
 		location = time.UTC
 	}
-	if s, errS = time.ParseInLocation("2006-01-02T15:04", startTime, location); errS != nil {
-		s = s.UTC()
-	}
+	s, errS := parseStartTime(startTime, location)
 
 	switch {
 	case errS != nil && errD != nil:
-		data.error("Start time and duration are invalid.")
+		data.error("error.start_duration_invalid")
 		s, d = time.Now(), time.Hour
 	case errS != nil:
-		data.error("Start time is invalid.")
+		data.error("error.start_invalid")
 		s = time.Now()
 	case errD != nil:
-		data.error("Duration is invalid.")
+		data.error("error.duration_invalid")
+		d = time.Hour
+	case d <= 0:
+		data.error("error.duration_not_positive")
+		d = time.Hour
+	case d > c.cfg.Meetings.MaxDuration:
+		data.error("error.duration_too_long",
+			hoursMinutes(c.cfg.Meetings.MaxDuration))
 		d = time.Hour
 	}
+	checkMeetingTexts(data, description, agenda)
 
 	meeting.StartTime = s
 	meeting.StopTime = s.Add(d)
 	if data.hasError() {
-		check(w, r, c.tmpls.ExecuteTemplate(w, "meeting_edit.tmpl", data))
+		c.meetingEditRender(w, r, meeting, data)
 		return
 	}
 	meetings, err := models.LoadMeetings(ctx, c.db, misc.Values(committeeID))
@@ -379,12 +809,25 @@ func (c *Controller) meetingEditStore(w http.ResponseWriter, r *http.Request) {
 	}
 	if meetings.Contains(
 		models.OverlapFilter(meeting.StartTime, meeting.StopTime, meetingID)) {
-		data.error("Time range collides with another meeting in this committee.")
-		check(w, r, c.tmpls.ExecuteTemplate(w, "meeting_edit.tmpl", data))
+		data.error("error.meeting_collides")
+		c.meetingEditRender(w, r, meeting, data)
 		return
 	}
 	meeting.Gathering = gathering
-	if !check(w, r, meeting.Store(ctx, c.db)) {
+	switch err := meeting.Store(ctx, c.db); {
+	case errors.Is(err, models.ErrModified):
+		if meeting, err = models.LoadMeeting(ctx, c.db, meetingID, committeeID); !check(w, r, err) {
+			return
+		}
+		if meeting == nil {
+			c.chair(w, r)
+			return
+		}
+		data["Meeting"] = meeting
+		data.error("error.modified_concurrently")
+		c.meetingEditRender(w, r, meeting, data)
+		return
+	case !check(w, r, err):
 		return
 	}
 	c.chair(w, r)
@@ -397,7 +840,22 @@ func (c *Controller) meetingStatus(w http.ResponseWriter, r *http.Request) {
 func (c *Controller) meetingStatusError(
 	w http.ResponseWriter,
 	r *http.Request,
-	errMsg string,
+	errKey string,
+	errArgs ...any,
+) {
+	c.meetingStatusRender(w, r, func(data templateData) {
+		if errKey != "" {
+			data.error(errKey, errArgs...)
+		}
+	})
+}
+
+// meetingStatusRender renders the status of a meeting.
+// The template data can be adjusted by the given function.
+func (c *Controller) meetingStatusRender(
+	w http.ResponseWriter,
+	r *http.Request,
+	adjust func(templateData),
 ) {
 	var (
 		meetingID, err1   = misc.Atoi64(r.FormValue("meeting"))
@@ -431,32 +889,27 @@ func (c *Controller) meetingStatusError(
 	if !check(w, r, err) {
 		return
 	}
+	expected, err := models.LoadMeetingExpected(ctx, c.db, meetingID)
+	if !check(w, r, err) {
+		return
+	}
 
-	var numVoters, attendingVoters, numNonVoters, numMembers int
-	for _, member := range members {
-		if ms := member.FindMembership(committee.Name); ms != nil &&
-			ms.HasRole(models.MemberRole) {
-			switch ms.Status {
-			case models.Voting:
-				numVoters++
-				if attendees[member.Nickname] {
-					attendingVoters++
-				}
-			case models.NoneVoting:
-				numNonVoters++
-			case models.Member:
-				numMembers++
-			}
+	quorum := calculateQuorum(members, committeeID, attendees, expected)
+
+	// Explain why absent members don't count against the quorum.
+	excused, err := models.LoadExcused(ctx, c.db, committeeID, meeting.StopTime)
+	if !check(w, r, err) {
+		return
+	}
+	for nickname := range excused {
+		if attendees.Attended(nickname) || !expected.Includes(nickname) {
+			delete(excused, nickname)
 		}
 	}
 
-	quorum := models.Quorum{
-		Total:           len(members),
-		Member:          numMembers,
-		Voting:          numVoters,
-		AttendingVoting: attendingVoters,
-		Attending:       len(attendees),
-		NonVoting:       numNonVoters,
+	documents, err := models.LoadMeetingDocuments(ctx, c.db, meetingID)
+	if !check(w, r, err) {
+		return
 	}
 
 	slices.SortFunc(members, (*models.User).Compare)
@@ -467,14 +920,24 @@ func (c *Controller) meetingStatusError(
 		"Meeting":        meeting,
 		"Members":        members,
 		"Attendees":      attendees,
-		"Quorum":         &quorum,
+		"Excused":        excused,
+		"Expected":       expected,
+		"Quorum":         quorum,
 		"Committee":      committee,
 		"AlreadyRunning": alreadyRunning,
+		"Documents":      documents,
 	}
-	if errMsg != "" {
-		data.error(errMsg)
-	}
-	check(w, r, c.tmpls.ExecuteTemplate(w, "meeting_status.tmpl", data))
+	adjust(data)
+	check(w, r, c.templates(r).ExecuteTemplate(w, "meeting_status.tmpl", data))
+}
+
+// countVoters counts the voting members of a committee.
+func countVoters(members []*models.User, committeeID int64) int {
+	crit := models.MembershipByID(committeeID)
+	return misc.Count(slices.Values(members), func(member *models.User) bool {
+		ms := member.FindMembershipCriterion(crit)
+		return ms != nil && ms.HasRole(models.MemberRole) && ms.Status == models.Voting
+	})
 }
 
 func (c *Controller) meetingStatusStore(w http.ResponseWriter, r *http.Request) {
@@ -493,6 +956,34 @@ func (c *Controller) meetingStatusStore(w http.ResponseWriter, r *http.Request)
 	if !check(w, r, err) {
 		return
 	}
+	if meeting == nil {
+		c.chair(w, r)
+		return
+	}
+
+	// Running a meeting without voting members makes the quorum meaningless.
+	// Depending on the configuration this is blocked or has to be confirmed.
+	if meetingStatus == models.MeetingRunning &&
+		meeting.Status != models.MeetingRunning &&
+		!meeting.Gathering &&
+		(c.cfg.Meetings.BlockWithoutVoters || r.FormValue("confirm") == "") {
+		voters, err := models.LoadCommitteeUsersByStatus(
+			ctx, c.db, committeeID, &meeting.StartTime, models.Voting)
+		if !check(w, r, err) {
+			return
+		}
+		if countVoters(voters, committeeID) == 0 {
+			if c.cfg.Meetings.BlockWithoutVoters {
+				c.meetingStatusError(w, r, "error.no_voters_run")
+				return
+			}
+			c.meetingStatusRender(w, r, func(data templateData) {
+				data.error("error.no_voters_quorum")
+				data["ConfirmRun"] = true
+			})
+			return
+		}
+	}
 
 	// Whether to use time.Now() or not
 	timer := misc.CalculateEndpoint(meeting.StartTime, meeting.StopTime)
@@ -502,26 +993,57 @@ func (c *Controller) meetingStatusStore(w http.ResponseWriter, r *http.Request)
 		timer,
 	); {
 	case errors.Is(err, models.ErrAlreadyRunning):
-		c.meetingStatusError(w, r, "Already have a running meeting in this committee.")
+		c.meetingStatusError(w, r, "error.already_running")
 		return
 	case errors.Is(err, models.ErrNewerConcluded):
-		c.meetingStatusError(w, r, "Already have a concluded meeting that is newer.")
+		c.meetingStatusError(w, r, "error.newer_concluded")
+		return
+	case errors.Is(err, models.ErrNotRunning):
+		c.meetingStatusError(w, r, "error.only_running_conclude")
 		return
 	case !check(w, r, err):
 		return
 	}
+	if meetingStatus != models.MeetingRunning {
+		c.quorum.forget(meetingID)
+	}
+	// Tell the event streams about the new status.
+	if c.events.subscribed(meetingID) {
+		if meeting, err = models.LoadMeeting(ctx, c.db, meetingID, committeeID); !check(w, r, err) {
+			return
+		}
+		if meeting != nil && !check(w, r, c.publishMeeting(ctx, meeting, nil)) {
+			return
+		}
+	}
 	c.meetingStatus(w, r)
 }
 
+// parsePresenceAction parses the action of an attendance change.
+// 'attend' marks as attending, 'novote' as attending without
+// taking part in votes and 'absent' as not attending.
+func parsePresenceAction(action string) (models.Presence, error) {
+	switch action {
+	case "attend":
+		return models.PresentVoting, nil
+	case "novote":
+		return models.PresentNonVoting, nil
+	case "absent":
+		return models.Absent, nil
+	default:
+		return 0, fmt.Errorf("unknown attendance action %q", action)
+	}
+}
+
 func (c *Controller) meetingAttendStore(w http.ResponseWriter, r *http.Request) {
 	var (
 		meetingID, err1   = misc.Atoi64(r.FormValue("meeting"))
 		committeeID, err2 = misc.Atoi64(r.FormValue("committee"))
-		attend            = !strings.Contains(strings.ToLower(r.FormValue("action")), "not attending")
-		rendered, err3    = misc.Atoi64(r.FormValue("rendered"))
+		presence, err3    = parsePresenceAction(r.FormValue("action"))
+		rendered, err4    = misc.Atoi64(r.FormValue("rendered"))
 		ctx               = r.Context()
 	)
-	if !checkParam(w, err1, err2, err3) {
+	if !checkParam(w, err1, err2, err3, err4) {
 		return
 	}
 	meeting, err := models.LoadMeeting(ctx, c.db, meetingID, committeeID)
@@ -556,17 +1078,118 @@ func (c *Controller) meetingAttendStore(w http.ResponseWriter, r *http.Request)
 			}
 		}
 	}
-	action := models.Attend
-	if !attend {
-		action = models.Unattend
+	var before *models.Quorum
+	if c.quorumWebhookEnabled() {
+		if before, err = c.loadQuorum(ctx, meeting, users); !check(w, r, err) {
+			return
+		}
 	}
-	if !check(w, r, action(ctx, c.db, meetingID, seq, time.UnixMicro(rendered).UTC())) {
+	if !check(w, r, models.AttendPresence(
+		ctx, c.db, meetingID, seq, presence, time.UnixMicro(rendered).UTC())) {
+		return
+	}
+	if before != nil && !check(w, r, c.notifyQuorumReached(ctx, meeting, users, before)) {
+		return
+	}
+	if !check(w, r, c.publishMeeting(ctx, meeting, users)) {
 		return
 	}
 	c.meetingStatus(w, r)
 }
 
+// attendToggleResult is the JSON answer of an attendance toggle.
+type attendToggleResult struct {
+	Meeting  int64             `json:"meeting"`
+	Nickname string            `json:"nickname"`
+	Attend   bool              `json:"attend"`
+	Presence string            `json:"presence"`
+	Voting   bool              `json:"voting"`
+	Reached  bool              `json:"reached"`
+	Quorum   quorumEventCounts `json:"quorum"`
+}
+
+func (c *Controller) meetingAttendToggle(w http.ResponseWriter, r *http.Request) {
+	var (
+		meetingID, err1   = misc.Atoi64(r.FormValue("meeting"))
+		committeeID, err2 = misc.Atoi64(r.FormValue("committee"))
+		presence, err3    = parsePresenceAction(r.FormValue("action"))
+		nickname          = r.FormValue("nickname")
+		ctx               = r.Context()
+	)
+	if !checkParam(w, err1, err2, err3) {
+		return
+	}
+	meeting, err := models.LoadMeeting(ctx, c.db, meetingID, committeeID)
+	if !check(w, r, err) {
+		return
+	}
+	if meeting == nil {
+		http.Error(w, "meeting not found", http.StatusNotFound)
+		return
+	}
+	if meeting.Status != models.MeetingRunning {
+		http.Error(w, "meeting is not running", http.StatusConflict)
+		return
+	}
+	users, err := models.LoadCommitteeUsers(ctx, c.db, committeeID, &meeting.StartTime)
+	if !check(w, r, err) {
+		return
+	}
+	idx := slices.IndexFunc(users, func(u *models.User) bool {
+		return u.Nickname == nickname
+	})
+	if idx == -1 {
+		http.Error(w, "member not found", http.StatusNotFound)
+		return
+	}
+	ms := users[idx].FindMembershipCriterion(models.MembershipByID(committeeID))
+	if ms == nil {
+		http.Error(w, "member not found", http.StatusNotFound)
+		return
+	}
+	voting := ms.Status == models.Voting && ms.HasRole(models.MemberRole)
+	var before *models.Quorum
+	if c.quorumWebhookEnabled() {
+		if before, err = c.loadQuorum(ctx, meeting, users); !check(w, r, err) {
+			return
+		}
+	}
+	if !check(w, r, models.UpdateAttendeePresence(ctx, c.db, meetingID, nickname, presence, voting)) {
+		return
+	}
+	if before != nil && !check(w, r, c.notifyQuorumReached(ctx, meeting, users, before)) {
+		return
+	}
+	if !check(w, r, c.publishMeeting(ctx, meeting, users)) {
+		return
+	}
+	quorum, err := c.loadQuorum(ctx, meeting, users)
+	if !check(w, r, err) {
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	check(w, r, json.NewEncoder(w).Encode(&attendToggleResult{
+		Meeting:  meetingID,
+		Nickname: nickname,
+		Attend:   presence != models.Absent,
+		Presence: presence.String(),
+		Voting:   voting,
+		Reached:  quorum.Reached(),
+		Quorum:   newQuorumEventCounts(quorum),
+	}))
+}
+
 func (c *Controller) meetingsOverview(w http.ResponseWriter, r *http.Request) {
+	c.meetingsOverviewError(w, r, "")
+}
+
+func (c *Controller) meetingsOverviewError(
+	w http.ResponseWriter,
+	r *http.Request,
+	errKey string,
+	errArgs ...any,
+) {
 	var (
 		committeeID, err = misc.Atoi64(r.FormValue("committee"))
 		ctx              = r.Context()
@@ -578,9 +1201,48 @@ func (c *Controller) meetingsOverview(w http.ResponseWriter, r *http.Request) {
 	if !check(w, r, err) {
 		return
 	}
-	// Number of meetings to load.
-	const limit = -1
-	overview, err := models.LoadMeetingsOverview(ctx, c.db, committeeID, limit)
+	page := parsePagination(r, defaultMeetingsPerPage, maxMeetingsPerPage)
+	overview, err := models.LoadMeetingsOverview(
+		ctx, c.db, committeeID, page.Offset(), page.PerPage)
+	if !check(w, r, err) {
+		return
+	}
+	page.Total = overview.Total
+	user := auth.UserFromContext(ctx)
+	data := templateData{
+		"Session":    auth.SessionFromContext(ctx),
+		"User":       user,
+		"Committee":  committee,
+		"Overview":   overview,
+		"Pagination": page,
+	}
+	// Chairs need the users of the committee to transfer the chair.
+	if ms := user.MembershipByID(committeeID); ms != nil && ms.HasRole(models.ChairRole) {
+		users, err := models.LoadCommitteeUsers(ctx, c.db, committeeID, nil)
+		if !check(w, r, err) {
+			return
+		}
+		data["Users"] = users
+	}
+	if errKey != "" {
+		data.error(errKey, errArgs...)
+	}
+	check(w, r, c.templates(r).ExecuteTemplate(w, "meetings_overview.tmpl", data))
+}
+
+func (c *Controller) committeeStats(w http.ResponseWriter, r *http.Request) {
+	var (
+		committeeID, err = misc.Atoi64(r.FormValue("committee"))
+		ctx              = r.Context()
+	)
+	if !checkParam(w, err) {
+		return
+	}
+	committee, err := models.LoadCommittee(ctx, c.db, committeeID)
+	if !check(w, r, err) {
+		return
+	}
+	stats, err := models.CommitteeStats(ctx, c.db, committeeID)
 	if !check(w, r, err) {
 		return
 	}
@@ -588,9 +1250,113 @@ func (c *Controller) meetingsOverview(w http.ResponseWriter, r *http.Request) {
 		"Session":   auth.SessionFromContext(ctx),
 		"User":      auth.UserFromContext(ctx),
 		"Committee": committee,
-		"Overview":  overview,
+		"Stats":     stats,
+	}
+	check(w, r, c.templates(r).ExecuteTemplate(w, "committee_stats.tmpl", data))
+}
+
+func (c *Controller) memberStatusRevert(w http.ResponseWriter, r *http.Request) {
+	var (
+		committeeID, err = misc.Atoi64(r.FormValue("committee"))
+		nickname         = r.FormValue("nickname")
+		ctx              = r.Context()
+	)
+	if !checkParam(w, err) {
+		return
+	}
+	switch err := models.RevertLastStatusChange(ctx, c.db, nickname, committeeID); {
+	case errors.Is(err, models.ErrNoStatusChange):
+		c.meetingsOverviewError(w, r, "error.no_status_change", nickname)
+		return
+	case !check(w, r, err):
+		return
+	}
+	c.meetingsOverview(w, r)
+}
+
+func (c *Controller) committeeBulkStatusStore(w http.ResponseWriter, r *http.Request) {
+	var (
+		committeeID, err = misc.Atoi64(r.FormValue("committee"))
+		nicknames        = r.Form["nicknames"]
+		ctx              = r.Context()
+	)
+	if !checkParam(w, err) {
+		return
+	}
+	status, err := models.ParseMemberStatus(r.FormValue("status"))
+	if !checkParam(w, err) {
+		return
+	}
+	switch {
+	case status == models.NoMember:
+		c.meetingsOverviewError(w, r, "error.members_not_removable")
+		return
+	case len(nicknames) == 0:
+		c.meetingsOverviewError(w, r, "error.no_members_selected")
+		return
+	}
+	switch err := models.UpdateCommitteeStatus(ctx, c.db, committeeID, nicknames, status); {
+	case errors.Is(err, models.ErrNotMember):
+		c.meetingsOverviewError(w, r, "error.only_members_status")
+		return
+	case !check(w, r, err):
+		return
+	}
+	c.meetingsOverview(w, r)
+}
+
+func (c *Controller) chairTransfer(w http.ResponseWriter, r *http.Request) {
+	var (
+		committeeID, err = misc.Atoi64(r.FormValue("committee"))
+		to               = r.FormValue("to")
+		redirect         = r.FormValue("redirect")
+		ctx              = r.Context()
+		user             = auth.UserFromContext(ctx)
+		from             = user.Nickname
+	)
+	if !checkParam(w, err) {
+		return
+	}
+	// Admins may hand over the chair of others.
+	if f := r.FormValue("from"); user.IsAdmin && f != "" {
+		from = f
+	}
+	errorPage := c.meetingsOverviewError
+	if redirect == "committee_edit" {
+		errorPage = c.committeeEditError
+	}
+	switch err := models.TransferChair(ctx, c.db, committeeID, from, to); {
+	case errors.Is(err, models.ErrNotChair):
+		errorPage(w, r, "error.not_chair", from)
+		return
+	case errors.Is(err, models.ErrAlreadyChair):
+		errorPage(w, r, "error.already_chair", to)
+		return
+	case errors.Is(err, models.ErrNotInCommittee):
+		errorPage(w, r, "error.not_active_member", to)
+		return
+	case !check(w, r, err):
+		return
+	}
+	if redirect == "committee_edit" {
+		c.committeeEdit(w, r)
+		return
+	}
+	// A former chair without other roles cannot see the overview any more.
+	target := "/"
+	if ms := user.MembershipByID(committeeID); from != user.Nickname ||
+		(ms != nil && ms.HasAnyRole(models.MemberRole, models.SecretaryRole, models.StaffRole)) {
+		target = fmt.Sprintf("/meetings_overview?committee=%d", committeeID)
+	}
+	http.Redirect(w, r, auth.SessionFromContext(ctx).URL(target), http.StatusSeeOther)
+}
+
+// formatOptionalTime formats an optional time for the CSV export.
+func formatOptionalTime(t *time.Time) string {
+	if t == nil {
+		return ""
 	}
-	check(w, r, c.tmpls.ExecuteTemplate(w, "meetings_overview.tmpl", data))
+	return t.UTC().Format("2006-01-02 15:04:05")
 }
 
 func (c *Controller) meetingsExport(w http.ResponseWriter, r *http.Request) {
@@ -601,8 +1367,12 @@ func (c *Controller) meetingsExport(w http.ResponseWriter, r *http.Request) {
 	if !checkParam(w, err) {
 		return
 	}
-	const limit = -1
-	overview, err := models.LoadMeetingsOverview(ctx, c.db, committeeID, limit)
+	const offset, limit = 0, -1
+	overview, err := models.LoadMeetingsOverview(ctx, c.db, committeeID, offset, limit)
+	if !check(w, r, err) {
+		return
+	}
+	lastModified, err := models.LoadMeetingsLastModified(ctx, c.db, committeeID)
 	if !check(w, r, err) {
 		return
 	}
@@ -611,9 +1381,10 @@ func (c *Controller) meetingsExport(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "text/csv")
 	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment;filename=meetings_%d.csv", committeeID))
 
-	// Create CSV writer
-	writer := csv.NewWriter(w)
-	defer writer.Flush()
+	// Create CSV writer. The export is buffered to be able
+	// to answer conditional requests.
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
 
 	// Write CSV header
 	header := []string{
@@ -629,6 +1400,8 @@ func (c *Controller) meetingsExport(w http.ResponseWriter, r *http.Request) {
 		"Total Voters",
 		"Attendees",
 		"Non-Attendees",
+		"Created",
+		"Updated",
 	}
 	if err := writer.Write(header); err != nil {
 		check(w, r, err)
@@ -642,6 +1415,15 @@ func (c *Controller) meetingsExport(w http.ResponseWriter, r *http.Request) {
 		if quorum == nil {
 			quorum = &models.Quorum{}
 		}
+		quorumReached := fmt.Sprintf("%t", quorum.Reached())
+		quorumPercent := fmt.Sprintf("%.2f", quorum.Percent())
+		attendingVoting := fmt.Sprintf("%d", quorum.AttendingVoting)
+		totalVoters := fmt.Sprintf("%d", quorum.Voting)
+		// Gatherings have no quorum.
+		if meetingData.IsGathering() {
+			quorumReached = "no quorum (gathering)"
+			quorumPercent, attendingVoting, totalVoters = "", "", ""
+		}
 
 		// Convert Status to string
 		var status string
@@ -662,20 +1444,25 @@ func (c *Controller) meetingsExport(w http.ResponseWriter, r *http.Request) {
 		}
 
 		var attendeesList []string
-		for nickname, voting := range meetingData.Attendees {
+		for nickname, attendance := range meetingData.Attendees {
 			status := "non-voting"
-			if voting {
+			if attendance.VotingAllowed {
 				status = "voting"
 			}
+			if attendance.Presence == models.PresentNonVoting {
+				status += ":" + attendance.Presence.String()
+			}
 			attendeesList = append(attendeesList, fmt.Sprintf("%s:%s", nickname, status))
 		}
 		// Convert to String to write to CSV
+		slices.Sort(attendeesList)
 		attendeesString := strings.Join(attendeesList, ",")
 
-		// All users except those who attended to get a list of all non-Attendees
+		// All expected users except those who attended to get a list of all non-Attendees
 		var nonAttendeesList []string
 		for _, user := range overview.Users {
-			if _, attended := meetingData.Attendees[user.Nickname]; !attended {
+			if _, attended := meetingData.Attendees[user.Nickname]; !attended &&
+				meetingData.Expected.Includes(user.Nickname) {
 				nonAttendeesList = append(nonAttendeesList, user.Nickname)
 			}
 		}
@@ -690,12 +1477,14 @@ func (c *Controller) meetingsExport(w http.ResponseWriter, r *http.Request) {
 			status,
 			fmt.Sprintf("%t", meeting.Gathering),
 			description,
-			fmt.Sprintf("%t", quorum.Reached()),
-			fmt.Sprintf("%.2f", quorum.Percent()),
-			fmt.Sprintf("%d", quorum.AttendingVoting),
-			fmt.Sprintf("%d", quorum.Voting),
+			quorumReached,
+			quorumPercent,
+			attendingVoting,
+			totalVoters,
 			attendeesString,
 			nonAttendeesString,
+			formatOptionalTime(meeting.CreatedAt),
+			formatOptionalTime(meeting.UpdatedAt),
 		}
 		// and write it to a file
 		if err := writer.Write(data); err != nil {
@@ -703,4 +1492,337 @@ func (c *Controller) meetingsExport(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 	}
+	writer.Flush()
+	if !check(w, r, writer.Error()) {
+		return
+	}
+
+	// The ETag covers changes which are not reflected by the modification time.
+	hash := sha256.Sum256(buf.Bytes())
+	w.Header().Set("ETag", `"`+hex.EncodeToString(hash[:16])+`"`)
+	var modTime time.Time
+	if lastModified != nil {
+		modTime = *lastModified
+	}
+	http.ServeContent(w, r, "", modTime, bytes.NewReader(buf.Bytes()))
+}
+
+// attendanceLabel describes the attendance of a member in the meeting export.
+func attendanceLabel(ma *models.MemberAttendance) string {
+	switch {
+	case ma.Attendance.Presence == models.PresentVoting:
+		return "present"
+	case ma.Attendance.Presence == models.PresentNonVoting:
+		return "present (not voting)"
+	case !ma.Expected:
+		return "not expected"
+	case ma.Excused:
+		return "excused"
+	default:
+		return "absent"
+	}
+}
+
+func (c *Controller) meetingExport(w http.ResponseWriter, r *http.Request) {
+	var (
+		meetingID, err1   = misc.Atoi64(r.FormValue("meeting"))
+		committeeID, err2 = misc.Atoi64(r.FormValue("committee"))
+		ctx               = r.Context()
+	)
+	if !checkParam(w, err1, err2) {
+		return
+	}
+	meeting, err := models.LoadMeeting(ctx, c.db, meetingID, committeeID)
+	if !check(w, r, err) {
+		return
+	}
+	if meeting == nil {
+		http.NotFound(w, r)
+		return
+	}
+	attendance, err := models.LoadMeetingAttendance(ctx, c.db, meetingID, committeeID)
+	if !check(w, r, err) {
+		return
+	}
+
+	filename := fmt.Sprintf("meeting_%d_%s.csv",
+		meetingID, meeting.StartTime.UTC().Format("2006-01-02"))
+	w.Header().Set("Content-Type", "text/csv")
+	w.Header().Set("Content-Disposition", "attachment;filename="+filename)
+
+	writer := csv.NewWriter(w)
+	header := []string{
+		"Nickname",
+		"Firstname",
+		"Lastname",
+		"Member Status",
+		"Attendance",
+		"Voting Allowed",
+	}
+	if err := writer.Write(header); err != nil {
+		check(w, r, err)
+		return
+	}
+	for _, ma := range attendance {
+		if err := writer.Write([]string{
+			ma.User.Nickname,
+			misc.EmptyString(ma.User.Firstname),
+			misc.EmptyString(ma.User.Lastname),
+			ma.Status.String(),
+			attendanceLabel(ma),
+			fmt.Sprintf("%t", ma.Attendance.VotingAllowed),
+		}); err != nil {
+			check(w, r, err)
+			return
+		}
+	}
+	writer.Flush()
+	check(w, r, writer.Error())
+}
+
+func (c *Controller) memberHistoryExport(w http.ResponseWriter, r *http.Request) {
+	var (
+		committeeID, err = misc.Atoi64(r.FormValue("committee"))
+		ctx              = r.Context()
+	)
+	if !checkParam(w, err) {
+		return
+	}
+	histories, err := models.LoadUsersHistories(ctx, c.db, committeeID)
+	if !check(w, r, err) {
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/csv")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment;filename=member_history_%d.csv", committeeID))
+
+	writer := csv.NewWriter(w)
+	rows := [][]string{
+		{"Computed At", time.Now().UTC().Format("2006-01-02 15:04:05")},
+		{"Nickname", "Status", "Since"},
+	}
+	// The entries of each user are already ordered by time.
+	for _, nickname := range slices.Sorted(maps.Keys(histories)) {
+		for _, entry := range histories[nickname] {
+			rows = append(rows, []string{
+				nickname,
+				entry.Status.String(),
+				entry.Since.UTC().Format("2006-01-02 15:04:05"),
+			})
+		}
+	}
+	check(w, r, writer.WriteAll(rows))
+}
+
+func (c *Controller) memberStreaksExport(w http.ResponseWriter, r *http.Request) {
+	var (
+		committeeID, err = misc.Atoi64(r.FormValue("committee"))
+		ctx              = r.Context()
+	)
+	if !checkParam(w, err) {
+		return
+	}
+	atRisk, upgrading, err := models.MemberStreaks(ctx, c.db, committeeID)
+	if !check(w, r, err) {
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/csv")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment;filename=member_streaks_%d.csv", committeeID))
+
+	writer := csv.NewWriter(w)
+	rows := [][]string{
+		{"Computed At", time.Now().UTC().Format("2006-01-02 15:04:05")},
+		{"Nickname", "Firstname", "Lastname", "Track", "Meeting", "Meeting Start"},
+	}
+	for _, track := range []struct {
+		name    string
+		streaks []*models.MemberStreak
+	}{
+		{"downgrade", atRisk},
+		{"upgrade", upgrading},
+	} {
+		for _, streak := range track.streaks {
+			rows = append(rows, []string{
+				streak.User.Nickname,
+				misc.EmptyString(streak.User.Firstname),
+				misc.EmptyString(streak.User.Lastname),
+				track.name,
+				strconv.FormatInt(streak.Meeting.ID, 10),
+				streak.Meeting.StartTime.UTC().Format("2006-01-02 15:04:05"),
+			})
+		}
+	}
+	check(w, r, writer.WriteAll(rows))
+}
+
+// uploadFormSlack is the space left for other form fields
+// in addition to the maximum upload size of a document.
+const uploadFormSlack = 64 * 1024
+
+// uploadMaxMemory is the part of an upload which is kept in memory.
+// The rest is stored in temporary files.
+const uploadMaxMemory = 1 << 20
+
+// uploadErrorKey is the context key of the error of parsing an upload.
+type uploadErrorKey struct{}
+
+// limitUpload limits the size of the request body of uploads.
+// The multipart form is parsed before the middlewares access its
+// fields. A failure like a too large body is stored in the context
+// of the request and reported by [uploadedFile].
+func (c *Controller) limitUpload(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		r.Body = http.MaxBytesReader(w, r.Body, c.cfg.Documents.MaxUploadSize+uploadFormSlack)
+		if err := r.ParseMultipartForm(uploadMaxMemory); err != nil {
+			r = r.WithContext(context.WithValue(r.Context(), uploadErrorKey{}, err))
+		}
+		next(w, r)
+	}
+}
+
+// uploadedFile returns the uploaded file of the form field with the
+// given name. If parsing the upload failed in [limitUpload] the
+// error is returned.
+func uploadedFile(r *http.Request, name string) (multipart.File, *multipart.FileHeader, error) {
+	if err, ok := r.Context().Value(uploadErrorKey{}).(error); ok {
+		return nil, nil, err
+	}
+	return r.FormFile(name)
+}
+
+// sniffContentType determines the content type of an uploaded file
+// from its first bytes. The declared content type is ignored unless
+// the content is plain text. Then a declared text type like
+// text/markdown is kept as these types cannot be detected.
+// The file is rewound afterwards.
+func sniffContentType(file io.ReadSeeker, declared string) (string, error) {
+	buf := make([]byte, 512)
+	n, err := io.ReadFull(file, buf)
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return "", err
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return "", err
+	}
+	sniffed, _, err := mime.ParseMediaType(http.DetectContentType(buf[:n]))
+	if err != nil {
+		return "", err
+	}
+	if sniffed == "text/plain" {
+		if declared, _, err := mime.ParseMediaType(declared); err == nil &&
+			strings.HasPrefix(declared, "text/") {
+			return declared, nil
+		}
+	}
+	return sniffed, nil
+}
+
+// documentPath returns the path where the content of a meeting document is stored.
+func (c *Controller) documentPath(meetingID, documentID int64) string {
+	return filepath.Join(
+		c.cfg.Documents.Directory,
+		strconv.FormatInt(meetingID, 10),
+		strconv.FormatInt(documentID, 10))
+}
+
+func (c *Controller) meetingDocumentStore(w http.ResponseWriter, r *http.Request) {
+	var (
+		meetingID, err1   = misc.Atoi64(r.FormValue("meeting"))
+		committeeID, err2 = misc.Atoi64(r.FormValue("committee"))
+		ctx               = r.Context()
+	)
+	if !checkParam(w, err1, err2) {
+		return
+	}
+	meeting, err := models.LoadMeeting(ctx, c.db, meetingID, committeeID)
+	if !check(w, r, err) {
+		return
+	}
+	if meeting == nil {
+		c.chair(w, r)
+		return
+	}
+	file, header, err := uploadedFile(r, "document")
+	var tooLarge *http.MaxBytesError
+	switch {
+	case errors.As(err, &tooLarge):
+		c.meetingStatusError(w, r, "error.document_too_large")
+		return
+	case errors.Is(err, http.ErrMissingFile):
+		c.meetingStatusError(w, r, "error.no_document")
+		return
+	case err != nil:
+		c.meetingStatusError(w, r, "error.upload_failed")
+		return
+	}
+	defer file.Close()
+	if header.Size > c.cfg.Documents.MaxUploadSize {
+		c.meetingStatusError(w, r, "error.document_too_large")
+		return
+	}
+	contentType, err := sniffContentType(file, header.Header.Get("Content-Type"))
+	if !check(w, r, err) {
+		return
+	}
+	if !slices.Contains(c.cfg.Documents.ContentTypes, contentType) {
+		c.meetingStatusError(w, r, "error.content_type_not_allowed", contentType)
+		return
+	}
+	user := auth.UserFromContext(ctx)
+	doc := models.MeetingDocument{
+		MeetingID:   meetingID,
+		Filename:    filepath.Base(header.Filename),
+		ContentType: contentType,
+		UploadedBy:  &user.Nickname,
+	}
+	store := func(documentID int64) error {
+		path := c.documentPath(meetingID, documentID)
+		if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+			return err
+		}
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o640)
+		if err != nil {
+			return err
+		}
+		if _, err := io.Copy(f, file); err != nil {
+			f.Close()
+			os.Remove(path)
+			return err
+		}
+		return f.Close()
+	}
+	if !check(w, r, models.AddMeetingDocument(ctx, c.db, &doc, store)) {
+		return
+	}
+	c.meetingStatus(w, r)
+}
+
+func (c *Controller) meetingDocument(w http.ResponseWriter, r *http.Request) {
+	var (
+		documentID, err1  = misc.Atoi64(r.FormValue("document"))
+		meetingID, err2   = misc.Atoi64(r.FormValue("meeting"))
+		committeeID, err3 = misc.Atoi64(r.FormValue("committee"))
+		ctx               = r.Context()
+	)
+	if !checkParam(w, err1, err2, err3) {
+		return
+	}
+	doc, err := models.LoadMeetingDocument(ctx, c.db, documentID, meetingID, committeeID)
+	if !check(w, r, err) {
+		return
+	}
+	if doc == nil {
+		http.NotFound(w, r)
+		return
+	}
+	f, err := os.Open(c.documentPath(meetingID, documentID))
+	if !check(w, r, err) {
+		return
+	}
+	defer f.Close()
+	w.Header().Set("Content-Type", doc.ContentType)
+	w.Header().Set("Content-Disposition",
+		mime.FormatMediaType("attachment", map[string]string{"filename": doc.Filename}))
+	http.ServeContent(w, r, doc.Filename, doc.Uploaded, f)
 }
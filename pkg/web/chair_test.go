@@ -0,0 +1,497 @@
+// This file is Free Software under the Apache-2.0 License
+// without warranty, see README.md and LICENSE for details.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileCopyrightText: 2025 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2025 Intevation GmbH <https://intevation.de>
+
+package web
+
+import (
+	"bytes"
+	"errors"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/csaf-auxiliary/oasis-quorum-calculator/pkg/config"
+	"github.com/csaf-auxiliary/oasis-quorum-calculator/pkg/models"
+)
+
+// uploadRequest creates a multipart request with a committee field
+// and a document of the given size.
+func uploadRequest(t *testing.T, size int) *http.Request {
+	t.Helper()
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	if err := mw.WriteField("committee", "1"); err != nil {
+		t.Fatal(err)
+	}
+	fw, err := mw.CreateFormFile("document", "doc.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fw.Write(bytes.Repeat([]byte("x"), size))
+	if err := mw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	r := httptest.NewRequest(http.MethodPost, "/meeting_document_store?meeting=2", &body)
+	r.Header.Set("Content-Type", mw.FormDataContentType())
+	return r
+}
+
+func TestLimitUpload(t *testing.T) {
+	c := &Controller{cfg: &config.Config{
+		Documents: config.Documents{MaxUploadSize: 1000},
+	}}
+	for _, tc := range []struct {
+		name     string
+		size     int
+		tooLarge bool
+	}{
+		{"small", 100, false},
+		{"oversize", 2 * uploadFormSlack, true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var called bool
+			handler := c.limitUpload(func(_ http.ResponseWriter, r *http.Request) {
+				called = true
+				// Fields of the query are available in any case.
+				if got := r.FormValue("meeting"); got != "2" {
+					t.Errorf("meeting = %q, want \"2\"", got)
+				}
+				file, header, err := uploadedFile(r, "document")
+				var maxBytes *http.MaxBytesError
+				if tc.tooLarge {
+					if !errors.As(err, &maxBytes) {
+						t.Fatalf("got error %v, want *http.MaxBytesError", err)
+					}
+					return
+				}
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				defer file.Close()
+				if header.Size != int64(tc.size) {
+					t.Errorf("size = %d, want %d", header.Size, tc.size)
+				}
+				if got := r.FormValue("committee"); got != "1" {
+					t.Errorf("committee = %q, want \"1\"", got)
+				}
+			})
+			handler(httptest.NewRecorder(), uploadRequest(t, tc.size))
+			if !called {
+				t.Fatal("handler not called")
+			}
+		})
+	}
+}
+
+func TestSniffContentType(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		content  string
+		declared string
+		want     string
+	}{
+		{"pdf", "%PDF-1.7\n...", "application/pdf", "application/pdf"},
+		{"pdf declared as text", "%PDF-1.7\n...", "text/plain", "application/pdf"},
+		{"markdown", "# Agenda\n\n- item\n", "text/markdown", "text/markdown"},
+		{"text without type", "minutes", "", "text/plain"},
+		{"text declared as pdf", "minutes", "application/pdf", "text/plain"},
+		{"html declared as text", "<html><body>hi</body></html>", "text/plain", "text/html"},
+		{"html declared as markdown", "<script>alert(1)</script>", "text/markdown", "text/html"},
+		{"binary declared as pdf", "\x00\x01\x02\x03", "application/pdf", "application/octet-stream"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			file := strings.NewReader(tc.content)
+			got, err := sniffContentType(file, tc.declared)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tc.want {
+				t.Errorf("got %q, want %q", got, tc.want)
+			}
+			if file.Len() != len(tc.content) {
+				t.Error("file not rewound")
+			}
+		})
+	}
+}
+
+func TestParsePresenceAction(t *testing.T) {
+	for _, tc := range []struct {
+		action string
+		want   models.Presence
+		fail   bool
+	}{
+		{action: "attend", want: models.PresentVoting},
+		{action: "novote", want: models.PresentNonVoting},
+		{action: "absent", want: models.Absent},
+		{action: "Mark as Attending but Not Voting", fail: true},
+		{action: "not voting", fail: true},
+		{action: "Attend", fail: true},
+		{action: "", fail: true},
+	} {
+		got, err := parsePresenceAction(tc.action)
+		switch {
+		case tc.fail && err == nil:
+			t.Errorf("%q: expected an error", tc.action)
+		case !tc.fail && err != nil:
+			t.Errorf("%q: unexpected error: %v", tc.action, err)
+		case !tc.fail && got != tc.want:
+			t.Errorf("%q: got %v, want %v", tc.action, got, tc.want)
+		}
+	}
+}
+
+func TestParseStartTime(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skipf("timezone data not available: %v", err)
+	}
+	for _, tc := range []struct {
+		input    string
+		location *time.Location
+		want     string
+	}{
+		{"2025-01-15T10:00", time.UTC, "2025-01-15T10:00:00Z"},
+		{"2025-01-15T10:00", berlin, "2025-01-15T09:00:00Z"},
+		{"2025-07-15T10:00", berlin, "2025-07-15T08:00:00Z"},
+		{"2025-01-01T00:30", berlin, "2024-12-31T23:30:00Z"},
+	} {
+		got, err := parseStartTime(tc.input, tc.location)
+		if err != nil {
+			t.Errorf("%s in %s: %v", tc.input, tc.location, err)
+			continue
+		}
+		if got.Location() != time.UTC {
+			t.Errorf("%s in %s: not in UTC but %s", tc.input, tc.location, got.Location())
+		}
+		if s := got.Format(time.RFC3339); s != tc.want {
+			t.Errorf("%s in %s: got %s, want %s", tc.input, tc.location, s, tc.want)
+		}
+	}
+	for _, input := range []string{"", "2025-01-15", "2025-01-15 10:00", "2025-13-01T10:00"} {
+		if _, err := parseStartTime(input, berlin); err == nil {
+			t.Errorf("%q: expected an error", input)
+		}
+	}
+}
+
+// chairFixture creates a committee chaired by the admin
+// with an upcoming meeting and returns the session of the admin.
+func chairFixture(t *testing.T, srv *testServer) string {
+	t.Helper()
+	srv.exec(t, `INSERT INTO committees (id, name, slug) VALUES (1, 'TC', 'tc')`)
+	srv.exec(t, `INSERT INTO committee_roles (nickname, committees_id, committee_role_id) `+
+		`VALUES ('admin', 1, 0)`)
+	srv.exec(t, `INSERT INTO meetings (id, committees_id, status, start_time, stop_time) `+
+		`VALUES (1, 1, 0, ?, ?)`,
+		time.Now().Add(24*time.Hour).UTC(), time.Now().Add(25*time.Hour).UTC())
+	return srv.login(t, "admin", testPassword)
+}
+
+var confirmToken = regexp.MustCompile(`name="confirm" value="([^"]+)"`)
+
+func TestMeetingsStoreDelete(t *testing.T) {
+	srv := newTestServer(t)
+	sessionID := chairFixture(t, srv)
+
+	form := url.Values{
+		"SESSIONID": {sessionID},
+		"committee": {"1"},
+		"meetings":  {"1"},
+		"delete":    {"delete"},
+	}
+	resp := srv.post(t, "/meetings_store", form)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("confirmation page: %s", resp.Status)
+	}
+	m := confirmToken.FindStringSubmatch(body(t, resp))
+	if m == nil {
+		t.Fatal("confirmation page has no token")
+	}
+
+	form.Set("confirm", m[1])
+	resp = srv.post(t, "/meetings_store", form)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("deletion: %s", resp.Status)
+	}
+	// The chair page is rendered completely.
+	if page := body(t, resp); !strings.HasSuffix(strings.TrimSpace(page), "</html>") {
+		t.Errorf("chair page not rendered completely:\n%s", page)
+	}
+	var n int
+	if err := srv.db.DB.QueryRow(`SELECT count(*) FROM meetings`).Scan(&n); err != nil {
+		t.Fatal(err)
+	}
+	if n != 0 {
+		t.Errorf("got %d meetings, want 0", n)
+	}
+}
+
+func TestChairTransferErrors(t *testing.T) {
+	srv := newTestServer(t)
+	sessionID := chairFixture(t, srv)
+	srv.user(t, "bob")
+	srv.user(t, "carol")
+	srv.exec(t, `INSERT INTO committee_roles (nickname, committees_id, committee_role_id) `+
+		`VALUES ('carol', 1, 1)`)
+
+	for _, tc := range []struct {
+		name     string
+		from, to string
+		language string
+		want     string
+	}{
+		{"not active", "", "bob", "en", "&#34;bob&#34; is no active user of the committee."},
+		{"already chair", "", "admin", "en", "&#34;admin&#34; already is a chair of the committee."},
+		{"not chair", "bob", "carol", "en", "&#34;bob&#34; is not a chair of the committee."},
+		{"german", "", "bob", "de", "&#34;bob&#34; ist kein aktiver Benutzer des Gremiums."},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			form := url.Values{
+				"SESSIONID": {sessionID},
+				"committee": {"1"},
+				"from":      {tc.from},
+				"to":        {tc.to},
+			}
+			req, err := http.NewRequest(http.MethodPost, srv.URL+"/chair_transfer",
+				strings.NewReader(form.Encode()))
+			if err != nil {
+				t.Fatal(err)
+			}
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			req.Header.Set("Accept-Language", tc.language)
+			resp := srv.do(t, req)
+			if resp.StatusCode != http.StatusOK {
+				t.Fatalf("got %s", resp.Status)
+			}
+			if page := body(t, resp); !strings.Contains(page, tc.want) {
+				t.Errorf("message %q not found:\n%s", tc.want, page)
+			}
+		})
+	}
+	// Nothing was transferred.
+	var chair string
+	if err := srv.db.DB.QueryRow(`SELECT nickname FROM committee_roles ` +
+		`WHERE committees_id = 1 AND committee_role_id = 0`).Scan(&chair); err != nil {
+		t.Fatal(err)
+	}
+	if chair != "admin" {
+		t.Errorf("chair is %q, want admin", chair)
+	}
+}
+
+func TestMeetingStatusStoreNotRunning(t *testing.T) {
+	srv := newTestServer(t)
+	sessionID := chairFixture(t, srv)
+	resp := srv.post(t, "/meeting_status_store", url.Values{
+		"SESSIONID": {sessionID},
+		"committee": {"1"},
+		"meeting":   {"1"},
+		"status":    {"concluded"},
+	})
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("got %s", resp.Status)
+	}
+	if want, page := "Only running meetings can be concluded.", body(t, resp); !strings.Contains(page, want) {
+		t.Errorf("message %q not found:\n%s", want, page)
+	}
+	var status models.MeetingStatus
+	if err := srv.db.DB.QueryRow(`SELECT status FROM meetings WHERE id = 1`).Scan(&status); err != nil {
+		t.Fatal(err)
+	}
+	if status != models.MeetingOnHold {
+		t.Errorf("got status %v, want %v", status, models.MeetingOnHold)
+	}
+}
+
+func TestMeetingsExportConditional(t *testing.T) {
+	srv := newTestServer(t)
+	sessionID := chairFixture(t, srv)
+	srv.exec(t, `UPDATE meetings SET updated_at = '2025-01-01 10:00:00+00:00' WHERE id = 1`)
+
+	export := func(header, value string) *http.Response {
+		t.Helper()
+		query := url.Values{"SESSIONID": {sessionID}, "committee": {"1"}}
+		req, err := http.NewRequest(http.MethodGet, srv.URL+"/meetings_export?"+query.Encode(), nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if header != "" {
+			req.Header.Set(header, value)
+		}
+		return srv.do(t, req)
+	}
+	expect := func(resp *http.Response, status int) {
+		t.Helper()
+		if resp.StatusCode != status {
+			t.Fatalf("got %s, want %d", resp.Status, status)
+		}
+	}
+
+	first := export("", "")
+	expect(first, http.StatusOK)
+	lastModified, etag := first.Header.Get("Last-Modified"), first.Header.Get("ETag")
+	if lastModified != "Wed, 01 Jan 2025 10:00:00 GMT" || etag == "" {
+		t.Fatalf("got Last-Modified %q and ETag %q", lastModified, etag)
+	}
+	if csv := body(t, first); !strings.HasPrefix(csv, "Meeting ID,") {
+		t.Fatalf("unexpected export:\n%s", csv)
+	}
+
+	// Unchanged meetings are not sent again.
+	expect(export("If-Modified-Since", lastModified), http.StatusNotModified)
+	expect(export("If-None-Match", etag), http.StatusNotModified)
+	expect(export("If-Modified-Since", "Wed, 01 Jan 2025 09:59:59 GMT"), http.StatusOK)
+
+	// A change of the meeting is sent.
+	srv.exec(t, `UPDATE meetings SET description = 'changed', `+
+		`updated_at = '2025-01-02 10:00:00+00:00' WHERE id = 1`)
+	expect(export("If-Modified-Since", lastModified), http.StatusOK)
+	expect(export("If-None-Match", etag), http.StatusOK)
+}
+
+func TestMeetingStatusStoreNoVoters(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		block   bool
+		voters  bool
+		confirm bool
+		want    string
+		status  models.MeetingStatus
+	}{
+		{"warn", false, false, false, "The committee has no voting members.", models.MeetingOnHold},
+		{"warn confirmed", false, false, true, "", models.MeetingRunning},
+		{"block", true, false, false, "Cannot run meeting:", models.MeetingOnHold},
+		{"block confirmed", true, false, true, "Cannot run meeting:", models.MeetingOnHold},
+		{"voters", true, true, false, "", models.MeetingRunning},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			srv := newTestServer(t)
+			srv.cfg.Meetings.BlockWithoutVoters = tc.block
+			sessionID := chairFixture(t, srv)
+			if tc.voters {
+				srv.user(t, "bob")
+				srv.exec(t, `INSERT INTO committee_roles (nickname, committees_id, committee_role_id) `+
+					`VALUES ('bob', 1, 1)`)
+				srv.exec(t, `INSERT INTO member_history (nickname, committees_id, status, since) `+
+					`VALUES ('bob', 1, 1, '2024-01-01 00:00:00+00:00')`)
+			}
+			form := url.Values{
+				"SESSIONID": {sessionID},
+				"committee": {"1"},
+				"meeting":   {"1"},
+				"status":    {"running"},
+			}
+			if tc.confirm {
+				form.Set("confirm", "true")
+			}
+			resp := srv.post(t, "/meeting_status_store", form)
+			if resp.StatusCode != http.StatusOK {
+				t.Fatalf("got %s", resp.Status)
+			}
+			page := body(t, resp)
+			if tc.want != "" && !strings.Contains(page, tc.want) {
+				t.Errorf("message %q not found:\n%s", tc.want, page)
+			}
+			// Only the warning can be confirmed.
+			if confirmable := strings.Contains(page, `name="confirm"`); confirmable !=
+				(!tc.block && !tc.voters && !tc.confirm) {
+				t.Errorf("confirmation offered: %t", confirmable)
+			}
+			var status models.MeetingStatus
+			if err := srv.db.DB.QueryRow(`SELECT status FROM meetings WHERE id = 1`).Scan(&status); err != nil {
+				t.Fatal(err)
+			}
+			if status != tc.status {
+				t.Errorf("got status %v, want %v", status, tc.status)
+			}
+		})
+	}
+}
+
+func TestMeetingEditStoreModified(t *testing.T) {
+	srv := newTestServer(t)
+	sessionID := chairFixture(t, srv)
+	srv.user(t, "bob")
+	srv.exec(t, `INSERT INTO committee_roles (nickname, committees_id, committee_role_id) `+
+		`VALUES ('bob', 1, 1)`)
+	srv.exec(t, `INSERT INTO committees (id, name, slug) VALUES (2, 'SC', 'sc')`)
+	// Someone else has stored the meeting in the meantime.
+	srv.exec(t, `UPDATE meetings SET description = 'Other', version = version + 1 WHERE id = 1`)
+
+	post := func(description, version, duration string) string {
+		t.Helper()
+		resp := srv.post(t, "/meeting_edit_store", url.Values{
+			"SESSIONID":   {sessionID},
+			"committee":   {"1"},
+			"meeting":     {"1"},
+			"version":     {version},
+			"description": {description},
+			"start_time":  {time.Now().Add(48 * time.Hour).UTC().Format("2006-01-02T15:04")},
+			"duration":    {duration},
+			"timezone":    {"UTC"},
+		})
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("got %s", resp.Status)
+		}
+		return body(t, resp)
+	}
+	store := func(description, version string) string {
+		t.Helper()
+		return post(description, version, "1h")
+	}
+	// Error pages offer to edit the expected attendees and to move the meeting.
+	complete := func(page string) {
+		t.Helper()
+		for _, want := range []string{`id="expected_bob"`, `<option value="2">SC</option>`} {
+			if !strings.Contains(page, want) {
+				t.Errorf("%q not found:\n%s", want, page)
+			}
+		}
+	}
+	description := func() string {
+		t.Helper()
+		var description string
+		if err := srv.db.DB.QueryRow(`SELECT description FROM meetings WHERE id = 1`).Scan(&description); err != nil {
+			t.Fatal(err)
+		}
+		return description
+	}
+
+	const conflict = "This record was modified by someone else"
+	page := store("Stale", "0")
+	if !strings.Contains(page, conflict) {
+		t.Errorf("conflict not reported:\n%s", page)
+	}
+	complete(page)
+	// The current state is shown to be edited again.
+	if !strings.Contains(page, ">Other</textarea>") || !strings.Contains(page, `name="version" value="1"`) {
+		t.Errorf("current state not shown:\n%s", page)
+	}
+	if got := description(); got != "Other" {
+		t.Errorf("stale update stored %q", got)
+	}
+
+	page = post("Invalid", "1", "-1h")
+	if !strings.Contains(page, "Duration is invalid.") {
+		t.Errorf("invalid duration not reported:\n%s", page)
+	}
+	complete(page)
+
+	if page := store("Current", "1"); strings.Contains(page, conflict) {
+		t.Errorf("conflict reported for current version:\n%s", page)
+	}
+	if got := description(); got != "Current" {
+		t.Errorf("got description %q, want %q", got, "Current")
+	}
+}
@@ -9,9 +9,12 @@
 package web
 
 import (
-	"fmt"
+	"encoding/json"
+	"errors"
 	"net/http"
+	"net/url"
 	"slices"
+	"strconv"
 	"strings"
 
 	"github.com/csaf-auxiliary/oasis-quorum-calculator/pkg/auth"
@@ -20,6 +23,15 @@ import (
 )
 
 func (c *Controller) committeeEdit(w http.ResponseWriter, r *http.Request) {
+	c.committeeEditError(w, r, "")
+}
+
+func (c *Controller) committeeEditError(
+	w http.ResponseWriter,
+	r *http.Request,
+	errKey string,
+	errArgs ...any,
+) {
 	id, err := misc.Atoi64(r.FormValue("id"))
 	if !checkParam(w, err) {
 		return
@@ -38,12 +50,34 @@ func (c *Controller) committeeEdit(w http.ResponseWriter, r *http.Request) {
 		"User":      auth.UserFromContext(ctx),
 		"Committee": committee,
 	}
-	check(w, r, c.tmpls.ExecuteTemplate(w, "committee_edit.tmpl", data))
+	if errKey != "" {
+		data.error(errKey, errArgs...)
+	}
+	c.committeeEditRender(w, r, committee, data)
+}
+
+// committeeEditRender renders the edit page of a committee
+// including the users needed to transfer the chair.
+func (c *Controller) committeeEditRender(
+	w http.ResponseWriter,
+	r *http.Request,
+	committee *models.Committee,
+	data templateData,
+) {
+	users, err := models.LoadCommitteeUsers(r.Context(), c.db, committee.ID, nil)
+	if !check(w, r, err) {
+		return
+	}
+	data["Users"] = users
+	check(w, r, c.templates(r).ExecuteTemplate(w, "committee_edit.tmpl", data))
 }
 
 func (c *Controller) committeeEditStore(w http.ResponseWriter, r *http.Request) {
-	id, err := misc.Atoi64(r.FormValue("id"))
-	if !checkParam(w, err) {
+	var (
+		id, err1      = misc.Atoi64(r.FormValue("id"))
+		version, err2 = misc.Atoi64(r.FormValue("version"))
+	)
+	if !checkParam(w, err1, err2) {
 		return
 	}
 	ctx := r.Context()
@@ -55,6 +89,8 @@ func (c *Controller) committeeEditStore(w http.ResponseWriter, r *http.Request)
 		c.committees(w, r)
 		return
 	}
+	// Only accept changes based on the current state.
+	committee.Version = version
 	data := templateData{
 		"Session":   auth.SessionFromContext(ctx),
 		"User":      auth.UserFromContext(ctx),
@@ -62,61 +98,158 @@ func (c *Controller) committeeEditStore(w http.ResponseWriter, r *http.Request)
 	}
 	var (
 		name        = strings.TrimSpace(r.FormValue("name"))
-		description = strings.TrimSpace(r.FormValue("description"))
+		slug        = strings.TrimSpace(r.FormValue("slug"))
+		description = misc.SanitizeText(r.FormValue("description"))
+		reminders   = r.FormValue("attendance_reminders") == "true"
 		changed     bool
 	)
-	if name == "" {
-		data.error("Missing committee name.")
-	} else {
+	switch {
+	case name == "":
+		data.error("error.committee_name_missing")
+	case slug == "":
+		data.error("error.slug_missing")
+	case misc.Slugify(slug) != slug:
+		data.error("error.slug_invalid")
+	case textTooLong(&description, maxDescriptionLength):
+		data.error("error.description_too_long", maxDescriptionLength)
+	default:
 		if name != committee.Name {
 			committee.Name = name
 			changed = true
 		}
+		if slug != committee.Slug {
+			committee.Slug = slug
+			changed = true
+		}
 		misc.NilChanger(&changed, &committee.Description, description)
+		if reminders != committee.AttendanceReminders {
+			committee.AttendanceReminders = reminders
+			changed = true
+		}
 	}
-	if changed && !check(w, r, committee.Store(ctx, c.db)) {
+	if changed {
+		switch err := committee.Store(ctx, c.db); {
+		case errors.Is(err, models.ErrSlugTaken):
+			data.error("error.slug_taken", slug)
+		case errors.Is(err, models.ErrModified):
+			data.error("error.modified_concurrently")
+			if committee, err = models.LoadCommittee(ctx, c.db, id); !check(w, r, err) {
+				return
+			}
+			if committee == nil {
+				c.committees(w, r)
+				return
+			}
+			data["Committee"] = committee
+		case !check(w, r, err):
+			return
+		}
+	}
+	c.committeeEditRender(w, r, committee, data)
+}
+
+func (c *Controller) committeeArchive(w http.ResponseWriter, r *http.Request) {
+	id, err := misc.Atoi64(r.FormValue("id"))
+	if !checkParam(w, err) {
 		return
 	}
-	check(w, r, c.tmpls.ExecuteTemplate(w, "committee_edit.tmpl", data))
+	archive := models.ArchiveCommittee
+	if r.FormValue("archived") == "false" {
+		archive = models.UnarchiveCommittee
+	}
+	if !check(w, r, archive(r.Context(), c.db, id)) {
+		return
+	}
+	c.committeeEdit(w, r)
 }
 
 func (c *Controller) committees(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
-	committees, err := models.LoadCommittees(ctx, c.db)
+	var (
+		ctx             = r.Context()
+		query           = strings.TrimSpace(r.FormValue("q"))
+		includeArchived = r.FormValue("include_archived") != ""
+		committees      []*models.Committee
+		err             error
+	)
+	if query != "" {
+		committees, err = models.SearchCommittees(ctx, c.db, query, includeArchived)
+	} else {
+		committees, err = models.LoadCommitteesFiltered(ctx, c.db, "", includeArchived)
+	}
 	if !check(w, r, err) {
 		return
 	}
 	data := templateData{
-		"Session":    auth.SessionFromContext(ctx),
-		"User":       auth.UserFromContext(ctx),
-		"Committees": committees,
+		"Session":         auth.SessionFromContext(ctx),
+		"User":            auth.UserFromContext(ctx),
+		"Committees":      committees,
+		"Query":           query,
+		"IncludeArchived": includeArchived,
 	}
-	check(w, r, c.tmpls.ExecuteTemplate(w, "committees.tmpl", data))
+	check(w, r, c.templates(r).ExecuteTemplate(w, "committees.tmpl", data))
 }
 
 func (c *Controller) committeesStore(w http.ResponseWriter, r *http.Request) {
 	if r.FormValue("delete") != "" {
-		ids := misc.ParseSeq(slices.Values(r.Form["committees"]), misc.Atoi64)
-		if !check(w, r, models.DeleteCommitteesByID(r.Context(), c.db, ids)) {
+		ctx := r.Context()
+		ids := slices.Collect(misc.ParseSeq(slices.Values(r.Form["committees"]), misc.Atoi64))
+		committees, err := models.LoadCommitteesFiltered(ctx, c.db, "", true)
+		if !check(w, r, err) {
 			return
 		}
+		d := &deletion{
+			Action: "/committees_store",
+			Back:   "/committees?SESSIONID=" + url.QueryEscape(auth.SessionFromContext(ctx).ID()),
+			Submit: "delete",
+			Verb:   "deleted",
+			Field:  "committees",
+		}
+		for _, committee := range committees {
+			if slices.Contains(ids, committee.ID) {
+				d.Entries = append(d.Entries, &deletionEntry{
+					Value: strconv.FormatInt(committee.ID, 10),
+					Label: committee.Name,
+				})
+			}
+		}
+		if len(d.Entries) > 0 {
+			if !c.confirmDeletion(w, r, d) {
+				return
+			}
+			ids := misc.ParseSeq(slices.Values(d.Values()), misc.Atoi64)
+			if !check(w, r, models.DeleteCommitteesByID(ctx, c.db, ids)) {
+				return
+			}
+		}
 	}
 	c.committees(w, r)
 }
 
 func (c *Controller) committeeCreate(w http.ResponseWriter, r *http.Request) {
+	c.committeeCreateError(w, r, "")
+}
+
+func (c *Controller) committeeCreateError(
+	w http.ResponseWriter,
+	r *http.Request,
+	errKey string,
+	errArgs ...any,
+) {
 	ctx := r.Context()
 	data := templateData{
 		"Session": auth.SessionFromContext(ctx),
 		"User":    auth.UserFromContext(ctx),
 	}
-	check(w, r, c.tmpls.ExecuteTemplate(w, "committee_create.tmpl", data))
+	if errKey != "" {
+		data.error(errKey, errArgs...)
+	}
+	check(w, r, c.templates(r).ExecuteTemplate(w, "committee_create.tmpl", data))
 }
 
 func (c *Controller) committeeStore(w http.ResponseWriter, r *http.Request) {
 	var (
 		name        = strings.TrimSpace(r.FormValue("name"))
-		description = misc.NilString(strings.TrimSpace(r.FormValue("description")))
+		description = misc.NilString(misc.SanitizeText(r.FormValue("description")))
 		ctx         = r.Context()
 	)
 	data := templateData{
@@ -125,9 +258,12 @@ func (c *Controller) committeeStore(w http.ResponseWriter, r *http.Request) {
 		"Session":     auth.SessionFromContext(ctx),
 		"User":        auth.UserFromContext(ctx),
 	}
-	if name == "" {
-		data.error("Name is missing.")
-	} else {
+	switch {
+	case name == "":
+		data.error("error.name_missing")
+	case textTooLong(description, maxDescriptionLength):
+		data.error("error.description_too_long", maxDescriptionLength)
+	default:
 		committee, err := models.CreateCommittee(ctx, c.db, name, description)
 		if !check(w, r, err) {
 			return
@@ -137,7 +273,73 @@ func (c *Controller) committeeStore(w http.ResponseWriter, r *http.Request) {
 			c.committees(w, r)
 			return
 		}
-		data.error(fmt.Sprintf("Committee %q already exists.", name))
+		data.error("error.committee_exists", name)
+	}
+	check(w, r, c.templates(r).ExecuteTemplate(w, "committee_create.tmpl", data))
+}
+
+func (c *Controller) committeeExport(w http.ResponseWriter, r *http.Request) {
+	id, err := misc.Atoi64(r.FormValue("id"))
+	if !checkParam(w, err) {
+		return
 	}
-	check(w, r, c.tmpls.ExecuteTemplate(w, "committee_create.tmpl", data))
+	export, err := models.ExportCommittee(r.Context(), c.db, id)
+	if !check(w, r, err) {
+		return
+	}
+	if export == nil {
+		c.committees(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Disposition", "attachment;filename="+export.Slug+".json")
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	check(w, r, enc.Encode(export))
+}
+
+func (c *Controller) committeeImport(w http.ResponseWriter, r *http.Request) {
+	file, _, err := uploadedFile(r, "committee")
+	var tooLarge *http.MaxBytesError
+	switch {
+	case errors.As(err, &tooLarge):
+		c.committeeCreateError(w, r, "error.document_too_large")
+		return
+	case errors.Is(err, http.ErrMissingFile):
+		c.committeeCreateError(w, r, "error.no_document")
+		return
+	case err != nil:
+		c.committeeCreateError(w, r, "error.upload_failed")
+		return
+	}
+	defer file.Close()
+	_, err = models.ImportCommitteeJSON(r.Context(), c.db, file)
+	if errors.Is(err, models.ErrInvalidImport) {
+		c.committeeCreateError(w, r, "error.import_invalid", err.Error())
+		return
+	}
+	if !check(w, r, err) {
+		return
+	}
+	c.committees(w, r)
+}
+
+func (c *Controller) runningMeetings(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	meetings, err := models.LoadRunningMeetings(ctx, c.db)
+	if !check(w, r, err) {
+		return
+	}
+	data := templateData{
+		"Session":  auth.SessionFromContext(ctx),
+		"User":     auth.UserFromContext(ctx),
+		"Meetings": meetings,
+	}
+	check(w, r, c.templates(r).ExecuteTemplate(w, "running_meetings.tmpl", data))
+}
+
+func (c *Controller) dbStats(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	check(w, r, json.NewEncoder(w).Encode(c.db.Stats()))
 }
@@ -0,0 +1,128 @@
+// This file is Free Software under the Apache-2.0 License
+// without warranty, see README.md and LICENSE for details.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileCopyrightText: 2025 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2025 Intevation GmbH <https://intevation.de>
+
+package web
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/csaf-auxiliary/oasis-quorum-calculator/pkg/misc"
+)
+
+func TestCommitteeEditStoreModified(t *testing.T) {
+	srv := newTestServer(t)
+	sessionID := srv.login(t, "admin", testPassword)
+	srv.exec(t, `INSERT INTO committees (id, name, slug) VALUES (1, 'TC', 'tc')`)
+	// Someone else has stored the committee in the meantime.
+	srv.exec(t, `UPDATE committees SET name = 'Other', version = version + 1 WHERE id = 1`)
+
+	store := func(name, version string) string {
+		t.Helper()
+		resp := srv.post(t, "/committee_edit_store", url.Values{
+			"SESSIONID": {sessionID},
+			"id":        {"1"},
+			"version":   {version},
+			"name":      {name},
+			"slug":      {"tc"},
+		})
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("got %s", resp.Status)
+		}
+		return body(t, resp)
+	}
+	name := func() string {
+		t.Helper()
+		var name string
+		if err := srv.db.DB.QueryRow(`SELECT name FROM committees WHERE id = 1`).Scan(&name); err != nil {
+			t.Fatal(err)
+		}
+		return name
+	}
+
+	const conflict = "This record was modified by someone else"
+	page := store("Stale", "0")
+	if !strings.Contains(page, conflict) {
+		t.Errorf("conflict not reported:\n%s", page)
+	}
+	// The current state is shown to be edited again.
+	if !strings.Contains(page, `value="Other"`) || !strings.Contains(page, `name="version" value="1"`) {
+		t.Errorf("current state not shown:\n%s", page)
+	}
+	if got := name(); got != "Other" {
+		t.Errorf("stale update stored %q", got)
+	}
+
+	if page := store("Current", "1"); strings.Contains(page, conflict) {
+		t.Errorf("conflict reported for current version:\n%s", page)
+	}
+	if got := name(); got != "Current" {
+		t.Errorf("got name %q, want %q", got, "Current")
+	}
+}
+
+func TestCommitteeStoreDescriptionLength(t *testing.T) {
+	srv := newTestServer(t)
+	sessionID := srv.login(t, "admin", testPassword)
+
+	store := func(name, description string) string {
+		t.Helper()
+		resp := srv.post(t, "/committee_store", url.Values{
+			"SESSIONID":   {sessionID},
+			"name":        {name},
+			"description": {description},
+		})
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("got %s", resp.Status)
+		}
+		return body(t, resp)
+	}
+	description := func(name string) (string, bool) {
+		t.Helper()
+		var description string
+		switch err := srv.db.DB.QueryRow(
+			`SELECT description FROM committees WHERE name = ?`, name,
+		).Scan(&description); {
+		case errors.Is(err, sql.ErrNoRows):
+			return "", false
+		case err != nil:
+			t.Fatal(err)
+		}
+		return description, true
+	}
+
+	const tooLong = "Description must not exceed 2000 characters."
+	// Multi-byte characters are counted as one.
+	atLimit := strings.Repeat("ü", maxDescriptionLength)
+	if page := store("At limit", atLimit); strings.Contains(page, tooLong) {
+		t.Errorf("description at limit rejected")
+	}
+	if got, ok := description("At limit"); !ok || got != atLimit {
+		t.Errorf("description at limit not stored")
+	}
+
+	if page := store("Over limit", atLimit+"a"); !strings.Contains(page, tooLong) {
+		t.Errorf("over-length description not reported")
+	}
+	if _, ok := description("Over limit"); ok {
+		t.Errorf("over-length description stored")
+	}
+
+	// Control characters are stripped before storing and do not count.
+	const sanitized = "Welcome\nto the TC"
+	rest := strings.Repeat("ü", maxDescriptionLength-len(sanitized))
+	store("Sanitized", " Wel\x00come\r\nto the\x07 TC"+rest+"\n")
+	got, _ := description("Sanitized")
+	if want := sanitized + rest; got != want {
+		t.Errorf("got description %q, want %q", misc.Shorten(&got), misc.Shorten(&want))
+	}
+}
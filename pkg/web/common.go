@@ -9,18 +9,60 @@
 package web
 
 import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
 	"errors"
 	"fmt"
 	"log/slog"
 	"net/http"
 	"regexp"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
+	"unicode/utf8"
 
+	"github.com/csaf-auxiliary/oasis-quorum-calculator/pkg/auth"
 	"github.com/csaf-auxiliary/oasis-quorum-calculator/pkg/misc"
+	"github.com/csaf-auxiliary/oasis-quorum-calculator/pkg/models"
 )
 
+// Limits of the texts entered by users.
+const (
+	maxDescriptionLength = 2000
+	maxAgendaLength      = 10000
+)
+
+// textTooLong returns true if the optional text
+// is longer than the given number of characters.
+func textTooLong(text *string, limit int) bool {
+	return text != nil && utf8.RuneCountInString(*text) > limit
+}
+
+// highlightMeetings returns the running and the next
+// upcoming meeting of each committee.
+func highlightMeetings(
+	meetings models.Meetings,
+	now time.Time,
+) (live, next map[int64]*models.Meeting) {
+	byCommittee := misc.GroupBy(slices.Values(meetings), func(m *models.Meeting) int64 {
+		return m.CommitteeID
+	})
+	live = map[int64]*models.Meeting{}
+	next = map[int64]*models.Meeting{}
+	for id, group := range byCommittee {
+		ms := models.Meetings(group)
+		if m := ms.CurrentlyRunning(); m != nil {
+			live[id] = m
+		}
+		if m := ms.NextAfter(now); m != nil && m != live[id] {
+			next[id] = m
+		}
+	}
+	return live, next
+}
+
 // datetimeHoursMinutes rounds the duration to minutes
 // and returns a value suitable for datetime attributes.
 func datetimeHoursMinutes(d time.Duration) string {
@@ -47,6 +89,37 @@ func hoursMinutes(d time.Duration) string {
 	return b.String()
 }
 
+// daysHoursMinutes rounds the duration to minutes and returns
+// it in the form accepted by [parseDuration], e.g. "1d 12h".
+// Components which are zero are left out.
+func daysHoursMinutes(d time.Duration) string {
+	d = d.Round(time.Minute)
+	days := int(d / (24 * time.Hour))
+	hours := int(d.Hours()) % 24
+	minutes := int(d.Minutes()) % 60
+	var parts []string
+	if days != 0 {
+		parts = append(parts, strconv.Itoa(days)+"d")
+	}
+	if hours != 0 {
+		parts = append(parts, strconv.Itoa(hours)+"h")
+	}
+	if minutes != 0 || len(parts) == 0 {
+		parts = append(parts, strconv.Itoa(minutes)+"m")
+	}
+	return strings.Join(parts, " ")
+}
+
+// inTZ converts the given time into the timezone with the given name.
+// Falls back to UTC if the timezone is unknown.
+func inTZ(t time.Time, tzName string) time.Time {
+	location, err := time.LoadLocation(tzName)
+	if err != nil {
+		location = time.UTC
+	}
+	return t.In(location)
+}
+
 // args is used in templates to construct maps of key/value pairs.
 func args(args ...any) (any, error) {
 	n := len(args)
@@ -61,22 +134,30 @@ func args(args ...any) (any, error) {
 	return m, nil
 }
 
-var durationRe = regexp.MustCompile(`^\s*(?:(\d+)\s*h)?\s*(?:(\d+)\s*m)?\s*$`)
+var durationRe = regexp.MustCompile(
+	`^\s*(?:(\d+)\s*d)?\s*(?:(\d+)\s*h)?\s*(?:(\d+)\s*m)?\s*$`)
 
-// parseDuration parses hours an minutes to a duration.
+// parseDuration parses days, hours and minutes to a duration.
+// Each of the components is optional but they have to be in this order,
+// e.g. "2d 3h", "1h30m" or "90m".
 func parseDuration(d string) (time.Duration, error) {
 	match := durationRe.FindStringSubmatch(d)
 	if match == nil {
-		return 0, errors.New("not a valid duration")
+		return 0, fmt.Errorf("not a valid duration %q (expected e.g. \"1d 2h 30m\")", d)
 	}
-	var h, m int64
+	var days, h, m int64
 	if match[1] != "" {
-		h, _ = misc.Atoi64(match[1])
+		days, _ = misc.Atoi64(match[1])
 	}
 	if match[2] != "" {
-		m, _ = misc.Atoi64(match[2])
+		h, _ = misc.Atoi64(match[2])
 	}
-	return time.Duration(h)*time.Hour + time.Duration(m)*time.Minute, nil
+	if match[3] != "" {
+		m, _ = misc.Atoi64(match[3])
+	}
+	return time.Duration(days)*24*time.Hour +
+		time.Duration(h)*time.Hour +
+		time.Duration(m)*time.Minute, nil
 }
 
 // checkParam checks a list of errors if there are any.
@@ -100,3 +181,142 @@ func check(w http.ResponseWriter, r *http.Request, err error) bool {
 	}
 	return true
 }
+
+// pagination is used in templates to render pagination controls.
+type pagination struct {
+	Page    int64
+	PerPage int64
+	Total   int64
+}
+
+// parsePagination extracts the page and the number of entries per page
+// from the 'page' and 'per_page' parameters of the request.
+// Invalid values are replaced by the first page and the given default.
+func parsePagination(r *http.Request, defaultPerPage, maxPerPage int64) *pagination {
+	page, err := misc.Atoi64(r.FormValue("page"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+	perPage, err := misc.Atoi64(r.FormValue("per_page"))
+	if err != nil || perPage < 1 {
+		perPage = defaultPerPage
+	}
+	return &pagination{
+		Page:    page,
+		PerPage: min(perPage, maxPerPage),
+	}
+}
+
+// Offset returns the offset of the first entry of the current page.
+func (p *pagination) Offset() int64 {
+	return (p.Page - 1) * p.PerPage
+}
+
+// Pages returns the number of pages.
+func (p *pagination) Pages() int64 {
+	return max(1, (p.Total+p.PerPage-1)/p.PerPage)
+}
+
+// HasPrev returns true if there is a previous page.
+func (p *pagination) HasPrev() bool {
+	return p.Page > 1
+}
+
+// HasNext returns true if there is a next page.
+func (p *pagination) HasNext() bool {
+	return p.Page < p.Pages()
+}
+
+// Prev returns the number of the previous page.
+func (p *pagination) Prev() int64 {
+	return min(p.Page-1, p.Pages())
+}
+
+// Next returns the number of the next page.
+func (p *pagination) Next() int64 {
+	return p.Page + 1
+}
+
+// deletion is a bulk deletion which has to be confirmed
+// by the user before it is performed.
+type deletion struct {
+	// Action is the path the confirmation is posted to.
+	Action string
+	// Back is the URL of the listing the deletion was started from.
+	Back string
+	// Submit is the name of the submit button which triggered the deletion.
+	Submit string
+	// Verb describes what happens to the entries, e.g. "deleted".
+	Verb string
+	// Field is the name of the form field of the selected entries.
+	Field string
+	// Hidden are additional form fields which are posted again.
+	Hidden map[string]string
+	// Entries are the entries which will be deleted.
+	Entries []*deletionEntry
+	// Token authorizes exactly this deletion.
+	Token string
+}
+
+// deletionEntry is an entry of a bulk deletion.
+type deletionEntry struct {
+	Value string
+	Label string
+}
+
+// Values returns the values of the entries to be deleted.
+func (d *deletion) Values() []string {
+	values := make([]string, len(d.Entries))
+	for i, e := range d.Entries {
+		values[i] = e.Value
+	}
+	return values
+}
+
+// token returns a token bound to the session, the action
+// and the entries of the deletion.
+func (d *deletion) token(secret []byte, sessionID string) string {
+	values := d.Values()
+	slices.Sort(values)
+	mac := hmac.New(sha256.New, secret)
+	for _, s := range append([]string{sessionID, d.Action, d.Submit}, values...) {
+		mac.Write([]byte(s))
+		mac.Write([]byte{0})
+	}
+	return base64.URLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// confirmDeletion returns true if the user has confirmed the deletion.
+// Otherwise a page listing the entries to be deleted is rendered.
+// It posts the entries back together with a token which
+// is needed to perform the deletion.
+func (c *Controller) confirmDeletion(w http.ResponseWriter, r *http.Request, d *deletion) bool {
+	return c.confirmDeletionError(w, r, d, "")
+}
+
+// confirmDeletionError is like [Controller.confirmDeletion] but shows
+// the given message on the confirmation page.
+func (c *Controller) confirmDeletionError(
+	w http.ResponseWriter,
+	r *http.Request,
+	d *deletion,
+	errKey string,
+	errArgs ...any,
+) bool {
+	ctx := r.Context()
+	session := auth.SessionFromContext(ctx)
+	d.Token = d.token(c.cfg.Sessions.Secret, session.ID())
+	if hmac.Equal([]byte(r.FormValue("confirm")), []byte(d.Token)) {
+		return true
+	}
+	data := templateData{
+		"Session":  session,
+		"User":     auth.UserFromContext(ctx),
+		"Deletion": d,
+	}
+	if errKey != "" {
+		data.error(errKey, errArgs...)
+	}
+	check(w, r, c.templates(r).ExecuteTemplate(w, "confirm_delete.tmpl", data))
+	return false
+}
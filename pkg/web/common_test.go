@@ -0,0 +1,101 @@
+// This file is Free Software under the Apache-2.0 License
+// without warranty, see README.md and LICENSE for details.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileCopyrightText: 2025 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2025 Intevation GmbH <https://intevation.de>
+
+package web
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDaysHoursMinutes(t *testing.T) {
+	for _, tc := range []struct {
+		d    time.Duration
+		want string
+	}{
+		{0, "0m"},
+		{30 * time.Second, "1m"},
+		{45 * time.Minute, "45m"},
+		{2 * time.Hour, "2h"},
+		{36 * time.Hour, "1d 12h"},
+		{24*time.Hour + 5*time.Minute, "1d 5m"},
+		{365 * 24 * time.Hour, "365d"},
+		{50*time.Hour + 30*time.Minute, "2d 2h 30m"},
+	} {
+		got := daysHoursMinutes(tc.d)
+		if got != tc.want {
+			t.Errorf("%v: got %q, want %q", tc.d, got, tc.want)
+			continue
+		}
+		// The result has to be parseable again.
+		switch back, err := parseDuration(got); {
+		case err != nil:
+			t.Errorf("%q: %v", got, err)
+		case back != tc.d.Round(time.Minute):
+			t.Errorf("%q: parsed back to %v", got, back)
+		}
+	}
+}
+
+func TestParseDuration(t *testing.T) {
+	for _, tc := range []struct {
+		input string
+		want  time.Duration
+		fail  bool
+	}{
+		{input: "90m", want: 90 * time.Minute},
+		{input: "2h", want: 2 * time.Hour},
+		{input: "1h30m", want: 90 * time.Minute},
+		{input: "1h 30m", want: 90 * time.Minute},
+		{input: "1d", want: 24 * time.Hour},
+		{input: "2d 3h", want: 51 * time.Hour},
+		{input: "1d2h3m", want: 26*time.Hour + 3*time.Minute},
+		{input: " 1 d 2 h ", want: 26 * time.Hour},
+		{input: "", want: 0},
+		{input: "1h2x", fail: true},
+		{input: "30m 1h", fail: true},
+		{input: "1h 1d", fail: true},
+		{input: "1.5h", fail: true},
+		{input: "-1h", fail: true},
+		{input: "h", fail: true},
+		{input: "garbage", fail: true},
+	} {
+		got, err := parseDuration(tc.input)
+		switch {
+		case tc.fail && err == nil:
+			t.Errorf("%q: expected an error", tc.input)
+		case !tc.fail && err != nil:
+			t.Errorf("%q: unexpected error: %v", tc.input, err)
+		case !tc.fail && got != tc.want:
+			t.Errorf("%q: got %v, want %v", tc.input, got, tc.want)
+		}
+	}
+}
+
+func TestTextTooLong(t *testing.T) {
+	text := func(s string) *string { return &s }
+	for _, tc := range []struct {
+		name string
+		text *string
+		want bool
+	}{
+		{"nil", nil, false},
+		{"empty", text(""), false},
+		{"at limit", text(strings.Repeat("a", 10)), false},
+		{"over limit", text(strings.Repeat("a", 11)), true},
+		{"runes at limit", text(strings.Repeat("ü", 10)), false},
+		{"runes over limit", text(strings.Repeat("ü", 11)), true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := textTooLong(tc.text, 10); got != tc.want {
+				t.Errorf("got %t, want %t", got, tc.want)
+			}
+		})
+	}
+}
@@ -13,7 +13,6 @@ import (
 	"fmt"
 	"html/template"
 	"net/http"
-	"net/url"
 	"path/filepath"
 	"slices"
 	"time"
@@ -27,20 +26,20 @@ import (
 
 // Controller binds the endpoints to the internal logic.
 type Controller struct {
-	cfg   *config.Config
-	db    *database.Database
-	tmpls *template.Template
+	cfg    *config.Config
+	db     *database.Database
+	tmpls  map[string]*template.Template
+	quorum quorumWatcher
+	events meetingEvents
 }
 
 type templateData map[string]any
 
-func (td templateData) error(msg string) {
-	if v, ok := td["error"]; ok {
-		if m, ok := v.(string); ok {
-			msg = m + " " + msg
-		}
-	}
-	td["Error"] = msg
+// error adds the message with the given key and arguments
+// to the errors shown to the user.
+func (td templateData) error(key string, args ...any) {
+	msgs, _ := td["Error"].([]*message)
+	td["Error"] = append(msgs, &message{key: key, args: args})
 }
 
 func (td templateData) hasError() bool {
@@ -53,7 +52,9 @@ var templateFuncs = template.FuncMap{
 	"Role":                      models.ParseRole,
 	"MemberStatus":              models.ParseMemberStatus,
 	"MeetingStatus":             models.ParseMeetingStatus,
+	"AbsentStatus":              models.ParseAbsentStatus,
 	"Shorten":                   misc.Shorten,
+	"ShortenN":                  misc.ShortenN,
 	"Args":                      args,
 	"CommitteeIDFilter":         models.CommitteeIDFilter,
 	"RunningFilter":             func() models.MeetingFilter { return models.RunningFilter },
@@ -61,6 +62,7 @@ var templateFuncs = template.FuncMap{
 	"DatetimeHoursMinutes":      datetimeHoursMinutes,
 	"HoursMinutes":              hoursMinutes,
 	"Now":                       func() time.Time { return time.Now().UTC() },
+	"InTZ":                      inTZ,
 }
 
 // NewController returns a new Controller.
@@ -70,15 +72,31 @@ func NewController(
 ) (*Controller, error) {
 	path := filepath.Join(cfg.Web.Root, "templates", "*.tmpl")
 
-	tmpls, err := template.New("index").Funcs(templateFuncs).ParseGlob(path)
+	tmpl, err := template.New("index").
+		Funcs(templateFuncs).
+		Funcs(localeFuncs(defaultLocale)).
+		ParseGlob(path)
 	if err != nil {
 		return nil, fmt.Errorf("loading templates failed: %w", err)
 	}
 
+	// One set of templates per locale as the functions
+	// cannot be exchanged while executing.
+	tmpls := make(map[string]*template.Template, len(catalogs))
+	for locale := range catalogs {
+		clone, err := tmpl.Clone()
+		if err != nil {
+			return nil, fmt.Errorf("cloning templates failed: %w", err)
+		}
+		tmpls[locale] = clone.Funcs(localeFuncs(locale))
+	}
+
 	return &Controller{
-		cfg:   cfg,
-		db:    db,
-		tmpls: tmpls,
+		cfg:    cfg,
+		db:     db,
+		tmpls:  tmpls,
+		quorum: quorumWatcher{reached: map[int64]bool{}},
+		events: meetingEvents{subscribers: map[int64]map[chan *meetingEvent]struct{}{}},
 	}, nil
 }
 
@@ -91,81 +109,121 @@ func (c *Controller) home(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// An explicitly requested view overrides the stored preference.
+	view := r.FormValue("view")
+	if view == "" {
+		view = user.PreferredView()
+	}
+	http.Redirect(w, r, session.URL(landingPage(user, view)), http.StatusFound)
+}
+
+// landingPage returns the page the user should land on.
+// The view is honored if the user has the needed roles.
+// Otherwise the page is picked by role precedence.
+func landingPage(user *models.User, view string) string {
 	var isMember, isChair, isSecretary bool
 	for _, i := range user.Memberships {
 		isChair = isChair || slices.Contains(i.Roles, models.ChairRole)
 		isSecretary = isSecretary || slices.Contains(i.Roles, models.SecretaryRole)
 		isMember = isMember || slices.Contains(i.Roles, models.MemberRole)
 	}
-
-	redirectURI := "/user"
 	switch {
+	case view == "chair" && (isChair || isSecretary):
+		return "/chair"
+	case view == "member" && isMember:
+		return "/member"
 	case user.IsAdmin:
-		redirectURI = "/users"
+		return "/users"
 	case isChair || isSecretary:
-		redirectURI = "/chair"
+		return "/chair"
 	case isMember:
-		redirectURI = "/member"
+		return "/member"
 	}
-
-	http.Redirect(w, r, redirectURI+"?SESSIONID="+url.QueryEscape(session.ID()), http.StatusFound)
+	return "/user"
 }
 
 // Bind return a http handler to be used in a web server.
 func (c *Controller) Bind() http.Handler {
 	router := http.NewServeMux()
-	mw := auth.NewMiddleware(c.cfg, c.db, "/auth")
+	mw := auth.NewMiddleware(c.cfg, c.db, "/auth", "/user")
 
 	for _, route := range []struct {
 		pattern string
 		handler http.HandlerFunc
 	}{
 		// Auth
-		{"/auth", c.auth},
-		{"/login", c.login},
-		{"/logout", mw.LoggedIn(c.logout)},
-		{"/", mw.User(c.home)},
+		{"GET /auth", c.auth},
+		{"POST /login", c.login},
+		{"POST /logout", mw.LoggedIn(c.logout)},
+		{"GET /{$}", mw.User(c.home)},
 		// User
-		{"/user", mw.User(c.user)},
-		{"/user_store", mw.User(c.userStore)},
-		{"/user_create", mw.Admin(c.userCreate)},
-		{"/user_edit", mw.AdminOrRoles(c.userEdit, models.StaffRole)},
-		{"/user_edit_store", mw.Admin(c.userEditStore)},
-		{"/user_create_store", mw.Admin(c.userCreateStore)},
-		{"/user_committees_store", mw.AdminOrRoles(c.userCommitteesStore, models.StaffRole)},
-		{"/users", mw.AdminOrRoles(c.users, models.StaffRole)},
-		{"/users_store", mw.Admin(c.usersStore)},
+		{"GET /user", mw.PasswordChange(c.user)},
+		{"POST /user_store", mw.PasswordChange(c.userStore)},
+		{"GET /sessions", mw.User(c.sessions)},
+		{"POST /sessions_store", mw.User(c.sessionsStore)},
+		{"GET /user_create", mw.Admin(c.userCreate)},
+		{"GET /user_edit", mw.AdminOrRoles(c.userEdit, models.StaffRole)},
+		{"POST /user_edit_store", mw.Admin(c.userEditStore)},
+		{"POST /user_reset_password", mw.Admin(c.userResetPassword)},
+		{"POST /user_unlock", mw.Admin(c.userUnlock)},
+		{"POST /user_create_store", mw.Admin(c.userCreateStore)},
+		{"POST /user_committees_store", mw.AdminOrRoles(c.userCommitteesStore, models.StaffRole)},
+		{"GET /users", mw.AdminOrRoles(c.users, models.StaffRole)},
+		{"POST /users_store", mw.Admin(c.usersStore)},
+		{"GET /users_merge", mw.Admin(c.usersMerge)},
+		{"POST /users_merge_store", mw.Admin(c.usersMergeStore)},
 		// Committees
-		{"/committee_edit", mw.Admin(c.committeeEdit)},
-		{"/committee_edit_store", mw.Admin(c.committeeEditStore)},
-		{"/committees", mw.Admin(c.committees)},
-		{"/committees_store", mw.Admin(c.committeesStore)},
-		{"/committee_create", mw.Admin(c.committeeCreate)},
-		{"/committee_store", mw.Admin(c.committeeStore)},
+		{"GET /committee_edit", mw.Admin(c.committeeEdit)},
+		{"POST /committee_edit_store", mw.Admin(c.committeeEditStore)},
+		{"POST /committee_archive", mw.Admin(c.committeeArchive)},
+		{"GET /committees", mw.Admin(c.committees)},
+		{"POST /committees_store", mw.Admin(c.committeesStore)},
+		{"GET /committee_create", mw.Admin(c.committeeCreate)},
+		{"POST /committee_store", mw.Admin(c.committeeStore)},
+		{"POST /committee_import", c.limitUpload(mw.Admin(c.committeeImport))},
+		{"GET /committee_export", mw.Admin(c.committeeExport)},
+		{"GET /running_meetings", mw.Admin(c.runningMeetings)},
+		{"GET /dbstats", mw.Admin(c.dbStats)},
 		// Chair and Secretary
-		{"/chair", mw.Roles(c.chair, models.ChairRole, models.SecretaryRole, models.StaffRole)},
-		{"/absent_overview", mw.Roles(c.absentOverview, models.ChairRole, models.SecretaryRole, models.StaffRole)},
-		{"/absent_store", mw.Roles(c.absentStore, models.ChairRole, models.SecretaryRole, models.StaffRole)},
-		{"/absent_create_store", mw.Roles(c.absentCreateStore, models.ChairRole, models.SecretaryRole, models.StaffRole)},
-		{"/meetings_overview", mw.CommitteeRoles(c.meetingsOverview, models.ChairRole, models.MemberRole, models.SecretaryRole, models.StaffRole)},
-		{"/meetings_store", mw.CommitteeRoles(c.meetingsStore, models.ChairRole, models.SecretaryRole, models.StaffRole)},
-		{"/meeting_create", mw.CommitteeRoles(c.meetingCreate, models.ChairRole, models.SecretaryRole, models.StaffRole)},
-		{"/meeting_create_store", mw.CommitteeRoles(c.meetingCreateStore, models.ChairRole, models.SecretaryRole, models.StaffRole)},
-		{"/meeting_edit", mw.CommitteeRoles(c.meetingEdit, models.ChairRole, models.SecretaryRole, models.StaffRole)},
-		{"/meeting_edit_store", mw.CommitteeRoles(c.meetingEditStore, models.ChairRole, models.SecretaryRole, models.StaffRole)},
-		{"/meeting_status", mw.CommitteeRoles(c.meetingStatus, models.ChairRole, models.MemberRole, models.SecretaryRole, models.StaffRole)},
-		{"/meeting_status_store", mw.CommitteeRoles(c.meetingStatusStore, models.ChairRole, models.SecretaryRole, models.StaffRole)},
-		{"/meeting_attend_store", mw.CommitteeRoles(c.meetingAttendStore, models.ChairRole, models.SecretaryRole, models.StaffRole)},
-		{"/meetings_export", mw.CommitteeRoles(c.meetingsExport, models.ChairRole, models.SecretaryRole, models.StaffRole)},
+		{"GET /chair", mw.Roles(c.chair, models.ChairRole, models.SecretaryRole, models.StaffRole)},
+		{"GET /absent_overview", mw.Roles(c.absentOverview, models.ChairRole, models.SecretaryRole, models.StaffRole)},
+		{"POST /absent_store", mw.Roles(c.absentStore, models.ChairRole, models.SecretaryRole, models.StaffRole)},
+		{"POST /absent_create_store", mw.Roles(c.absentCreateStore, models.ChairRole, models.SecretaryRole, models.StaffRole)},
+		{"GET /meetings_overview", mw.CommitteeRoles(c.meetingsOverview, models.ChairRole, models.MemberRole, models.SecretaryRole, models.StaffRole)},
+		{"POST /meetings_store", mw.CommitteeRoles(c.meetingsStore, models.ChairRole, models.SecretaryRole, models.StaffRole)},
+		{"GET /meeting_create", mw.CommitteeRoles(c.meetingCreate, models.ChairRole, models.SecretaryRole, models.StaffRole)},
+		{"GET /meeting_clone", mw.CommitteeRoles(c.meetingClone, models.ChairRole, models.SecretaryRole, models.StaffRole)},
+		{"POST /meeting_create_store", mw.CommitteeRoles(c.meetingCreateStore, models.ChairRole, models.SecretaryRole, models.StaffRole)},
+		{"GET /meeting_edit", mw.CommitteeRoles(c.meetingEdit, models.ChairRole, models.SecretaryRole, models.StaffRole)},
+		{"POST /meeting_edit_store", mw.CommitteeRoles(c.meetingEditStore, models.ChairRole, models.SecretaryRole, models.StaffRole)},
+		{"POST /meeting_move", mw.AdminOrCommitteeRoles(c.meetingMove, models.ChairRole)},
+		{"POST /meeting_expected_store", mw.CommitteeRoles(c.meetingExpectedStore, models.ChairRole, models.SecretaryRole, models.StaffRole)},
+		{"GET /meeting_status", mw.CommitteeRoles(c.meetingStatus, models.ChairRole, models.MemberRole, models.SecretaryRole, models.StaffRole)},
+		{"POST /meeting_status_store", mw.CommitteeRoles(c.meetingStatusStore, models.ChairRole, models.SecretaryRole, models.StaffRole)},
+		{"GET /meeting_events", mw.CommitteeRoles(c.meetingEvents, models.ChairRole, models.MemberRole, models.SecretaryRole, models.StaffRole)},
+		{"POST /meeting_attend_toggle", mw.CommitteeRoles(c.meetingAttendToggle, models.ChairRole, models.SecretaryRole, models.StaffRole)},
+		{"POST /meeting_attend_store", mw.CommitteeRoles(c.meetingAttendStore, models.ChairRole, models.SecretaryRole, models.StaffRole)},
+		{"POST /member_status_revert", mw.CommitteeRoles(c.memberStatusRevert, models.ChairRole)},
+		{"POST /committee_bulk_status_store", mw.CommitteeRoles(c.committeeBulkStatusStore, models.ChairRole)},
+		{"POST /chair_transfer", mw.AdminOrCommitteeRoles(c.chairTransfer, models.ChairRole)},
+		{"GET /committee_stats", mw.CommitteeRoles(c.committeeStats, models.ChairRole, models.SecretaryRole, models.StaffRole)},
+		{"GET /meeting_export", mw.CommitteeRoles(c.meetingExport, models.ChairRole, models.SecretaryRole, models.StaffRole)},
+		{"GET /meetings_export", mw.CommitteeRoles(c.meetingsExport, models.ChairRole, models.SecretaryRole, models.StaffRole)},
+		{"GET /member_streaks_export", mw.CommitteeRoles(c.memberStreaksExport, models.ChairRole, models.SecretaryRole, models.StaffRole)},
+		{"GET /member_history_export", mw.AdminOrCommitteeRoles(c.memberHistoryExport, models.ChairRole)},
+		{"GET /meeting_document", mw.CommitteeRoles(c.meetingDocument, models.ChairRole, models.MemberRole, models.SecretaryRole, models.StaffRole)},
+		{"POST /meeting_document_store", c.limitUpload(mw.CommitteeRoles(c.meetingDocumentStore, models.ChairRole, models.SecretaryRole, models.StaffRole))},
 		// Member
-		{"/member", mw.Roles(c.member, models.MemberRole)},
-		{"/member_attend", mw.CommitteeRoles(c.memberAttend, models.MemberRole)},
+		{"GET /member", mw.Roles(c.member, models.MemberRole)},
+		{"POST /member_attend", mw.CommitteeRoles(c.memberAttend, models.MemberRole)},
+		{"GET /absence_request", mw.CommitteeRoles(c.absenceRequest, models.MemberRole)},
+		{"POST /absence_request_store", mw.CommitteeRoles(c.absenceRequestStore, models.MemberRole)},
 	} {
 		router.HandleFunc(route.pattern, route.handler)
 	}
 
 	static := http.FileServer(http.Dir(c.cfg.Web.Root))
-	router.Handle("/static/", static)
+	router.Handle("GET /static/", static)
 
-	return router
+	return auth.AccessLog(c.cfg.Log.AccessLevel, router)
 }
@@ -0,0 +1,193 @@
+// This file is Free Software under the Apache-2.0 License
+// without warranty, see README.md and LICENSE for details.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileCopyrightText: 2025 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2025 Intevation GmbH <https://intevation.de>
+
+package web
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/csaf-auxiliary/oasis-quorum-calculator/pkg/config"
+	"github.com/csaf-auxiliary/oasis-quorum-calculator/pkg/database"
+	"github.com/csaf-auxiliary/oasis-quorum-calculator/pkg/misc"
+)
+
+// testPassword is the password of all users created by [testServer.user].
+const testPassword = "secret"
+
+// testServer is a running web server backed by an empty database.
+type testServer struct {
+	*httptest.Server
+	cfg *config.Config
+	db  *database.Database
+}
+
+// newTestServer starts a web server with the default configuration.
+// The admin user can log in with [testPassword].
+func newTestServer(t *testing.T) *testServer {
+	t.Helper()
+	cfg, err := config.Load("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg.Web.Root = filepath.Join("..", "..", "web")
+	cfg.Documents.Directory = t.TempDir()
+	cfg.Database = config.Database{
+		DatabaseURL:        filepath.Join(t.TempDir(), "oqcd.sqlite"),
+		Driver:             "sqlite3",
+		Migrate:            true,
+		MaxOpenConnections: 1,
+		MaxIdleConnections: 1,
+	}
+	cfg.PresetDefaults()
+	db, err := database.NewDatabase(context.Background(), &cfg.Database)
+	if err != nil {
+		t.Fatalf("creating database failed: %v", err)
+	}
+	t.Cleanup(func() { db.DB.Close() })
+	ctrl, err := NewController(cfg, db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := &testServer{
+		Server: httptest.NewServer(ctrl.Bind()),
+		cfg:    cfg,
+		db:     db,
+	}
+	t.Cleanup(srv.Close)
+	srv.exec(t, `UPDATE users SET password = ?, must_change_password = false `+
+		`WHERE nickname = 'admin'`, misc.EncodePassword(testPassword))
+	return srv
+}
+
+// exec executes a statement as a fixture.
+func (srv *testServer) exec(t *testing.T, stmt string, args ...any) {
+	t.Helper()
+	if _, err := srv.db.DB.Exec(stmt, args...); err != nil {
+		t.Fatalf("fixture %q failed: %v", stmt, err)
+	}
+}
+
+// user creates a user with [testPassword] as password.
+func (srv *testServer) user(t *testing.T, nickname string) {
+	t.Helper()
+	srv.exec(t, `INSERT INTO users (nickname, password, firstname, lastname) `+
+		`VALUES (?, ?, ?, ?)`, nickname, misc.EncodePassword(testPassword), nickname, nickname)
+}
+
+// post sends the form without following redirects.
+func (srv *testServer) post(t *testing.T, path string, form url.Values) *http.Response {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodPost, srv.URL+path, strings.NewReader(form.Encode()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return srv.do(t, req)
+}
+
+// get requests the path without following redirects.
+func (srv *testServer) get(t *testing.T, path string, query url.Values) *http.Response {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodGet, srv.URL+path+"?"+query.Encode(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return srv.do(t, req)
+}
+
+func (srv *testServer) do(t *testing.T, req *http.Request) *http.Response {
+	t.Helper()
+	client := *srv.Client()
+	client.CheckRedirect = func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { resp.Body.Close() })
+	return resp
+}
+
+// login logs the user in and returns the session id.
+func (srv *testServer) login(t *testing.T, nickname, password string) string {
+	t.Helper()
+	resp := srv.post(t, "/login", url.Values{
+		"nickname": {nickname},
+		"password": {password},
+	})
+	if resp.StatusCode != http.StatusFound {
+		t.Fatalf("login of %q failed: %s", nickname, resp.Status)
+	}
+	loc, err := resp.Location()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sessionID := loc.Query().Get("SESSIONID")
+	if sessionID == "" {
+		t.Fatalf("login of %q returned no session: %s", nickname, loc)
+	}
+	return sessionID
+}
+
+// body returns the body of the response.
+func body(t *testing.T, resp *http.Response) string {
+	t.Helper()
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
+
+// routePattern matches the routes registered in [Controller.Bind].
+var routePattern = regexp.MustCompile(`\{"(GET|POST) (/[a-z_]+)"`)
+
+// routes returns the paths of the routes with the given method.
+func routes(t *testing.T, method string) []string {
+	t.Helper()
+	src, err := os.ReadFile("controller.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var paths []string
+	for _, m := range routePattern.FindAllStringSubmatch(string(src), -1) {
+		if m[1] == method {
+			paths = append(paths, m[2])
+		}
+	}
+	return paths
+}
+
+func TestMethodNotAllowed(t *testing.T) {
+	srv := newTestServer(t)
+	posts := routes(t, http.MethodPost)
+	if !slices.Contains(posts, "/user_store") || !slices.Contains(posts, "/login") {
+		t.Fatalf("routes not found: %q", posts)
+	}
+	for _, path := range posts {
+		if resp := srv.get(t, path, nil); resp.StatusCode != http.StatusMethodNotAllowed {
+			t.Errorf("GET %s: got %s", path, resp.Status)
+		}
+	}
+	for _, path := range routes(t, http.MethodGet) {
+		if resp := srv.post(t, path, nil); resp.StatusCode != http.StatusMethodNotAllowed {
+			t.Errorf("POST %s: got %s", path, resp.Status)
+		}
+	}
+}
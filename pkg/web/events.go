@@ -0,0 +1,237 @@
+// This file is Free Software under the Apache-2.0 License
+// without warranty, see README.md and LICENSE for details.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileCopyrightText: 2025 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2025 Intevation GmbH <https://intevation.de>
+
+package web
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"maps"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/csaf-auxiliary/oasis-quorum-calculator/pkg/misc"
+	"github.com/csaf-auxiliary/oasis-quorum-calculator/pkg/models"
+)
+
+// eventsKeepAlive is the interval in which comments are sent
+// to keep idle event streams open.
+const eventsKeepAlive = 30 * time.Second
+
+// meetingEvent is the state of a meeting sent to the event streams.
+type meetingEvent struct {
+	Meeting   int64             `json:"meeting"`
+	Status    string            `json:"status"`
+	Reached   bool              `json:"reached"`
+	Quorum    quorumEventCounts `json:"quorum"`
+	Attendees []string          `json:"attendees"`
+}
+
+// meetingEvents distributes the events of meetings to the subscribers.
+type meetingEvents struct {
+	mu          sync.Mutex
+	subscribers map[int64]map[chan *meetingEvent]struct{}
+}
+
+// subscribe registers a new subscriber to the events of a meeting.
+// The returned function has to be called to unsubscribe.
+func (me *meetingEvents) subscribe(meetingID int64) (<-chan *meetingEvent, func()) {
+	// Only the latest event is of interest so one slot is enough.
+	ch := make(chan *meetingEvent, 1)
+	me.mu.Lock()
+	defer me.mu.Unlock()
+	subs := me.subscribers[meetingID]
+	if subs == nil {
+		subs = map[chan *meetingEvent]struct{}{}
+		me.subscribers[meetingID] = subs
+	}
+	subs[ch] = struct{}{}
+	return ch, func() {
+		me.mu.Lock()
+		defer me.mu.Unlock()
+		delete(subs, ch)
+		if len(subs) == 0 {
+			delete(me.subscribers, meetingID)
+		}
+	}
+}
+
+// subscribed returns true if there are subscribers to a meeting.
+func (me *meetingEvents) subscribed(meetingID int64) bool {
+	me.mu.Lock()
+	defer me.mu.Unlock()
+	return len(me.subscribers[meetingID]) > 0
+}
+
+// publish sends an event to all subscribers of its meeting.
+// Events not yet consumed by slow subscribers are replaced.
+func (me *meetingEvents) publish(event *meetingEvent) {
+	me.mu.Lock()
+	defer me.mu.Unlock()
+	for ch := range me.subscribers[event.Meeting] {
+		select {
+		case <-ch:
+		default:
+		}
+		select {
+		case ch <- event:
+		default:
+		}
+	}
+}
+
+// loadMeetingEvent loads the current state of a meeting.
+// members are the members of the committee at the start of the meeting.
+func (c *Controller) loadMeetingEvent(
+	ctx context.Context,
+	meeting *models.Meeting,
+	members []*models.User,
+) (*meetingEvent, error) {
+	attendees, err := meeting.Attendees(ctx, c.db)
+	if err != nil {
+		return nil, err
+	}
+	expected, err := models.LoadMeetingExpected(ctx, c.db, meeting.ID)
+	if err != nil {
+		return nil, err
+	}
+	quorum := calculateQuorum(members, meeting.CommitteeID, attendees, expected)
+	nicknames := slices.AppendSeq(make([]string, 0, len(attendees)), maps.Keys(attendees))
+	slices.Sort(nicknames)
+	return &meetingEvent{
+		Meeting:   meeting.ID,
+		Status:    meeting.Status.String(),
+		Reached:   quorum.Reached(),
+		Quorum:    newQuorumEventCounts(quorum),
+		Attendees: nicknames,
+	}, nil
+}
+
+// publishMeeting sends the current state of a meeting to its subscribers.
+// If members is nil the members of the committee are loaded if needed.
+func (c *Controller) publishMeeting(
+	ctx context.Context,
+	meeting *models.Meeting,
+	members []*models.User,
+) error {
+	if !c.events.subscribed(meeting.ID) {
+		return nil
+	}
+	if members == nil {
+		var err error
+		if members, err = models.LoadCommitteeUsers(
+			ctx, c.db, meeting.CommitteeID, &meeting.StartTime,
+		); err != nil {
+			return err
+		}
+	}
+	event, err := c.loadMeetingEvent(ctx, meeting, members)
+	if err != nil {
+		return err
+	}
+	c.events.publish(event)
+	return nil
+}
+
+// MeetingConcluded updates the state kept about a meeting which was
+// concluded outside of the web interface like by the [models.Concluder].
+// The event streams of the meeting are told about the conclusion.
+func (c *Controller) MeetingConcluded(ctx context.Context, meeting *models.Meeting) {
+	c.quorum.forget(meeting.ID)
+	if err := c.publishMeeting(ctx, meeting, nil); err != nil {
+		slog.ErrorContext(ctx, "publishing concluded meeting failed",
+			"meeting", meeting.ID, "error", err)
+	}
+}
+
+// writeMeetingEvent writes an event in the server-sent events format.
+func writeMeetingEvent(w http.ResponseWriter, event *meetingEvent) error {
+	var buf bytes.Buffer
+	buf.WriteString("event: meeting\ndata: ")
+	if err := json.NewEncoder(&buf).Encode(event); err != nil {
+		return err
+	}
+	// The encoder terminates the data line.
+	buf.WriteByte('\n')
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+func (c *Controller) meetingEvents(w http.ResponseWriter, r *http.Request) {
+	var (
+		meetingID, err1   = misc.Atoi64(r.FormValue("meeting"))
+		committeeID, err2 = misc.Atoi64(r.FormValue("committee"))
+		ctx               = r.Context()
+	)
+	if !checkParam(w, err1, err2) {
+		return
+	}
+	meeting, err := models.LoadMeeting(ctx, c.db, meetingID, committeeID)
+	if !check(w, r, err) {
+		return
+	}
+	if meeting == nil {
+		http.Error(w, "meeting not found", http.StatusNotFound)
+		return
+	}
+	if meeting.Status != models.MeetingRunning {
+		http.Error(w, "meeting is not running", http.StatusConflict)
+		return
+	}
+	// Subscribe before loading the state to not miss any change.
+	events, unsubscribe := c.events.subscribe(meetingID)
+	defer unsubscribe()
+
+	members, err := models.LoadCommitteeUsers(ctx, c.db, committeeID, &meeting.StartTime)
+	if !check(w, r, err) {
+		return
+	}
+	event, err := c.loadMeetingEvent(ctx, meeting, members)
+	if !check(w, r, err) {
+		return
+	}
+
+	rc := http.NewResponseController(w)
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	keepAlive := time.NewTicker(eventsKeepAlive)
+	defer keepAlive.Stop()
+	for {
+		if event != nil {
+			if err := writeMeetingEvent(w, event); err != nil {
+				return
+			}
+			// The stream ends with the meeting.
+			if event.Status != models.MeetingRunning.String() {
+				rc.Flush()
+				return
+			}
+			event = nil
+		}
+		if err := rc.Flush(); err != nil {
+			return
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case event = <-events:
+		case <-keepAlive.C:
+			if _, err := fmt.Fprint(w, ": keep-alive\n\n"); err != nil {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,203 @@
+// This file is Free Software under the Apache-2.0 License
+// without warranty, see README.md and LICENSE for details.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileCopyrightText: 2025 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2025 Intevation GmbH <https://intevation.de>
+
+package web
+
+import (
+	"fmt"
+	"html/template"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/csaf-auxiliary/oasis-quorum-calculator/pkg/models"
+)
+
+// defaultLocale is used if no other supported locale is requested.
+// Its catalog is the fallback for messages missing in other catalogs.
+const defaultLocale = "en"
+
+// catalog maps message keys to the texts of a locale.
+// The texts are formatted with [fmt.Sprintf] if there are arguments.
+type catalog map[string]string
+
+// catalogs are the message catalogs of the supported locales.
+var catalogs = map[string]catalog{
+	"en": catalogEN,
+	"de": catalogDE,
+}
+
+// message is a translatable message with its arguments.
+type message struct {
+	key  string
+	args []any
+}
+
+// translate looks up the text of the key in the catalog of the locale.
+// It falls back to the default locale and then to the key itself.
+func translate(locale, key string, args ...any) string {
+	text, ok := catalogs[locale][key]
+	if !ok {
+		if text, ok = catalogs[defaultLocale][key]; !ok {
+			text = key
+		}
+	}
+	if len(args) > 0 {
+		return fmt.Sprintf(text, args...)
+	}
+	return text
+}
+
+// localeFuncs returns the template functions bound to a locale.
+func localeFuncs(locale string) template.FuncMap {
+	return template.FuncMap{
+		"T": func(key string, args ...any) string {
+			return translate(locale, key, args...)
+		},
+		"Message": func(m *message) string {
+			return translate(locale, m.key, m.args...)
+		},
+		"StatusLabel": func(status models.MemberStatus) string {
+			return translate(locale, "status."+status.String())
+		},
+		"RoleLabel": func(role models.Role) string {
+			return translate(locale, "role."+role.String())
+		},
+	}
+}
+
+// negotiateLocale returns the supported locale with the highest
+// quality in the given Accept-Language header.
+func negotiateLocale(acceptLanguage string) string {
+	best, bestQ := defaultLocale, 0.0
+	for _, lang := range strings.Split(acceptLanguage, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(lang), ";")
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			var err error
+			if q, err = strconv.ParseFloat(v, 64); err != nil {
+				continue
+			}
+		}
+		// Only the primary language is relevant, e.g. "de" of "de-AT".
+		tag, _, _ = strings.Cut(strings.ToLower(tag), "-")
+		if _, ok := catalogs[tag]; ok && q > bestQ {
+			best, bestQ = tag, q
+		}
+	}
+	return best
+}
+
+// templates returns the templates in the locale requested by the client.
+func (c *Controller) templates(r *http.Request) *template.Template {
+	return c.tmpls[negotiateLocale(r.Header.Get("Accept-Language"))]
+}
+
+// catalogEN is the English message catalog.
+var catalogEN = catalog{
+	"locale": "en",
+	// Navigation
+	"nav.users":      "users",
+	"nav.committees": "committees",
+	"nav.running":    "running",
+	"nav.chair":      "chair",
+	"nav.member":     "member",
+	"nav.me":         "me",
+	"nav.logout":     "Logout",
+	"footer.github":  "OQC at",
+	// Login
+	"login.legend":   "Login",
+	"login.user":     "User:",
+	"login.password": "Password:",
+	"login.submit":   "Login",
+	// Meeting status
+	"meeting.onhold":    "Waiting",
+	"meeting.running":   "Running",
+	"meeting.concluded": "Concluded",
+	// Meetings filter
+	"filter.status": "Status:",
+	"filter.any":    "Any",
+	"filter.from":   "From:",
+	"filter.to":     "To:",
+	"filter.search": "Search descriptions",
+	"filter.submit": "Filter",
+	"filter.reset":  "Reset",
+	// Member status
+	"status.member":     "Non-voting member",
+	"status.voting":     "Voting member",
+	"status.nonevoting": "Persistent non-voting member",
+	"status.nomember":   "No member",
+	"status.observer":   "Observer",
+	// Roles
+	"role.chair":     "Chair",
+	"role.member":    "Member",
+	"role.secretary": "Secretary",
+	"role.staff":     "Staff",
+	// Errors
+	"error.label":                    "Error:",
+	"error.missing_user_name":        "Missing user name",
+	"error.missing_password":         "Missing password",
+	"error.login_failed":             "Login failed",
+	"error.account_locked":           "The account is temporarily locked after too many failed logins.",
+	"error.invalid_timezone":         "Invalid timezone.",
+	"error.invalid_email":            "Invalid email address.",
+	"error.invalid_entries":          "%d of the selected entries are invalid. Nothing was changed.",
+	"error.meetings_concluded":       "%d of the %d selected meetings are concluded and cannot be removed.",
+	"error.meetings_not_deleted":     "%d of %d meetings deleted; concluded meetings cannot be removed.",
+	"error.invalid_view":             "Unknown landing page.",
+	"error.modified_concurrently":    "This record was modified by someone else; the current state has been reloaded.",
+	"error.start_stop_invalid":       "Start time and stop time are invalid.",
+	"error.start_invalid":            "Start time is invalid.",
+	"error.stop_invalid":             "Stop time is invalid.",
+	"error.start_duration_invalid":   "Start time and duration are invalid.",
+	"error.duration_invalid":         "Duration is invalid.",
+	"error.duration_not_positive":    "Duration must be greater than zero.",
+	"error.duration_too_long":        "Duration must not exceed %s.",
+	"error.absent_collides":          "Time range collides with another excused absent in this committee.",
+	"error.absent_too_long":          "Maximum absent time is %s.",
+	"error.absent_only_self":         "You can only request excused absents for yourself.",
+	"error.meeting_collides":         "Time range collides with another meeting in this committee.",
+	"error.meeting_concluded":        "Concluded meetings cannot be changed.",
+	"error.meeting_move_collides":    "The meeting collides with another meeting of the target committee.",
+	"error.committee_archived":       "The committee is archived and accepts no new meetings.",
+	"error.description_too_long":     "Description must not exceed %d characters.",
+	"error.agenda_too_long":          "Agenda must not exceed %d characters.",
+	"error.no_voters_run":            "Cannot run meeting: the committee has no voting members.",
+	"error.no_voters_quorum":         "The committee has no voting members. The quorum cannot be reached.",
+	"error.already_running":          "Already have a running meeting in this committee.",
+	"error.attendance_closed":        "Attendance can only be changed while the meeting is running or shortly after its conclusion.",
+	"error.newer_concluded":          "Already have a concluded meeting that is newer.",
+	"error.only_running_conclude":    "Only running meetings can be concluded.",
+	"error.no_status_change":         "No status change of %q to revert.",
+	"error.members_not_removable":    "Members cannot be removed by a status change.",
+	"error.no_members_selected":      "No members selected.",
+	"error.only_members_status":      "Only members of the committee can change their status.",
+	"error.document_too_large":       "Document is too large.",
+	"error.no_document":              "No document selected.",
+	"error.upload_failed":            "Uploading document failed.",
+	"error.content_type_not_allowed": "Content type %q is not allowed.",
+	"error.import_invalid":           "The committee could not be imported: %s",
+	"error.committee_name_missing":   "Missing committee name.",
+	"error.name_missing":             "Name is missing.",
+	"error.committee_exists":         "Committee %q already exists.",
+	"error.slug_missing":             "Missing short code.",
+	"error.slug_invalid":             "Short code may only contain lower case letters, digits and single dashes.",
+	"error.slug_taken":               "Short code %q is already used by another committee.",
+	"error.password_mismatch":        "Password and confirmation do not match.",
+	"error.password_too_short":       "Password too short (need at least 8 characters)",
+	"error.last_admin":               "The last administrator cannot be removed.",
+	"error.merge_protected":          "The user %q cannot be removed.",
+	"error.merge_self":               "A user cannot be merged with itself.",
+	"error.unknown_user":             "Unknown user %q.",
+	"error.last_chair":               "The last chair of a committee cannot be removed. Transfer the chair first.",
+	"error.not_chair":                "%q is not a chair of the committee.",
+	"error.already_chair":            "%q already is a chair of the committee.",
+	"error.not_active_member":        "%q is no active user of the committee.",
+	"error.login_name_missing":       "Login name is missing.",
+	"error.user_exists":              "A user with the name %q already exists (names are case-insensitive).",
+}
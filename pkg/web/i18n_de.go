@@ -0,0 +1,113 @@
+// This file is Free Software under the Apache-2.0 License
+// without warranty, see README.md and LICENSE for details.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileCopyrightText: 2025 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2025 Intevation GmbH <https://intevation.de>
+
+package web
+
+// catalogDE is the German message catalog.
+var catalogDE = catalog{
+	"locale": "de",
+	// Navigation
+	"nav.users":      "Benutzer",
+	"nav.committees": "Gremien",
+	"nav.running":    "laufend",
+	"nav.chair":      "Vorsitz",
+	"nav.member":     "Mitglied",
+	"nav.me":         "ich",
+	"nav.logout":     "Abmelden",
+	"footer.github":  "OQC auf",
+	// Login
+	"login.legend":   "Anmeldung",
+	"login.user":     "Benutzer:",
+	"login.password": "Passwort:",
+	"login.submit":   "Anmelden",
+	// Meeting status
+	"meeting.onhold":    "Wartend",
+	"meeting.running":   "Läuft",
+	"meeting.concluded": "Abgeschlossen",
+	// Meetings filter
+	"filter.status": "Status:",
+	"filter.any":    "Alle",
+	"filter.from":   "Von:",
+	"filter.to":     "Bis:",
+	"filter.search": "Beschreibungen durchsuchen",
+	"filter.submit": "Filtern",
+	"filter.reset":  "Zurücksetzen",
+	// Member status
+	"status.member":     "Nicht stimmberechtigtes Mitglied",
+	"status.voting":     "Stimmberechtigtes Mitglied",
+	"status.nonevoting": "Dauerhaft nicht stimmberechtigtes Mitglied",
+	"status.nomember":   "Kein Mitglied",
+	"status.observer":   "Beobachter",
+	// Roles
+	"role.chair":     "Vorsitz",
+	"role.member":    "Mitglied",
+	"role.secretary": "Sekretariat",
+	"role.staff":     "Mitarbeiter",
+	// Errors
+	"error.label":                    "Fehler:",
+	"error.missing_user_name":        "Benutzername fehlt",
+	"error.missing_password":         "Passwort fehlt",
+	"error.login_failed":             "Anmeldung fehlgeschlagen",
+	"error.account_locked":           "Das Konto ist nach zu vielen fehlgeschlagenen Anmeldungen vorübergehend gesperrt.",
+	"error.invalid_timezone":         "Ungültige Zeitzone.",
+	"error.invalid_email":            "Ungültige E-Mail-Adresse.",
+	"error.invalid_entries":          "%d der ausgewählten Einträge sind ungültig. Es wurde nichts geändert.",
+	"error.meetings_concluded":       "%d der %d ausgewählten Sitzungen sind abgeschlossen und können nicht entfernt werden.",
+	"error.meetings_not_deleted":     "%d von %d Sitzungen gelöscht; abgeschlossene Sitzungen können nicht entfernt werden.",
+	"error.invalid_view":             "Unbekannte Startseite.",
+	"error.modified_concurrently":    "Dieser Eintrag wurde zwischenzeitlich von jemand anderem geändert; der aktuelle Stand wurde neu geladen.",
+	"error.start_stop_invalid":       "Start- und Endzeit sind ungültig.",
+	"error.start_invalid":            "Die Startzeit ist ungültig.",
+	"error.stop_invalid":             "Die Endzeit ist ungültig.",
+	"error.start_duration_invalid":   "Startzeit und Dauer sind ungültig.",
+	"error.duration_invalid":         "Die Dauer ist ungültig.",
+	"error.duration_not_positive":    "Die Dauer muss größer als null sein.",
+	"error.duration_too_long":        "Die Dauer darf %s nicht überschreiten.",
+	"error.absent_collides":          "Der Zeitraum überschneidet sich mit einer anderen entschuldigten Abwesenheit in diesem Gremium.",
+	"error.absent_too_long":          "Die maximale Abwesenheit beträgt %s.",
+	"error.absent_only_self":         "Entschuldigte Abwesenheiten können nur für sich selbst beantragt werden.",
+	"error.meeting_collides":         "Der Zeitraum überschneidet sich mit einer anderen Sitzung in diesem Gremium.",
+	"error.meeting_concluded":        "Abgeschlossene Sitzungen können nicht geändert werden.",
+	"error.meeting_move_collides":    "Die Sitzung überschneidet sich mit einer anderen Sitzung des Zielgremiums.",
+	"error.committee_archived":       "Das Gremium ist archiviert und nimmt keine neuen Sitzungen an.",
+	"error.description_too_long":     "Die Beschreibung darf höchstens %d Zeichen lang sein.",
+	"error.agenda_too_long":          "Die Tagesordnung darf höchstens %d Zeichen lang sein.",
+	"error.no_voters_run":            "Die Sitzung kann nicht gestartet werden: Das Gremium hat keine stimmberechtigten Mitglieder.",
+	"error.no_voters_quorum":         "Das Gremium hat keine stimmberechtigten Mitglieder. Das Quorum kann nicht erreicht werden.",
+	"error.already_running":          "In diesem Gremium läuft bereits eine Sitzung.",
+	"error.attendance_closed":        "Die Anwesenheit kann nur während der laufenden Sitzung oder kurz nach ihrem Abschluss geändert werden.",
+	"error.newer_concluded":          "Es gibt bereits eine neuere abgeschlossene Sitzung.",
+	"error.only_running_conclude":    "Nur laufende Sitzungen können abgeschlossen werden.",
+	"error.no_status_change":         "Es gibt keine Statusänderung von %q zum Zurücknehmen.",
+	"error.members_not_removable":    "Mitglieder können nicht durch eine Statusänderung entfernt werden.",
+	"error.no_members_selected":      "Keine Mitglieder ausgewählt.",
+	"error.only_members_status":      "Nur Mitglieder des Gremiums können ihren Status ändern.",
+	"error.document_too_large":       "Das Dokument ist zu groß.",
+	"error.no_document":              "Kein Dokument ausgewählt.",
+	"error.upload_failed":            "Das Hochladen des Dokuments ist fehlgeschlagen.",
+	"error.content_type_not_allowed": "Der Inhaltstyp %q ist nicht erlaubt.",
+	"error.import_invalid":           "Das Gremium konnte nicht importiert werden: %s",
+	"error.committee_name_missing":   "Der Name des Gremiums fehlt.",
+	"error.name_missing":             "Der Name fehlt.",
+	"error.committee_exists":         "Das Gremium %q existiert bereits.",
+	"error.slug_missing":             "Das Kürzel fehlt.",
+	"error.slug_invalid":             "Das Kürzel darf nur Kleinbuchstaben, Ziffern und einzelne Bindestriche enthalten.",
+	"error.slug_taken":               "Das Kürzel %q wird bereits von einem anderen Gremium verwendet.",
+	"error.password_mismatch":        "Passwort und Bestätigung stimmen nicht überein.",
+	"error.password_too_short":       "Das Passwort ist zu kurz (mindestens 8 Zeichen).",
+	"error.last_admin":               "Der letzte Administrator kann nicht entfernt werden.",
+	"error.merge_protected":          "Der Benutzer %q kann nicht entfernt werden.",
+	"error.merge_self":               "Ein Benutzer kann nicht mit sich selbst zusammengeführt werden.",
+	"error.unknown_user":             "Unbekannter Benutzer %q.",
+	"error.last_chair":               "Der letzte Vorsitz eines Gremiums kann nicht entfernt werden. Übertragen Sie zuerst den Vorsitz.",
+	"error.not_chair":                "%q ist kein Vorsitz des Gremiums.",
+	"error.already_chair":            "%q hat bereits den Vorsitz des Gremiums.",
+	"error.not_active_member":        "%q ist kein aktiver Benutzer des Gremiums.",
+	"error.login_name_missing":       "Der Anmeldename fehlt.",
+	"error.user_exists":              "Ein Benutzer mit dem Namen %q existiert bereits (Groß- und Kleinschreibung wird nicht unterschieden).",
+}
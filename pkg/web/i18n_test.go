@@ -0,0 +1,146 @@
+// This file is Free Software under the Apache-2.0 License
+// without warranty, see README.md and LICENSE for details.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileCopyrightText: 2025 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2025 Intevation GmbH <https://intevation.de>
+
+package web
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"maps"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/csaf-auxiliary/oasis-quorum-calculator/pkg/models"
+)
+
+func TestLabels(t *testing.T) {
+	for locale := range catalogs {
+		funcs := localeFuncs(locale)
+		statusLabel := funcs["StatusLabel"].(func(models.MemberStatus) string)
+		roleLabel := funcs["RoleLabel"].(func(models.Role) string)
+
+		seen := map[string]bool{}
+		for status := models.Member; status <= models.Observer; status++ {
+			label := statusLabel(status)
+			if strings.HasPrefix(label, "status.") || strings.Contains(label, "unknown") {
+				t.Errorf("%s: status %d has no label: %q", locale, int(status), label)
+			}
+			if seen[label] {
+				t.Errorf("%s: label %q is used twice", locale, label)
+			}
+			seen[label] = true
+			// The label is found by the string which parses back.
+			if parsed, err := models.ParseMemberStatus(status.String()); err != nil || parsed != status {
+				t.Errorf("%s: status %d does not round-trip: %v", locale, int(status), err)
+			}
+		}
+
+		clear(seen)
+		for role := models.ChairRole; role <= models.StaffRole; role++ {
+			label := roleLabel(role)
+			if strings.HasPrefix(label, "role.") || strings.Contains(label, "unknown") {
+				t.Errorf("%s: role %d has no label: %q", locale, int(role), label)
+			}
+			if seen[label] {
+				t.Errorf("%s: label %q is used twice", locale, label)
+			}
+			seen[label] = true
+			if parsed, err := models.ParseRole(role.String()); err != nil || parsed != role {
+				t.Errorf("%s: role %d does not round-trip: %v", locale, int(role), err)
+			}
+		}
+	}
+}
+
+// errorKey matches the message keys of errors.
+var errorKey = regexp.MustCompile(`^error\.[a-z_]+$`)
+
+// templateKey matches the message keys used in the templates.
+var templateKey = regexp.MustCompile(`\bT "([a-z_.]+)"`)
+
+// usedKeys returns the error keys of the handlers
+// and the message keys of the templates.
+func usedKeys(t *testing.T) []string {
+	t.Helper()
+	keys := map[string]bool{}
+	fset := token.NewFileSet()
+	sources, err := filepath.Glob("*.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, source := range sources {
+		if strings.HasSuffix(source, "_test.go") {
+			continue
+		}
+		f, err := parser.ParseFile(fset, source, nil, parser.SkipObjectResolution)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ast.Inspect(f, func(n ast.Node) bool {
+			if lit, ok := n.(*ast.BasicLit); ok && lit.Kind == token.STRING {
+				if s, err := strconv.Unquote(lit.Value); err == nil && errorKey.MatchString(s) {
+					keys[s] = true
+				}
+			}
+			return true
+		})
+	}
+	templates, err := filepath.Glob(filepath.Join("..", "..", "web", "templates", "*.tmpl"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tmpl := range templates {
+		data, err := os.ReadFile(tmpl)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, m := range templateKey.FindAllStringSubmatch(string(data), -1) {
+			keys[m[1]] = true
+		}
+	}
+	return slices.Sorted(maps.Keys(keys))
+}
+
+func TestCatalogsComplete(t *testing.T) {
+	keys := usedKeys(t)
+	if len(keys) == 0 {
+		t.Fatal("no message keys found")
+	}
+	for locale, catalog := range catalogs {
+		for _, key := range keys {
+			if _, ok := catalog[key]; !ok {
+				t.Errorf("%s: missing message %q", locale, key)
+			}
+		}
+	}
+}
+
+func TestTranslateFallback(t *testing.T) {
+	catalogs["xx"] = catalog{"greeting": "Moin %s"}
+	defer delete(catalogs, "xx")
+	for _, tc := range []struct {
+		locale, key string
+		args        []any
+		want        string
+	}{
+		{"xx", "greeting", []any{"Hamburg"}, "Moin Hamburg"},
+		{"xx", "error.unknown_user", []any{"bob"}, `Unknown user "bob".`},
+		{"xx", "no.such.key", nil, "no.such.key"},
+		{"unknown", "error.unknown_user", []any{"bob"}, `Unknown user "bob".`},
+	} {
+		if got := translate(tc.locale, tc.key, tc.args...); got != tc.want {
+			t.Errorf("%s %s: got %q, want %q", tc.locale, tc.key, got, tc.want)
+		}
+	}
+}
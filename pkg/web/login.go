@@ -9,8 +9,8 @@
 package web
 
 import (
+	"errors"
 	"net/http"
-	"net/url"
 
 	"github.com/csaf-auxiliary/oasis-quorum-calculator/pkg/auth"
 	"github.com/csaf-auxiliary/oasis-quorum-calculator/pkg/models"
@@ -21,11 +21,11 @@ func (c *Controller) authFailed(w http.ResponseWriter, r *http.Request, nickname
 		"nickname": nickname,
 		"error":    msg,
 	}
-	check(w, r, c.tmpls.ExecuteTemplate(w, "auth.tmpl", data))
+	check(w, r, c.templates(r).ExecuteTemplate(w, "auth.tmpl", data))
 }
 
 func (c *Controller) auth(w http.ResponseWriter, r *http.Request) {
-	check(w, r, c.tmpls.ExecuteTemplate(w, "auth.tmpl", nil))
+	check(w, r, c.templates(r).ExecuteTemplate(w, "auth.tmpl", nil))
 }
 
 func (c *Controller) login(w http.ResponseWriter, r *http.Request) {
@@ -36,23 +36,27 @@ func (c *Controller) login(w http.ResponseWriter, r *http.Request) {
 	}
 	nickname := r.FormValue("nickname")
 	if nickname == "" {
-		c.authFailed(w, r, "", "Missing user name")
+		c.authFailed(w, r, "", "error.missing_user_name")
 		return
 	}
 	password := r.FormValue("password")
 	if password == "" {
-		c.authFailed(w, r, nickname, "Missing password")
+		c.authFailed(w, r, nickname, "error.missing_password")
 		return
 	}
 	session, err := auth.NewSession(
 		r.Context(),
 		c.cfg, c.db,
 		nickname, password)
+	if errors.Is(err, auth.ErrAccountLocked) {
+		c.authFailed(w, r, nickname, "error.account_locked")
+		return
+	}
 	if !check(w, r, err) {
 		return
 	}
 	if session == nil {
-		c.authFailed(w, r, nickname, "Login failed")
+		c.authFailed(w, r, nickname, "error.login_failed")
 		return
 	}
 	_, err = models.LoadUser(r.Context(), c.db, nickname, nil)
@@ -60,7 +64,8 @@ func (c *Controller) login(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	http.Redirect(w, r, "/?SESSIONID="+url.QueryEscape(session.ID()), http.StatusFound)
+	session.SetCookie(w, c.cfg)
+	http.Redirect(w, r, session.URL("/"), http.StatusFound)
 }
 
 func (c *Controller) logout(_ http.ResponseWriter, r *http.Request) {
@@ -0,0 +1,38 @@
+// This file is Free Software under the Apache-2.0 License
+// without warranty, see README.md and LICENSE for details.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileCopyrightText: 2025 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2025 Intevation GmbH <https://intevation.de>
+
+package web
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestLoginCookie(t *testing.T) {
+	for _, cookie := range []bool{false, true} {
+		srv := newTestServer(t)
+		srv.cfg.Sessions.Cookie = cookie
+		resp := srv.post(t, "/login", url.Values{
+			"nickname": {"admin"},
+			"password": {testPassword},
+		})
+		if resp.StatusCode != http.StatusFound {
+			t.Fatalf("cookie %t: login failed: %s", cookie, resp.Status)
+		}
+		inURL := strings.Contains(resp.Header.Get("Location"), "SESSIONID=")
+		var inCookie bool
+		for _, c := range resp.Cookies() {
+			inCookie = inCookie || (c.Name == "SESSIONID" && c.Value != "")
+		}
+		if inURL == cookie || inCookie != cookie {
+			t.Errorf("cookie %t: session in URL %t, in cookie %t", cookie, inURL, inCookie)
+		}
+	}
+}
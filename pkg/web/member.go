@@ -11,7 +11,9 @@ package web
 import (
 	"fmt"
 	"net/http"
+	"slices"
 	"strconv"
+	"time"
 
 	"github.com/csaf-auxiliary/oasis-quorum-calculator/pkg/auth"
 	"github.com/csaf-auxiliary/oasis-quorum-calculator/pkg/misc"
@@ -19,25 +21,31 @@ import (
 )
 
 func (c *Controller) member(w http.ResponseWriter, r *http.Request) {
+	c.memberError(w, r, "")
+}
+
+func (c *Controller) memberError(
+	w http.ResponseWriter,
+	r *http.Request,
+	errKey string,
+	errArgs ...any,
+) {
 	ctx := r.Context()
 	user := auth.UserFromContext(ctx)
-	meetings, err := models.LoadMeetings(
-		ctx, c.db,
-		misc.Map(user.Committees(), (*models.Committee).GetID))
-	if !check(w, r, err) {
-		return
-	}
-	attended, err := models.AttendedMeetings(ctx, c.db, user.Nickname)
+	dashboard, err := models.LoadMemberDashboard(ctx, c.db, user.Nickname)
 	if !check(w, r, err) {
 		return
 	}
 	data := templateData{
-		"Session":  auth.SessionFromContext(ctx),
-		"User":     user,
-		"Meetings": meetings,
-		"Attended": attended,
+		"Session":   auth.SessionFromContext(ctx),
+		"User":      user,
+		"Dashboard": dashboard,
+		"Grace":     c.cfg.Meetings.AttendanceGracePeriod,
+	}
+	if errKey != "" {
+		data.error(errKey, errArgs...)
 	}
-	check(w, r, c.tmpls.ExecuteTemplate(w, "member.tmpl", data))
+	check(w, r, c.templates(r).ExecuteTemplate(w, "member.tmpl", data))
 }
 
 func (c *Controller) memberAttend(w http.ResponseWriter, r *http.Request) {
@@ -54,25 +62,143 @@ func (c *Controller) memberAttend(w http.ResponseWriter, r *http.Request) {
 	if !check(w, r, err) {
 		return
 	}
-	if meeting == nil || meeting.Status != models.MeetingRunning {
+	if meeting == nil {
 		c.member(w, r)
 		return
 	}
+	if !meeting.AttendanceChangeable(time.Now(), c.cfg.Meetings.AttendanceGracePeriod) {
+		c.memberError(w, r, "error.attendance_closed")
+		return
+	}
 	user := auth.UserFromContext(ctx)
+	// Late corrections after the conclusion refresh the frozen quorum
+	// and the status change caused by the conclusion.
+	if meeting.Status == models.MeetingConcluded {
+		if !check(w, r, models.CorrectAttendee(
+			ctx, c.db, meetingID, committeeID, user.Nickname, attend)) {
+			return
+		}
+		c.memberAttendRedirect(w, r, meetingID, committeeID)
+		return
+	}
 	ms := user.FindMembershipCriterion(models.MembershipByID(committeeID))
 	voting := ms.Status == models.Voting
+	var (
+		members []*models.User
+		before  *models.Quorum
+	)
+	if c.quorumWebhookEnabled() {
+		if members, err = models.LoadCommitteeUsers(ctx, c.db, committeeID, &meeting.StartTime); !check(w, r, err) {
+			return
+		}
+		if before, err = c.loadQuorum(ctx, meeting, members); !check(w, r, err) {
+			return
+		}
+	}
 	if !check(w, r, models.UpdateAttendee(ctx, c.db, meetingID, user.Nickname, attend, voting)) {
 		return
 	}
+	if before != nil && !check(w, r, c.notifyQuorumReached(ctx, meeting, members, before)) {
+		return
+	}
+	if !check(w, r, c.publishMeeting(ctx, meeting, members)) {
+		return
+	}
+	c.memberAttendRedirect(w, r, meetingID, committeeID)
+}
+
+func (c *Controller) memberAttendRedirect(
+	w http.ResponseWriter,
+	r *http.Request,
+	meetingID, committeeID int64,
+) {
 	// new parameter where to redirect
 	redirect := r.FormValue("redirect")
 
 	switch redirect {
 	case "meeting_status":
-		sessionID := r.FormValue("SESSIONID")
-		target := fmt.Sprintf("/meeting_status?SESSIONID=%s&meeting=%d&committee=%d", sessionID, meetingID, committeeID)
-		http.Redirect(w, r, target, http.StatusSeeOther)
+		target := fmt.Sprintf("/meeting_status?meeting=%d&committee=%d", meetingID, committeeID)
+		http.Redirect(w, r, auth.SessionFromContext(r.Context()).URL(target), http.StatusSeeOther)
 	default:
 		c.member(w, r)
 	}
 }
+
+func (c *Controller) absenceRequest(w http.ResponseWriter, r *http.Request) {
+	var (
+		committeeID, err = misc.Atoi64(r.FormValue("committee"))
+		ctx              = r.Context()
+	)
+	if !checkParam(w, err) {
+		return
+	}
+	user := auth.UserFromContext(ctx)
+	committee, err := models.LoadCommittee(ctx, c.db, committeeID)
+	if !check(w, r, err) {
+		return
+	}
+	memberAbsent, err := models.LoadAbsent(ctx, c.db, committeeID)
+	if !check(w, r, err) {
+		return
+	}
+	data := templateData{
+		"Session":      auth.SessionFromContext(ctx),
+		"User":         user,
+		"Committee":    committee,
+		"MemberAbsent": slices.Collect(memberAbsent.Filter(models.MemberAbsentNicknameFilter(user.Nickname))),
+	}
+	check(w, r, c.templates(r).ExecuteTemplate(w, "absence_request.tmpl", data))
+}
+
+func (c *Controller) absenceRequestStore(w http.ResponseWriter, r *http.Request) {
+	committeeID, err := misc.Atoi64(r.FormValue("committee"))
+	if !checkParam(w, err) {
+		return
+	}
+	var (
+		nickname  = r.FormValue("nickname")
+		startTime = r.FormValue("start_time")
+		stopTime  = r.FormValue("stop_time")
+		timezone  = r.FormValue("timezone")
+		ctx       = r.Context()
+		user      = auth.UserFromContext(ctx)
+	)
+	committee, err := models.LoadCommittee(ctx, c.db, committeeID)
+	if !check(w, r, err) {
+		return
+	}
+	memberAbsent, err := models.LoadAbsent(ctx, c.db, committeeID)
+	if !check(w, r, err) {
+		return
+	}
+	data := templateData{
+		"Session":      auth.SessionFromContext(ctx),
+		"User":         user,
+		"Committee":    committee,
+		"MemberAbsent": slices.Collect(memberAbsent.Filter(models.MemberAbsentNicknameFilter(user.Nickname))),
+	}
+	// Members are only allowed to request absents for themselves.
+	if nickname != "" && nickname != user.Nickname {
+		data.error("error.absent_only_self")
+		check(w, r, c.templates(r).ExecuteTemplate(w, "absence_request.tmpl", data))
+		return
+	}
+	start, stop := parseAbsentRange(data, startTime, stopTime, timezone)
+	m := models.MemberAbsent{
+		Name:      user.Nickname,
+		StartTime: start,
+		StopTime:  stop,
+		Status:    models.AbsentRequested,
+	}
+	if !data.hasError() {
+		c.checkNewAbsent(data, memberAbsent, &m)
+	}
+	if data.hasError() {
+		check(w, r, c.templates(r).ExecuteTemplate(w, "absence_request.tmpl", data))
+		return
+	}
+	if !check(w, r, m.StoreNew(ctx, c.db, committeeID)) {
+		return
+	}
+	c.absenceRequest(w, r)
+}
@@ -9,31 +9,69 @@
 package web
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"iter"
 	"maps"
 	"net/http"
+	"net/url"
 	"regexp"
 	"slices"
 	"strings"
+	"time"
 	"unicode/utf8"
 
 	"github.com/csaf-auxiliary/oasis-quorum-calculator/pkg/auth"
+	"github.com/csaf-auxiliary/oasis-quorum-calculator/pkg/database"
 	"github.com/csaf-auxiliary/oasis-quorum-calculator/pkg/misc"
 	"github.com/csaf-auxiliary/oasis-quorum-calculator/pkg/models"
 )
 
+const (
+	defaultUsersPerPage = 50
+	maxUsersPerPage     = 500
+)
+
 func (c *Controller) users(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
-	users, err := models.LoadAllUsers(ctx, c.db)
+	c.usersError(w, r, "")
+}
+
+func (c *Controller) usersError(
+	w http.ResponseWriter,
+	r *http.Request,
+	errKey string,
+	errArgs ...any,
+) {
+	var (
+		ctx                = r.Context()
+		query              = strings.TrimSpace(r.FormValue("q"))
+		includeDeactivated = r.FormValue("include_deactivated") != ""
+		users              []*models.User
+		err                error
+	)
+	data := templateData{
+		"Query":              query,
+		"IncludeDeactivated": includeDeactivated,
+		"Session":            auth.SessionFromContext(ctx),
+		"User":               auth.UserFromContext(ctx),
+	}
+	if query != "" {
+		users, err = models.SearchUsers(ctx, c.db, query)
+	} else {
+		page := parsePagination(r, defaultUsersPerPage, maxUsersPerPage)
+		users, page.Total, err = models.LoadUsersPage(
+			ctx, c.db, includeDeactivated, page.Offset(), page.PerPage)
+		data["Pagination"] = page
+	}
 	if !check(w, r, err) {
 		return
 	}
-	data := templateData{
-		"Users":   users,
-		"Session": auth.SessionFromContext(ctx),
-		"User":    auth.UserFromContext(ctx),
+	data["Users"] = users
+	if errKey != "" {
+		data.error(errKey, errArgs...)
 	}
-	check(w, r, c.tmpls.ExecuteTemplate(w, "users.tmpl", data))
+	check(w, r, c.templates(r).ExecuteTemplate(w, "users.tmpl", data))
 }
 
 func (c *Controller) user(w http.ResponseWriter, r *http.Request) {
@@ -42,7 +80,81 @@ func (c *Controller) user(w http.ResponseWriter, r *http.Request) {
 		"Session": auth.SessionFromContext(ctx),
 		"User":    auth.UserFromContext(ctx),
 	}
-	check(w, r, c.tmpls.ExecuteTemplate(w, "user.tmpl", data))
+	check(w, r, c.templates(r).ExecuteTemplate(w, "user.tmpl", data))
+}
+
+// sessionsNickname returns the nickname of the user whose sessions
+// are managed. Admins can manage the sessions of all users.
+func sessionsNickname(r *http.Request) string {
+	user := auth.UserFromContext(r.Context())
+	if nickname := r.FormValue("nickname"); nickname != "" && user.IsAdmin {
+		return nickname
+	}
+	return user.Nickname
+}
+
+// currentSessionPrefix returns the prefix of the token of the current session
+// if it belongs to the user with the given nickname.
+func currentSessionPrefix(r *http.Request, nickname string) string {
+	session := auth.SessionFromContext(r.Context())
+	if session.Nickname() != nickname {
+		return ""
+	}
+	token := session.Token()
+	return token[:min(len(token), models.SessionPrefixLength)]
+}
+
+func (c *Controller) sessions(w http.ResponseWriter, r *http.Request) {
+	var (
+		ctx      = r.Context()
+		nickname = sessionsNickname(r)
+	)
+	sessions, err := models.LoadSessions(ctx, c.db, nickname)
+	if !check(w, r, err) {
+		return
+	}
+	// Expired sessions may not be removed by the cleaner, yet.
+	expired := time.Now().Add(-c.cfg.Sessions.MaxAge)
+	sessions = slices.DeleteFunc(sessions, func(s *models.Session) bool {
+		return s.LastAccess.Before(expired)
+	})
+	data := templateData{
+		"Session":  auth.SessionFromContext(ctx),
+		"User":     auth.UserFromContext(ctx),
+		"Nickname": nickname,
+		"Sessions": sessions,
+		"Current":  currentSessionPrefix(r, nickname),
+	}
+	check(w, r, c.templates(r).ExecuteTemplate(w, "sessions.tmpl", data))
+}
+
+func (c *Controller) sessionsStore(w http.ResponseWriter, r *http.Request) {
+	var (
+		ctx      = r.Context()
+		nickname = sessionsNickname(r)
+		current  = currentSessionPrefix(r, nickname)
+		revoke   = r.FormValue("revoke")
+	)
+	switch {
+	case r.FormValue("revoke_all") != "":
+		if !check(w, r, models.DeleteSessions(ctx, c.db, nickname)) {
+			return
+		}
+		if current != "" {
+			// Logged out ourselves.
+			auth.SessionFromContext(ctx).Delete()
+			return
+		}
+	case revoke != "":
+		if !check(w, r, models.DeleteSession(ctx, c.db, nickname, revoke)) {
+			return
+		}
+		if revoke == current {
+			auth.SessionFromContext(ctx).Delete()
+			return
+		}
+	}
+	c.sessions(w, r)
 }
 
 func (c *Controller) userStore(w http.ResponseWriter, r *http.Request) {
@@ -51,6 +163,9 @@ func (c *Controller) userStore(w http.ResponseWriter, r *http.Request) {
 		lastname        = strings.TrimSpace(r.FormValue("lastname"))
 		password        = strings.TrimSpace(r.FormValue("password"))
 		passwordConfirm = strings.TrimSpace(r.FormValue("password2"))
+		timezone        = strings.TrimSpace(r.FormValue("timezone"))
+		email           = strings.TrimSpace(r.FormValue("email"))
+		defaultView     = r.FormValue("default_view")
 		changed         = false
 		ctx             = r.Context()
 		user            = auth.UserFromContext(ctx)
@@ -62,33 +177,174 @@ func (c *Controller) userStore(w http.ResponseWriter, r *http.Request) {
 		"Session": auth.SessionFromContext(ctx),
 		"User":    user,
 	}
+	if _, err := time.LoadLocation(timezone); err != nil {
+		data.error("error.invalid_timezone")
+	} else {
+		misc.NilChanger(&changed, &user.Timezone, timezone)
+	}
+	if email != "" && !misc.ValidEmail(email) {
+		data.error("error.invalid_email")
+	} else {
+		misc.NilChanger(&changed, &user.Email, email)
+	}
+	switch defaultView {
+	case "", "chair", "member":
+		misc.NilChanger(&changed, &user.DefaultView, defaultView)
+	default:
+		data.error("error.invalid_view")
+	}
 	switch {
 	case password != "" && password != passwordConfirm:
-		data.error("Password and confirmation do not match.")
+		data.error("error.password_mismatch")
 	case password != "" && utf8.RuneCountInString(password) < 8:
-		data.error("Password too short (need at least 8 characters)")
+		data.error("error.password_too_short")
 	case password != "":
 		misc.NilChanger(&changed, &user.Password, password)
+		user.MustChangePassword = false
 	}
 	if changed && !check(w, r, user.Store(ctx, c.db)) {
 		return
 	}
-	check(w, r, c.tmpls.ExecuteTemplate(w, "user.tmpl", data))
+	check(w, r, c.templates(r).ExecuteTemplate(w, "user.tmpl", data))
 }
 
 func (c *Controller) usersStore(w http.ResponseWriter, r *http.Request) {
-	if r.FormValue("delete") != "" {
-		me := auth.SessionFromContext(r.Context()).Nickname()
+	var (
+		ctx    = r.Context()
+		action func(context.Context, *database.Database, iter.Seq[string]) error
+		d      *deletion
+	)
+	switch {
+	case r.FormValue("delete") != "":
+		// Deleting only deactivates to keep the history of the users.
+		action = models.DeactivateUsersByNickname
+		d = &deletion{Submit: "delete", Verb: "deactivated"}
+	case r.FormValue("reactivate") != "":
+		action = models.ReactivateUsersByNickname
+	case r.FormValue("purge") != "":
+		action = models.DeleteUsersByNickname
+		d = &deletion{Submit: "purge", Verb: "permanently deleted"}
+	}
+	if action != nil {
+		me := auth.SessionFromContext(ctx).Nickname()
 		filter := misc.Filter(slices.Values(r.Form["users"]), func(nickname string) bool {
 			return nickname != "admin" && nickname != me
 		})
-		if !check(w, r, models.DeleteUsersByNickname(r.Context(), c.db, filter)) {
+		if d != nil {
+			selected := slices.Collect(filter)
+			users, err := models.LoadAllUsers(ctx, c.db)
+			if !check(w, r, err) {
+				return
+			}
+			d.Action = "/users_store"
+			d.Back = "/users?SESSIONID=" + url.QueryEscape(auth.SessionFromContext(ctx).ID())
+			d.Field = "users"
+			for _, user := range users {
+				if slices.Contains(selected, user.Nickname) {
+					d.Entries = append(d.Entries, &deletionEntry{
+						Value: user.Nickname,
+						Label: user.Nickname,
+					})
+				}
+			}
+			switch {
+			case len(d.Entries) == 0:
+				c.users(w, r)
+				return
+			case !c.confirmDeletion(w, r, d):
+				return
+			}
+			filter = slices.Values(d.Values())
+		}
+		switch err := action(ctx, c.db, filter); {
+		case errors.Is(err, models.ErrLastAdmin):
+			c.usersError(w, r, "error.last_admin")
+			return
+		case !check(w, r, err):
 			return
 		}
 	}
 	c.users(w, r)
 }
 
+func (c *Controller) usersMerge(w http.ResponseWriter, r *http.Request) {
+	c.usersMergeRender(w, r, nil, nil, false, "")
+}
+
+func (c *Controller) usersMergeRender(
+	w http.ResponseWriter,
+	r *http.Request,
+	keep, remove *models.User,
+	confirm bool,
+	errKey string,
+	errArgs ...any,
+) {
+	ctx := r.Context()
+	users, err := models.LoadAllUsers(ctx, c.db)
+	if !check(w, r, err) {
+		return
+	}
+	data := templateData{
+		"Session": auth.SessionFromContext(ctx),
+		"User":    auth.UserFromContext(ctx),
+		"Users":   users,
+		"Keep":    keep,
+		"Remove":  remove,
+		"Confirm": confirm,
+	}
+	if errKey != "" {
+		data.error(errKey, errArgs...)
+	}
+	check(w, r, c.templates(r).ExecuteTemplate(w, "users_merge.tmpl", data))
+}
+
+func (c *Controller) usersMergeStore(w http.ResponseWriter, r *http.Request) {
+	var (
+		ctx     = r.Context()
+		keep    = strings.TrimSpace(r.FormValue("keep"))
+		remove  = strings.TrimSpace(r.FormValue("remove"))
+		confirm = r.FormValue("confirm") == "true"
+		me      = auth.SessionFromContext(ctx).Nickname()
+	)
+	keepUser, err := models.LoadUser(ctx, c.db, keep, nil)
+	if !check(w, r, err) {
+		return
+	}
+	removeUser, err := models.LoadUser(ctx, c.db, remove, nil)
+	if !check(w, r, err) {
+		return
+	}
+	switch {
+	case keepUser == nil:
+		c.usersMergeRender(w, r, nil, removeUser, false, "error.unknown_user", keep)
+		return
+	case removeUser == nil:
+		c.usersMergeRender(w, r, keepUser, nil, false, "error.unknown_user", remove)
+		return
+	case keep == remove:
+		c.usersMergeRender(w, r, keepUser, removeUser, false, "error.merge_self")
+		return
+	case remove == "admin" || remove == me:
+		c.usersMergeRender(w, r, keepUser, removeUser, false, "error.merge_protected", remove)
+		return
+	case !confirm:
+		// Let the admin confirm the merge first.
+		c.usersMergeRender(w, r, keepUser, removeUser, true, "")
+		return
+	}
+	switch err := models.MergeUsers(ctx, c.db, keep, remove); {
+	case errors.Is(err, models.ErrLastAdmin):
+		c.usersMergeRender(w, r, keepUser, removeUser, false, "error.last_admin")
+		return
+	case errors.Is(err, models.ErrUnknownUser):
+		c.usersMergeRender(w, r, nil, nil, false, "error.unknown_user", remove)
+		return
+	case !check(w, r, err):
+		return
+	}
+	c.users(w, r)
+}
+
 func (c *Controller) userCreate(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	data := templateData{
@@ -96,7 +352,7 @@ func (c *Controller) userCreate(w http.ResponseWriter, r *http.Request) {
 		"User":    auth.UserFromContext(ctx),
 		"NewUser": &models.User{},
 	}
-	check(w, r, c.tmpls.ExecuteTemplate(w, "user_create.tmpl", data))
+	check(w, r, c.templates(r).ExecuteTemplate(w, "user_create.tmpl", data))
 }
 
 func (c *Controller) userCreateStore(w http.ResponseWriter, r *http.Request) {
@@ -104,6 +360,7 @@ func (c *Controller) userCreateStore(w http.ResponseWriter, r *http.Request) {
 		Nickname:  strings.TrimSpace(r.FormValue("nickname")),
 		Firstname: misc.NilString(strings.TrimSpace(r.FormValue("firstname"))),
 		Lastname:  misc.NilString(strings.TrimSpace(r.FormValue("lastname"))),
+		Email:     misc.NilString(strings.TrimSpace(r.FormValue("email"))),
 		IsAdmin:   r.FormValue("admin") == "admin",
 	}
 	ctx := r.Context()
@@ -117,25 +374,37 @@ func (c *Controller) userCreateStore(w http.ResponseWriter, r *http.Request) {
 		"NewUser":    &nuser,
 		"Committees": committees,
 	}
-	if nuser.Nickname == "" {
-		data.error("Login name is missing.")
-	} else {
+	switch {
+	case nuser.Nickname == "":
+		data.error("error.login_name_missing")
+	case nuser.Email != nil && !misc.ValidEmail(*nuser.Email):
+		data.error("error.invalid_email")
+	default:
 		password := misc.RandomString(12)
 		switch success, err := nuser.StoreNew(ctx, c.db, password); {
 		case !check(w, r, err):
 			return
 		case !success:
-			data.error(fmt.Sprintf("User %q already exists.", nuser.Nickname))
+			data.error("error.user_exists", nuser.Nickname)
 		default:
 			data["Password"] = password
-			check(w, r, c.tmpls.ExecuteTemplate(w, "user_created.tmpl", data))
+			check(w, r, c.templates(r).ExecuteTemplate(w, "user_created.tmpl", data))
 			return
 		}
 	}
-	check(w, r, c.tmpls.ExecuteTemplate(w, "user_create.tmpl", data))
+	check(w, r, c.templates(r).ExecuteTemplate(w, "user_create.tmpl", data))
 }
 
 func (c *Controller) userEdit(w http.ResponseWriter, r *http.Request) {
+	c.userEditError(w, r, "")
+}
+
+func (c *Controller) userEditError(
+	w http.ResponseWriter,
+	r *http.Request,
+	errKey string,
+	errArgs ...any,
+) {
 	nickname := r.FormValue("nickname")
 	ctx := r.Context()
 	user, err := models.LoadUser(ctx, c.db, nickname, nil)
@@ -151,7 +420,7 @@ func (c *Controller) userEdit(w http.ResponseWriter, r *http.Request) {
 	if !session.IsAdmin {
 		staffFilter = session.Nickname
 	}
-	committees, err := models.LoadCommitteesFiltered(ctx, c.db, staffFilter)
+	committees, err := models.LoadCommitteesFiltered(ctx, c.db, staffFilter, true)
 	if !check(w, r, err) {
 		return
 	}
@@ -160,8 +429,12 @@ func (c *Controller) userEdit(w http.ResponseWriter, r *http.Request) {
 		"User":       auth.UserFromContext(ctx),
 		"NewUser":    user,
 		"Committees": committees,
+		"Locked":     user.IsLocked(time.Now()),
+	}
+	if errKey != "" {
+		data.error(errKey, errArgs...)
 	}
-	check(w, r, c.tmpls.ExecuteTemplate(w, "user_edit.tmpl", data))
+	check(w, r, c.templates(r).ExecuteTemplate(w, "user_edit.tmpl", data))
 }
 
 func (c *Controller) userEditStore(w http.ResponseWriter, r *http.Request) {
@@ -180,13 +453,14 @@ func (c *Controller) userEditStore(w http.ResponseWriter, r *http.Request) {
 		lastname        = strings.TrimSpace(r.FormValue("lastname"))
 		password        = strings.TrimSpace(r.FormValue("password"))
 		passwordConfirm = strings.TrimSpace(r.FormValue("password2"))
+		email           = strings.TrimSpace(r.FormValue("email"))
 		changed         = false
 	)
 
 	misc.NilChanger(&changed, &user.Firstname, firstname)
 	misc.NilChanger(&changed, &user.Lastname, lastname)
 
-	committees, err := models.LoadCommittees(ctx, c.db)
+	committees, err := models.LoadCommitteesFiltered(ctx, c.db, "", true)
 	if !check(w, r, err) {
 		return
 	}
@@ -197,18 +471,66 @@ func (c *Controller) userEditStore(w http.ResponseWriter, r *http.Request) {
 		"NewUser":    user,
 		"Committees": committees,
 	}
+	if email != "" && !misc.ValidEmail(email) {
+		data.error("error.invalid_email")
+	} else {
+		misc.NilChanger(&changed, &user.Email, email)
+	}
 	switch {
 	case password != "" && password != passwordConfirm:
-		data.error("Password and confirmation do not match.")
+		data.error("error.password_mismatch")
 	case password != "" && utf8.RuneCountInString(password) < 8:
-		data.error("Password too short (need at least 8 characters)")
+		data.error("error.password_too_short")
 	case password != "":
 		misc.NilChanger(&changed, &user.Password, password)
 	}
 	if changed && !check(w, r, user.Store(ctx, c.db)) {
 		return
 	}
-	check(w, r, c.tmpls.ExecuteTemplate(w, "user_edit.tmpl", data))
+	check(w, r, c.templates(r).ExecuteTemplate(w, "user_edit.tmpl", data))
+}
+
+func (c *Controller) userResetPassword(w http.ResponseWriter, r *http.Request) {
+	nickname := r.FormValue("nickname")
+	ctx := r.Context()
+	user, err := models.LoadUser(ctx, c.db, nickname, nil)
+	if !check(w, r, err) {
+		return
+	}
+	if user == nil {
+		c.users(w, r)
+		return
+	}
+	password := misc.RandomString(12)
+	user.Password = &password
+	user.MustChangePassword = true
+	if !check(w, r, user.Store(ctx, c.db)) {
+		return
+	}
+	data := templateData{
+		"Session":  auth.SessionFromContext(ctx),
+		"User":     auth.UserFromContext(ctx),
+		"NewUser":  user,
+		"Password": password,
+	}
+	check(w, r, c.templates(r).ExecuteTemplate(w, "user_password_reset.tmpl", data))
+}
+
+func (c *Controller) userUnlock(w http.ResponseWriter, r *http.Request) {
+	nickname := r.FormValue("nickname")
+	ctx := r.Context()
+	user, err := models.LoadUser(ctx, c.db, nickname, nil)
+	if !check(w, r, err) {
+		return
+	}
+	if user == nil {
+		c.users(w, r)
+		return
+	}
+	if !check(w, r, models.UnlockUser(ctx, c.db, user.Nickname)) {
+		return
+	}
+	c.userEdit(w, r)
 }
 
 var roleCommitteeRe = regexp.MustCompile(`(member|chair|secretary|staff)(\d+)`)
@@ -223,7 +545,7 @@ func (c *Controller) userCommitteesStore(w http.ResponseWriter, r *http.Request)
 	if !session.IsAdmin {
 		staffFilter = session.Nickname
 	}
-	committees, err := models.LoadCommitteesFiltered(ctx, c.db, staffFilter)
+	committees, err := models.LoadCommitteesFiltered(ctx, c.db, staffFilter, true)
 	if !check(w, r, err) {
 		return
 	}
@@ -269,15 +591,19 @@ func (c *Controller) userCommitteesStore(w http.ResponseWriter, r *http.Request)
 	}
 
 	nickname := r.FormValue("nickname")
-	if !check(w, r, models.UpdateMemberships(
-		ctx, c.db, nickname, maps.Values(memberships))) {
+	switch err := models.UpdateMemberships(
+		ctx, c.db, nickname, maps.Values(memberships)); {
+	case errors.Is(err, models.ErrLastChair):
+		c.userEditError(w, r, "error.last_chair")
+		return
+	case !check(w, r, err):
 		return
 	}
 	user, err := models.LoadUser(ctx, c.db, nickname, nil)
 	if !check(w, r, err) {
 		return
 	}
-	committees, err = models.LoadCommitteesFiltered(ctx, c.db, staffFilter)
+	committees, err = models.LoadCommitteesFiltered(ctx, c.db, staffFilter, true)
 	if !check(w, r, err) {
 		return
 	}
@@ -287,5 +613,5 @@ func (c *Controller) userCommitteesStore(w http.ResponseWriter, r *http.Request)
 		"NewUser":    user,
 		"Committees": committees,
 	}
-	check(w, r, c.tmpls.ExecuteTemplate(w, "user_edit.tmpl", data))
+	check(w, r, c.templates(r).ExecuteTemplate(w, "user_edit.tmpl", data))
 }
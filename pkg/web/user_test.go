@@ -0,0 +1,65 @@
+// This file is Free Software under the Apache-2.0 License
+// without warranty, see README.md and LICENSE for details.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileCopyrightText: 2025 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2025 Intevation GmbH <https://intevation.de>
+
+package web
+
+import (
+	"database/sql"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestUserStoreEmail(t *testing.T) {
+	srv := newTestServer(t)
+	sessionID := srv.login(t, "admin", testPassword)
+
+	store := func(email string) string {
+		t.Helper()
+		resp := srv.post(t, "/user_store", url.Values{
+			"SESSIONID": {sessionID},
+			"email":     {email},
+		})
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("got %s", resp.Status)
+		}
+		return body(t, resp)
+	}
+	email := func() string {
+		t.Helper()
+		var email sql.NullString
+		if err := srv.db.DB.QueryRow(
+			`SELECT email FROM users WHERE nickname = 'admin'`,
+		).Scan(&email); err != nil {
+			t.Fatal(err)
+		}
+		return email.String
+	}
+
+	const invalid = "Invalid email address."
+	if page := store("admin@example.org"); strings.Contains(page, invalid) {
+		t.Errorf("valid email rejected")
+	}
+	if got := email(); got != "admin@example.org" {
+		t.Errorf("got email %q, want %q", got, "admin@example.org")
+	}
+	for _, bad := range []string{"admin", "Admin <admin@example.org>", "admin@localhost"} {
+		if page := store(bad); !strings.Contains(page, invalid) {
+			t.Errorf("%q: invalid email not reported", bad)
+		}
+		if got := email(); got != "admin@example.org" {
+			t.Errorf("%q: email changed to %q", bad, got)
+		}
+	}
+	// Clearing the email falls back to the nickname again.
+	store("")
+	if got := email(); got != "" {
+		t.Errorf("email not cleared: %q", got)
+	}
+}
@@ -0,0 +1,227 @@
+// This file is Free Software under the Apache-2.0 License
+// without warranty, see README.md and LICENSE for details.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileCopyrightText: 2025 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2025 Intevation GmbH <https://intevation.de>
+
+package web
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/csaf-auxiliary/oasis-quorum-calculator/pkg/models"
+)
+
+// quorumWatcher remembers if the quorum of running meetings
+// was reached to detect the transitions.
+type quorumWatcher struct {
+	mu      sync.Mutex
+	reached map[int64]bool
+}
+
+// transition records the new quorum state of a meeting and
+// returns true if the quorum has been reached by this change.
+// before is used if the state of the meeting is not known, yet.
+func (qw *quorumWatcher) transition(meetingID int64, before, after bool) bool {
+	qw.mu.Lock()
+	defer qw.mu.Unlock()
+	if prev, ok := qw.reached[meetingID]; ok {
+		before = prev
+	}
+	qw.reached[meetingID] = after
+	return !before && after
+}
+
+// forget removes the quorum state of a meeting which is not running anymore.
+func (qw *quorumWatcher) forget(meetingID int64) {
+	qw.mu.Lock()
+	defer qw.mu.Unlock()
+	delete(qw.reached, meetingID)
+}
+
+// quorumReachedEvent is the payload sent to the webhook
+// if the quorum of a running meeting is reached.
+type quorumReachedEvent struct {
+	Event     string            `json:"event"`
+	Time      time.Time         `json:"time"`
+	Committee quorumCommittee   `json:"committee"`
+	Meeting   quorumMeeting     `json:"meeting"`
+	Quorum    quorumEventCounts `json:"quorum"`
+}
+
+type quorumCommittee struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+}
+
+type quorumMeeting struct {
+	ID        int64     `json:"id"`
+	StartTime time.Time `json:"start_time"`
+	StopTime  time.Time `json:"stop_time"`
+}
+
+type quorumEventCounts struct {
+	Number          int `json:"number"`
+	Total           int `json:"total"`
+	Voting          int `json:"voting"`
+	AttendingVoting int `json:"attending_voting"`
+	Attending       int `json:"attending"`
+}
+
+// newQuorumEventCounts extracts the counts of a quorum.
+func newQuorumEventCounts(q *models.Quorum) quorumEventCounts {
+	return quorumEventCounts{
+		Number:          q.Number(),
+		Total:           q.Total,
+		Voting:          q.Voting,
+		AttendingVoting: q.AttendingVoting,
+		Attending:       q.Attending,
+	}
+}
+
+// calculateQuorum calculates the quorum of a meeting of a committee
+// from its expected members and the attendees.
+func calculateQuorum(
+	members []*models.User,
+	committeeID int64,
+	attendees models.Attendees,
+	expected models.Expected,
+) *models.Quorum {
+	var quorum models.Quorum
+	// With an explicit list only the expected attendees
+	// count so that there are never more attending than expected.
+	for nickname := range attendees {
+		if expected.Includes(nickname) {
+			quorum.Attending++
+		}
+	}
+	crit := models.MembershipByID(committeeID)
+	for _, member := range members {
+		if !expected.Includes(member.Nickname) {
+			continue
+		}
+		quorum.Total++
+		if ms := member.FindMembershipCriterion(crit); ms != nil &&
+			ms.HasRole(models.MemberRole) {
+			switch ms.Status {
+			case models.Voting:
+				quorum.Voting++
+				if attendees.Votes(member.Nickname) {
+					quorum.AttendingVoting++
+				}
+			case models.NoneVoting:
+				quorum.NonVoting++
+			case models.Member:
+				quorum.Member++
+			case models.Observer:
+				quorum.Observer++
+			}
+		}
+	}
+	return &quorum
+}
+
+// loadQuorum loads the attendees and the expected members
+// of a meeting and calculates its quorum.
+func (c *Controller) loadQuorum(
+	ctx context.Context,
+	meeting *models.Meeting,
+	members []*models.User,
+) (*models.Quorum, error) {
+	attendees, err := meeting.Attendees(ctx, c.db)
+	if err != nil {
+		return nil, err
+	}
+	expected, err := models.LoadMeetingExpected(ctx, c.db, meeting.ID)
+	if err != nil {
+		return nil, err
+	}
+	return calculateQuorum(members, meeting.CommitteeID, attendees, expected), nil
+}
+
+// quorumWebhookEnabled returns true if a webhook is configured
+// to be notified if the quorum is reached.
+func (c *Controller) quorumWebhookEnabled() bool {
+	return c.cfg.Webhooks.QuorumReachedURL != ""
+}
+
+// notifyQuorumReached checks if the quorum of a running meeting has been
+// reached by an attendance change and notifies the configured webhook.
+// The webhook is called in the background.
+func (c *Controller) notifyQuorumReached(
+	ctx context.Context,
+	meeting *models.Meeting,
+	members []*models.User,
+	before *models.Quorum,
+) error {
+	if meeting.Gathering {
+		return nil
+	}
+	after, err := c.loadQuorum(ctx, meeting, members)
+	if err != nil {
+		return err
+	}
+	if !c.quorum.transition(meeting.ID, before.Reached(), after.Reached()) {
+		return nil
+	}
+	committee, err := models.LoadCommittee(ctx, c.db, meeting.CommitteeID)
+	if err != nil {
+		return err
+	}
+	if committee == nil {
+		return nil
+	}
+	event := quorumReachedEvent{
+		Event: "quorum_reached",
+		Time:  time.Now().UTC(),
+		Committee: quorumCommittee{
+			ID:   committee.ID,
+			Name: committee.Name,
+		},
+		Meeting: quorumMeeting{
+			ID:        meeting.ID,
+			StartTime: meeting.StartTime.UTC(),
+			StopTime:  meeting.StopTime.UTC(),
+		},
+		Quorum: newQuorumEventCounts(after),
+	}
+	go c.postWebhook(c.cfg.Webhooks.QuorumReachedURL, &event)
+	return nil
+}
+
+// postWebhook posts the JSON encoded payload to the given URL.
+func (c *Controller) postWebhook(url string, payload any) {
+	if err := func() error {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return err
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), c.cfg.Webhooks.Timeout)
+		defer cancel()
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(data))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			return fmt.Errorf("unexpected status code %d (%s)", resp.StatusCode, resp.Status)
+		}
+		return nil
+	}(); err != nil {
+		slog.Error("calling webhook failed", "url", url, "error", err)
+	}
+}
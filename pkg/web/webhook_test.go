@@ -0,0 +1,85 @@
+// This file is Free Software under the Apache-2.0 License
+// without warranty, see README.md and LICENSE for details.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// SPDX-FileCopyrightText: 2025 German Federal Office for Information Security (BSI) <https://www.bsi.bund.de>
+// Software-Engineering: 2025 Intevation GmbH <https://intevation.de>
+
+package web
+
+import (
+	"testing"
+
+	"github.com/csaf-auxiliary/oasis-quorum-calculator/pkg/models"
+)
+
+func TestQuorumWatcher(t *testing.T) {
+	qw := quorumWatcher{reached: map[int64]bool{}}
+	if !qw.transition(1, false, true) {
+		t.Error("reaching the quorum is not a transition")
+	}
+	// The recorded state wins over the passed one.
+	if qw.transition(1, false, true) {
+		t.Error("staying above the quorum is a transition")
+	}
+	qw.forget(1)
+	if len(qw.reached) != 0 {
+		t.Errorf("%d meetings remembered after forget", len(qw.reached))
+	}
+	if !qw.transition(1, false, true) {
+		t.Error("forgotten meeting does not use the passed state")
+	}
+}
+
+// quorumMember creates a member of committee 1 with the given status.
+func quorumMember(nickname string, status models.MemberStatus) *models.User {
+	return &models.User{
+		Nickname: nickname,
+		Memberships: []*models.Membership{{
+			Committee: &models.Committee{ID: 1},
+			Status:    status,
+			Roles:     []models.Role{models.MemberRole},
+		}},
+	}
+}
+
+func TestCalculateQuorum(t *testing.T) {
+	members := []*models.User{
+		quorumMember("alice", models.Voting),
+		quorumMember("bob", models.Voting),
+		quorumMember("carol", models.Member),
+		quorumMember("dave", models.Voting),
+	}
+	attendees := models.Attendees{
+		"alice": {Presence: models.PresentVoting, VotingAllowed: true},
+		"bob":   {Presence: models.PresentNonVoting, VotingAllowed: true},
+		"carol": {Presence: models.PresentVoting},
+		"dave":  {Presence: models.PresentVoting, VotingAllowed: true},
+	}
+	for _, tc := range []struct {
+		name     string
+		expected models.Expected
+		want     models.Quorum
+	}{
+		{"all expected", nil, models.Quorum{
+			Total: 4, Voting: 3, AttendingVoting: 2, Attending: 4, Member: 1,
+		}},
+		{"explicit list", models.Expected{"alice": true, "carol": true}, models.Quorum{
+			Total: 2, Voting: 1, AttendingVoting: 1, Attending: 2, Member: 1,
+		}},
+		{"expected not attending", models.Expected{"bob": true, "eve": true}, models.Quorum{
+			Total: 1, Voting: 1, AttendingVoting: 0, Attending: 1,
+		}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got := calculateQuorum(members, 1, attendees, tc.expected)
+			if *got != tc.want {
+				t.Errorf("got %+v, want %+v", *got, tc.want)
+			}
+			if got.Attending > got.Total {
+				t.Errorf("%d attending but only %d expected", got.Attending, got.Total)
+			}
+		})
+	}
+}